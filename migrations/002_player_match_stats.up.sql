@@ -0,0 +1,58 @@
+CREATE TABLE player_match_stats (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    match_id UUID NOT NULL REFERENCES match(id) ON DELETE CASCADE,
+    player_id UUID NOT NULL REFERENCES player(id) ON DELETE CASCADE,
+    minutes_played INT DEFAULT -1,
+    points INT DEFAULT -1,
+    tries INT DEFAULT -1,
+    conversions INT DEFAULT -1,
+    conversion_attempts INT DEFAULT -1,
+    penalty_goals INT DEFAULT -1,
+    goal_conversion_rate FLOAT DEFAULT -1,
+    field_goals_1 INT DEFAULT -1,
+    field_goals_2 INT DEFAULT -1,
+    runs INT DEFAULT -1,
+    run_meters INT DEFAULT -1,
+    kick_return_meters INT DEFAULT -1,
+    post_contact_meters INT DEFAULT -1,
+    line_breaks INT DEFAULT -1,
+    line_break_assists INT DEFAULT -1,
+    try_assists INT DEFAULT -1,
+    line_engaged_runs INT DEFAULT -1,
+    tackle_breaks INT DEFAULT -1,
+    hit_ups INT DEFAULT -1,
+    avg_play_the_ball_speed FLOAT DEFAULT -1,
+    dummy_half_runs INT DEFAULT -1,
+    dummy_half_run_meters INT DEFAULT -1,
+    one_on_one_steals INT DEFAULT -1,
+    offloads INT DEFAULT -1,
+    dummy_passes INT DEFAULT -1,
+    passes INT DEFAULT -1,
+    receipts INT DEFAULT -1,
+    passes_to_run_ratio FLOAT DEFAULT -1,
+    tackle_efficiency FLOAT DEFAULT -1,
+    tackles_made INT DEFAULT -1,
+    missed_tackles INT DEFAULT -1,
+    ineffective_tackles INT DEFAULT -1,
+    intercepts INT DEFAULT -1,
+    kicks_defused INT DEFAULT -1,
+    kicks INT DEFAULT -1,
+    kick_meters INT DEFAULT -1,
+    forced_drop_outs INT DEFAULT -1,
+    bomb_kicks INT DEFAULT -1,
+    grubbers INT DEFAULT -1,
+    forty_twenty INT DEFAULT -1,
+    twenty_forty INT DEFAULT -1,
+    cross_field_kicks INT DEFAULT -1,
+    kicked_dead INT DEFAULT -1,
+    errors INT DEFAULT -1,
+    handling_errors INT DEFAULT -1,
+    one_on_one_lost INT DEFAULT -1,
+    penalties INT DEFAULT -1,
+    ruck_infringements INT DEFAULT -1,
+    inside_10_metres INT DEFAULT -1,
+    on_report INT DEFAULT -1,
+    sin_bins INT DEFAULT -1,
+    send_offs INT DEFAULT -1,
+    UNIQUE(match_id, player_id)
+);
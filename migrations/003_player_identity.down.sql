@@ -0,0 +1,27 @@
+DROP INDEX IF EXISTS player_name_idx;
+
+ALTER TABLE player
+    ADD COLUMN match_id UUID REFERENCES match(id),
+    ADD COLUMN position VARCHAR(50) NOT NULL DEFAULT '',
+    ADD COLUMN number INT NOT NULL DEFAULT 0;
+
+-- merged players can only be attached back to one of their appearances
+UPDATE player p
+SET match_id = mp.match_id,
+    position = mp.position,
+    number = mp.number
+FROM match_player mp
+WHERE mp.player_id = p.id;
+
+ALTER TABLE player
+    DROP COLUMN nrl_id,
+    DROP COLUMN profile_url;
+
+ALTER TABLE match_player
+    DROP CONSTRAINT match_player_match_id_player_id_key,
+    DROP COLUMN position,
+    DROP COLUMN number;
+
+-- players are keyed by their match again
+ALTER TABLE player
+    ADD CONSTRAINT player_match_id_name_first_name_last_key UNIQUE (match_id, name_first, name_last);
@@ -0,0 +1,32 @@
+-- appearances now carry the per-match details
+ALTER TABLE match_player
+    ADD COLUMN position VARCHAR(50) NOT NULL DEFAULT '',
+    ADD COLUMN number INT NOT NULL DEFAULT 0;
+
+UPDATE match_player mp
+SET position = p.position,
+    number = p.number
+FROM player p
+WHERE mp.player_id = p.id;
+
+-- repeated scrapes inserted the same appearance more than once
+DELETE FROM match_player a
+USING match_player b
+WHERE a.match_id = b.match_id
+  AND a.player_id = b.player_id
+  AND a.id > b.id;
+
+ALTER TABLE match_player
+    ADD CONSTRAINT match_player_match_id_player_id_key UNIQUE (match_id, player_id);
+
+-- player becomes a match independent entity, duplicates are merged by the
+-- scraper's reconciliation pass
+ALTER TABLE player DROP CONSTRAINT player_match_id_name_first_name_last_key;
+ALTER TABLE player
+    DROP COLUMN match_id,
+    DROP COLUMN position,
+    DROP COLUMN number,
+    ADD COLUMN nrl_id VARCHAR(255) UNIQUE,
+    ADD COLUMN profile_url VARCHAR(255) NOT NULL DEFAULT '';
+
+CREATE INDEX player_name_idx ON player (lower(name_first), lower(name_last));
@@ -0,0 +1,14 @@
+CREATE TABLE scrape_error (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    competition_id INT NOT NULL REFERENCES competition(id) ON DELETE CASCADE,
+    stage VARCHAR(50) NOT NULL,
+    url VARCHAR(512) NOT NULL DEFAULT '',
+    season VARCHAR(10) NOT NULL DEFAULT '',
+    round_index INT NOT NULL DEFAULT 0,
+    match_id UUID REFERENCES match(id) ON DELETE CASCADE,
+    message TEXT NOT NULL,
+    occurred_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX scrape_error_match_id_idx ON scrape_error (match_id);
+CREATE INDEX scrape_error_occurred_at_idx ON scrape_error (occurred_at);
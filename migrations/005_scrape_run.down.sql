@@ -0,0 +1,3 @@
+ALTER TABLE scrape_error DROP COLUMN IF EXISTS run_id;
+DROP TABLE IF EXISTS scrape_task;
+DROP TABLE IF EXISTS scrape_run;
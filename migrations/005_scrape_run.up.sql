@@ -0,0 +1,35 @@
+CREATE TABLE scrape_run (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    competition_id INT NOT NULL REFERENCES competition(id) ON DELETE CASCADE,
+    seasons VARCHAR(255) NOT NULL,
+    rounds VARCHAR(255) NOT NULL,
+    resumed_from UUID REFERENCES scrape_run(id) ON DELETE SET NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'running',
+    started_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    finished_at TIMESTAMPTZ
+);
+
+CREATE INDEX scrape_run_competition_id_idx ON scrape_run (competition_id, started_at);
+
+-- one row per stage of a task, tasks are keyed by season, round and match
+CREATE TABLE scrape_task (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    run_id UUID NOT NULL REFERENCES scrape_run(id) ON DELETE CASCADE,
+    task_key VARCHAR(255) NOT NULL,
+    kind VARCHAR(20) NOT NULL,
+    season VARCHAR(10) NOT NULL,
+    round_index INT NOT NULL DEFAULT 0,
+    round_id UUID REFERENCES round(id) ON DELETE CASCADE,
+    match_id UUID REFERENCES match(id) ON DELETE CASCADE,
+    url VARCHAR(512) NOT NULL DEFAULT '',
+    stage VARCHAR(50) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    message TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    UNIQUE(run_id, task_key, stage)
+);
+
+CREATE INDEX scrape_task_unfinished_idx ON scrape_task (run_id) WHERE status <> 'done';
+
+ALTER TABLE scrape_error ADD COLUMN run_id UUID REFERENCES scrape_run(id) ON DELETE CASCADE;
@@ -0,0 +1,25 @@
+ALTER TABLE round
+    ADD COLUMN start_day VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN end_day VARCHAR(255) NOT NULL DEFAULT '';
+
+UPDATE round r
+SET start_day = COALESCE(to_char(d.first_kickoff AT TIME ZONE 'Australia/Sydney', 'FMDay FMDD FMMonth'), ''),
+    end_day = COALESCE(to_char(d.last_kickoff AT TIME ZONE 'Australia/Sydney', 'FMDay FMDD FMMonth'), '')
+FROM (
+    SELECT round_id, MIN(kickoff_at) AS first_kickoff, MAX(kickoff_at) AS last_kickoff
+    FROM match
+    GROUP BY round_id
+) d
+WHERE d.round_id = r.id;
+
+ALTER TABLE match
+    ADD COLUMN kickoff_time VARCHAR(20) NOT NULL DEFAULT '',
+    ADD COLUMN date_played VARCHAR(20) NOT NULL DEFAULT '';
+
+UPDATE match
+SET kickoff_time = to_char(kickoff_at AT TIME ZONE 'Australia/Sydney', 'FMHH12:MI AM'),
+    date_played = to_char(kickoff_at AT TIME ZONE 'Australia/Sydney', 'FMDay FMDD FMMonth')
+WHERE kickoff_at IS NOT NULL;
+
+DROP INDEX IF EXISTS match_kickoff_at_idx;
+ALTER TABLE match DROP COLUMN kickoff_at;
@@ -0,0 +1,26 @@
+ALTER TABLE match ADD COLUMN kickoff_at TIMESTAMPTZ;
+
+-- date_played only ever held the header text, e.g. "Saturday 2 March", so
+-- the year comes from the season and the kickoff is taken as midnight Sydney
+-- time until the match is scraped again
+UPDATE match m
+SET kickoff_at = to_date(
+        regexp_replace(m.date_played, '^[A-Za-z]+,?\s+', '') || ' ' || s.year,
+        'FMDD FMMonth YYYY'
+    )::timestamp AT TIME ZONE 'Australia/Sydney'
+FROM round r
+JOIN season s ON s.id = r.season_id
+WHERE m.round_id = r.id
+  AND s.year ~ '^\d{4}$'
+  AND m.date_played ~* '^[a-z]+,?\s+\d{1,2}\s+(january|february|march|april|may|june|july|august|september|october|november|december)$';
+
+ALTER TABLE match
+    DROP COLUMN kickoff_time,
+    DROP COLUMN date_played;
+
+CREATE INDEX match_kickoff_at_idx ON match (kickoff_at);
+
+-- round start and end are derived from the kickoffs of their matches
+ALTER TABLE round
+    DROP COLUMN start_day,
+    DROP COLUMN end_day;
@@ -0,0 +1,12 @@
+DROP INDEX IF EXISTS match_home_team_id_idx;
+DROP INDEX IF EXISTS match_away_team_id_idx;
+
+ALTER TABLE match DROP CONSTRAINT match_round_id_home_team_id_away_team_id_key;
+ALTER TABLE match ADD CONSTRAINT match_round_id_home_team_away_team_key UNIQUE (round_id, home_team, away_team);
+
+ALTER TABLE match
+    DROP COLUMN home_team_id,
+    DROP COLUMN away_team_id;
+
+DROP TABLE IF EXISTS team_alias;
+DROP TABLE IF EXISTS team;
@@ -0,0 +1,271 @@
+-- canonical clubs, a club keeps its id through renames and relocations
+CREATE TABLE team (
+    id VARCHAR(50) PRIMARY KEY,
+    "name" VARCHAR(100) NOT NULL,
+    nickname VARCHAR(100) NOT NULL,
+    first_season INT NOT NULL,
+    last_season INT
+);
+
+-- names nrl.com has used for a team, stored lower case without punctuation.
+-- A name reused by different clubs is bounded by the seasons it applies to.
+CREATE TABLE team_alias (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    alias VARCHAR(100) NOT NULL,
+    team_id VARCHAR(50) NOT NULL REFERENCES team(id) ON DELETE CASCADE,
+    first_season INT,
+    last_season INT,
+
+    -- an unbounded alias is still only listed once
+    UNIQUE NULLS NOT DISTINCT (alias, first_season)
+);
+
+CREATE INDEX team_alias_alias_idx ON team_alias (alias);
+
+INSERT INTO team (id, "name", nickname, first_season, last_season) VALUES
+    ('broncos', 'Brisbane Broncos', 'Broncos', 1988, NULL),
+    ('raiders', 'Canberra Raiders', 'Raiders', 1982, NULL),
+    ('bulldogs', 'Canterbury-Bankstown Bulldogs', 'Bulldogs', 1935, NULL),
+    ('sharks', 'Cronulla-Sutherland Sharks', 'Sharks', 1967, NULL),
+    ('dolphins', 'Dolphins', 'Dolphins', 2023, NULL),
+    ('titans', 'Gold Coast Titans', 'Titans', 2007, NULL),
+    ('sea-eagles', 'Manly-Warringah Sea Eagles', 'Sea Eagles', 1947, NULL),
+    ('storm', 'Melbourne Storm', 'Storm', 1998, NULL),
+    ('knights', 'Newcastle Knights', 'Knights', 1988, NULL),
+    ('cowboys', 'North Queensland Cowboys', 'Cowboys', 1995, NULL),
+    ('eels', 'Parramatta Eels', 'Eels', 1947, NULL),
+    ('panthers', 'Penrith Panthers', 'Panthers', 1967, NULL),
+    ('rabbitohs', 'South Sydney Rabbitohs', 'Rabbitohs', 1908, NULL),
+    ('dragons', 'St George Illawarra Dragons', 'Dragons', 1999, NULL),
+    ('roosters', 'Sydney Roosters', 'Roosters', 1908, NULL),
+    ('warriors', 'New Zealand Warriors', 'Warriors', 1995, NULL),
+    ('wests-tigers', 'Wests Tigers', 'Wests Tigers', 2000, NULL),
+    ('st-george', 'St George Dragons', 'Dragons', 1921, 1998),
+    ('illawarra', 'Illawarra Steelers', 'Steelers', 1982, 1998),
+    ('balmain', 'Balmain Tigers', 'Tigers', 1908, 1999),
+    ('western-suburbs', 'Western Suburbs Magpies', 'Magpies', 1908, 1999),
+    ('north-sydney', 'North Sydney Bears', 'Bears', 1908, 1999),
+    ('northern-eagles', 'Northern Eagles', 'Northern Eagles', 2000, 2002),
+    ('adelaide', 'Adelaide Rams', 'Rams', 1997, 1998),
+    ('hunter', 'Hunter Mariners', 'Mariners', 1997, 1997),
+    ('gold-coast', 'Gold Coast Chargers', 'Chargers', 1988, 1998),
+    ('south-queensland', 'South Queensland Crushers', 'Crushers', 1995, 1997),
+    ('perth', 'Western Reds', 'Reds', 1995, 1997),
+    ('newtown', 'Newtown Jets', 'Jets', 1908, 1983);
+
+INSERT INTO team_alias (alias, team_id, first_season, last_season) VALUES
+    ('adelaide', 'adelaide', NULL, NULL),
+    ('adelaide rams', 'adelaide', NULL, NULL),
+    ('rams', 'adelaide', NULL, NULL),
+    ('balmain tigers', 'balmain', NULL, NULL),
+    ('tigers', 'balmain', NULL, 1999),
+    ('brisbane', 'broncos', NULL, NULL),
+    ('brisbane broncos', 'broncos', NULL, NULL),
+    ('broncos', 'broncos', NULL, NULL),
+    ('bankstown', 'bulldogs', NULL, NULL),
+    ('bulldogs', 'bulldogs', NULL, NULL),
+    ('canterbury', 'bulldogs', NULL, NULL),
+    ('canterbury bulldogs', 'bulldogs', NULL, NULL),
+    ('canterbury-bankstown', 'bulldogs', NULL, NULL),
+    ('canterbury-bankstown bulldogs', 'bulldogs', NULL, NULL),
+    ('cowboys', 'cowboys', NULL, NULL),
+    ('north queensland', 'cowboys', NULL, NULL),
+    ('north queensland cowboys', 'cowboys', NULL, NULL),
+    ('nth qld cowboys', 'cowboys', NULL, NULL),
+    ('dolphins', 'dolphins', NULL, NULL),
+    ('redcliffe dolphins', 'dolphins', NULL, NULL),
+    ('the dolphins', 'dolphins', NULL, NULL),
+    ('dragons', 'dragons', 1999, NULL),
+    ('st george illawarra', 'dragons', NULL, NULL),
+    ('st george illawarra dragons', 'dragons', NULL, NULL),
+    ('eels', 'eels', NULL, NULL),
+    ('parramatta', 'eels', NULL, NULL),
+    ('parramatta eels', 'eels', NULL, NULL),
+    ('chargers', 'gold-coast', NULL, NULL),
+    ('gold coast', 'gold-coast', NULL, 1998),
+    ('gold coast chargers', 'gold-coast', NULL, NULL),
+    ('gold coast giants', 'gold-coast', NULL, NULL),
+    ('gold coast gladiators', 'gold-coast', NULL, NULL),
+    ('gold coast seagulls', 'gold-coast', NULL, NULL),
+    ('gold coast-tweed giants', 'gold-coast', NULL, NULL),
+    ('hunter', 'hunter', NULL, NULL),
+    ('hunter mariners', 'hunter', NULL, NULL),
+    ('mariners', 'hunter', NULL, NULL),
+    ('illawarra', 'illawarra', NULL, NULL),
+    ('illawarra steelers', 'illawarra', NULL, NULL),
+    ('steelers', 'illawarra', NULL, NULL),
+    ('knights', 'knights', NULL, NULL),
+    ('newcastle', 'knights', NULL, NULL),
+    ('newcastle knights', 'knights', NULL, NULL),
+    ('jets', 'newtown', NULL, NULL),
+    ('newtown', 'newtown', NULL, NULL),
+    ('newtown jets', 'newtown', NULL, NULL),
+    ('bears', 'north-sydney', NULL, NULL),
+    ('north sydney', 'north-sydney', NULL, NULL),
+    ('north sydney bears', 'north-sydney', NULL, NULL),
+    ('northern eagles', 'northern-eagles', NULL, NULL),
+    ('panthers', 'panthers', NULL, NULL),
+    ('penrith', 'panthers', NULL, NULL),
+    ('penrith panthers', 'panthers', NULL, NULL),
+    ('perth', 'perth', NULL, NULL),
+    ('perth reds', 'perth', NULL, NULL),
+    ('reds', 'perth', NULL, NULL),
+    ('western reds', 'perth', NULL, NULL),
+    ('rabbitohs', 'rabbitohs', NULL, NULL),
+    ('south sydney', 'rabbitohs', NULL, NULL),
+    ('south sydney rabbitohs', 'rabbitohs', NULL, NULL),
+    ('souths', 'rabbitohs', NULL, NULL),
+    ('canberra', 'raiders', NULL, NULL),
+    ('canberra raiders', 'raiders', NULL, NULL),
+    ('raiders', 'raiders', NULL, NULL),
+    ('eastern suburbs', 'roosters', NULL, NULL),
+    ('eastern suburbs roosters', 'roosters', NULL, NULL),
+    ('roosters', 'roosters', NULL, NULL),
+    ('sydney', 'roosters', NULL, NULL),
+    ('sydney city roosters', 'roosters', NULL, NULL),
+    ('sydney roosters', 'roosters', NULL, NULL),
+    ('manly', 'sea-eagles', NULL, NULL),
+    ('manly sea eagles', 'sea-eagles', NULL, NULL),
+    ('manly-warringah', 'sea-eagles', NULL, NULL),
+    ('manly-warringah sea eagles', 'sea-eagles', NULL, NULL),
+    ('sea eagles', 'sea-eagles', NULL, NULL),
+    ('cronulla', 'sharks', NULL, NULL),
+    ('cronulla sharks', 'sharks', NULL, NULL),
+    ('cronulla-sutherland sharks', 'sharks', NULL, NULL),
+    ('sharks', 'sharks', NULL, NULL),
+    ('crushers', 'south-queensland', NULL, NULL),
+    ('south queensland', 'south-queensland', NULL, NULL),
+    ('south queensland crushers', 'south-queensland', NULL, NULL),
+    ('dragons', 'st-george', NULL, 1998),
+    ('st george', 'st-george', NULL, 1998),
+    ('st george dragons', 'st-george', NULL, NULL),
+    ('melbourne', 'storm', NULL, NULL),
+    ('melbourne storm', 'storm', NULL, NULL),
+    ('storm', 'storm', NULL, NULL),
+    ('gold coast', 'titans', 2007, NULL),
+    ('gold coast titans', 'titans', NULL, NULL),
+    ('titans', 'titans', NULL, NULL),
+    ('auckland warriors', 'warriors', NULL, NULL),
+    ('new zealand', 'warriors', NULL, NULL),
+    ('new zealand warriors', 'warriors', NULL, NULL),
+    ('nz warriors', 'warriors', NULL, NULL),
+    ('one new zealand warriors', 'warriors', NULL, NULL),
+    ('warriors', 'warriors', NULL, NULL),
+    ('magpies', 'western-suburbs', NULL, NULL),
+    ('western suburbs', 'western-suburbs', NULL, NULL),
+    ('western suburbs magpies', 'western-suburbs', NULL, NULL),
+    ('wests magpies', 'western-suburbs', NULL, NULL),
+    ('tigers', 'wests-tigers', 2000, NULL),
+    ('wests tigers', 'wests-tigers', NULL, NULL);
+
+ALTER TABLE match
+    ADD COLUMN home_team_id VARCHAR(50) REFERENCES team(id),
+    ADD COLUMN away_team_id VARCHAR(50) REFERENCES team(id);
+
+-- resolve the names already scraped, matches whose names are unknown keep a
+-- NULL id until they are scraped again
+UPDATE match m
+SET home_team_id = (
+        SELECT a.team_id FROM team_alias a
+        WHERE a.alias = lower(regexp_replace(regexp_replace(trim(m.home_team), '[.''’]', '', 'g'), '\s+', ' ', 'g'))
+          AND (a.first_season IS NULL OR a.first_season <= s.year::int)
+          AND (a.last_season IS NULL OR a.last_season >= s.year::int)
+        ORDER BY a.first_season DESC NULLS LAST
+        LIMIT 1
+    ),
+    away_team_id = (
+        SELECT a.team_id FROM team_alias a
+        WHERE a.alias = lower(regexp_replace(regexp_replace(trim(m.away_team), '[.''’]', '', 'g'), '\s+', ' ', 'g'))
+          AND (a.first_season IS NULL OR a.first_season <= s.year::int)
+          AND (a.last_season IS NULL OR a.last_season >= s.year::int)
+        ORDER BY a.first_season DESC NULLS LAST
+        LIMIT 1
+    )
+FROM round r
+JOIN season s ON s.id = r.season_id
+WHERE m.round_id = r.id
+  AND s.year ~ '^\d{4}$';
+
+-- different spellings of the same fixture are now the same match. The first
+-- row is kept and everything scraped under the others is moved onto it,
+-- except where the kept row (or an earlier duplicate) already has it.
+CREATE TEMP TABLE match_merge AS
+SELECT id AS dup, keep
+FROM (
+    SELECT id, first_value(id) OVER (PARTITION BY round_id, home_team_id, away_team_id ORDER BY id) AS keep
+    FROM match
+    WHERE home_team_id IS NOT NULL AND away_team_id IS NOT NULL
+) m
+WHERE id <> keep;
+
+CREATE TEMP TABLE match_earlier AS
+SELECT mm.dup, g.id AS earlier
+FROM match_merge mm
+JOIN (
+    SELECT keep AS id, keep FROM match_merge
+    UNION
+    SELECT dup, keep FROM match_merge
+) g ON g.keep = mm.keep AND g.id < mm.dup;
+
+DELETE FROM match_player c USING match_earlier e
+WHERE c.match_id = e.dup
+  AND EXISTS (SELECT 1 FROM match_player o WHERE o.match_id = e.earlier AND o.player_id = c.player_id);
+DELETE FROM player_match_stats c USING match_earlier e
+WHERE c.match_id = e.dup
+  AND EXISTS (SELECT 1 FROM player_match_stats o WHERE o.match_id = e.earlier AND o.player_id = c.player_id);
+DELETE FROM play_by_play c USING match_earlier e
+WHERE c.match_id = e.dup
+  AND EXISTS (SELECT 1 FROM play_by_play o WHERE o.match_id = e.earlier AND o.play_index = c.play_index);
+DELETE FROM match_official c USING match_earlier e
+WHERE c.match_id = e.dup
+  AND EXISTS (SELECT 1 FROM match_official o WHERE o.match_id = e.earlier);
+DELETE FROM pos_and_comp c USING match_earlier e
+WHERE c.match_id = e.dup
+  AND EXISTS (SELECT 1 FROM pos_and_comp o WHERE o.match_id = e.earlier);
+DELETE FROM attack c USING match_earlier e
+WHERE c.match_id = e.dup
+  AND EXISTS (SELECT 1 FROM attack o WHERE o.match_id = e.earlier);
+DELETE FROM passing c USING match_earlier e
+WHERE c.match_id = e.dup
+  AND EXISTS (SELECT 1 FROM passing o WHERE o.match_id = e.earlier);
+DELETE FROM kicking c USING match_earlier e
+WHERE c.match_id = e.dup
+  AND EXISTS (SELECT 1 FROM kicking o WHERE o.match_id = e.earlier);
+DELETE FROM defence c USING match_earlier e
+WHERE c.match_id = e.dup
+  AND EXISTS (SELECT 1 FROM defence o WHERE o.match_id = e.earlier);
+DELETE FROM neg_plays c USING match_earlier e
+WHERE c.match_id = e.dup
+  AND EXISTS (SELECT 1 FROM neg_plays o WHERE o.match_id = e.earlier);
+
+UPDATE match_player c SET match_id = mm.keep FROM match_merge mm WHERE c.match_id = mm.dup;
+UPDATE player_match_stats c SET match_id = mm.keep FROM match_merge mm WHERE c.match_id = mm.dup;
+UPDATE play_by_play c SET match_id = mm.keep FROM match_merge mm WHERE c.match_id = mm.dup;
+UPDATE match_official c SET match_id = mm.keep FROM match_merge mm WHERE c.match_id = mm.dup;
+UPDATE pos_and_comp c SET match_id = mm.keep FROM match_merge mm WHERE c.match_id = mm.dup;
+UPDATE attack c SET match_id = mm.keep FROM match_merge mm WHERE c.match_id = mm.dup;
+UPDATE passing c SET match_id = mm.keep FROM match_merge mm WHERE c.match_id = mm.dup;
+UPDATE kicking c SET match_id = mm.keep FROM match_merge mm WHERE c.match_id = mm.dup;
+UPDATE defence c SET match_id = mm.keep FROM match_merge mm WHERE c.match_id = mm.dup;
+UPDATE neg_plays c SET match_id = mm.keep FROM match_merge mm WHERE c.match_id = mm.dup;
+UPDATE scrape_error c SET match_id = mm.keep FROM match_merge mm WHERE c.match_id = mm.dup;
+UPDATE scrape_task c SET match_id = mm.keep FROM match_merge mm WHERE c.match_id = mm.dup;
+
+DELETE FROM match a USING match_merge mm WHERE a.id = mm.dup;
+
+DROP TABLE match_earlier;
+DROP TABLE match_merge;
+
+UPDATE match m
+SET home_team = h.nickname,
+    away_team = aw.nickname
+FROM team h, team aw
+WHERE h.id = m.home_team_id
+  AND aw.id = m.away_team_id;
+
+ALTER TABLE match DROP CONSTRAINT match_round_id_home_team_away_team_key;
+ALTER TABLE match
+    ADD CONSTRAINT match_round_id_home_team_id_away_team_id_key UNIQUE (round_id, home_team_id, away_team_id);
+
+CREATE INDEX match_home_team_id_idx ON match (home_team_id);
+CREATE INDEX match_away_team_id_idx ON match (away_team_id);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS match_venue_id_idx;
+ALTER TABLE match DROP COLUMN IF EXISTS venue_id;
+
+DROP TABLE IF EXISTS team_home_ground;
+DROP TABLE IF EXISTS venue_alias;
+DROP TABLE IF EXISTS venue;
@@ -0,0 +1,32 @@
+-- venues, their aliases and the home grounds are seeded by the scraper from
+-- scraper/data/venues.json
+CREATE TABLE venue (
+    id VARCHAR(50) PRIMARY KEY,
+    "name" VARCHAR(255) NOT NULL,
+    city VARCHAR(100) NOT NULL DEFAULT '',
+    "state" VARCHAR(50) NOT NULL DEFAULT '',
+    country VARCHAR(100) NOT NULL DEFAULT '',
+    latitude DOUBLE PRECISION NOT NULL,
+    longitude DOUBLE PRECISION NOT NULL,
+    capacity INT NOT NULL DEFAULT 0,
+    surface VARCHAR(20) NOT NULL DEFAULT ''
+);
+
+-- names a venue has gone by, stored lower case without punctuation
+CREATE TABLE venue_alias (
+    alias VARCHAR(255) PRIMARY KEY,
+    venue_id VARCHAR(50) NOT NULL REFERENCES venue(id) ON DELETE CASCADE
+);
+
+CREATE TABLE team_home_ground (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    team_id VARCHAR(50) NOT NULL REFERENCES team(id) ON DELETE CASCADE,
+    venue_id VARCHAR(50) NOT NULL REFERENCES venue(id) ON DELETE CASCADE,
+    first_season INT NOT NULL,
+    last_season INT,
+
+    UNIQUE(team_id, venue_id, first_season)
+);
+
+ALTER TABLE match ADD COLUMN venue_id VARCHAR(50) REFERENCES venue(id);
+CREATE INDEX match_venue_id_idx ON match (venue_id);
@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS play_by_play_player_id_idx;
+DROP INDEX IF EXISTS play_by_play_event_type_idx;
+
+ALTER TABLE play_by_play
+    DROP COLUMN IF EXISTS points,
+    DROP COLUMN IF EXISTS other_player_id,
+    DROP COLUMN IF EXISTS player_id,
+    DROP COLUMN IF EXISTS team_id,
+    DROP COLUMN IF EXISTS second,
+    DROP COLUMN IF EXISTS minute,
+    DROP COLUMN IF EXISTS event_type;
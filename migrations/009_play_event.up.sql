@@ -0,0 +1,16 @@
+-- nicknames such as "Wests Tigers" don't fit the original column
+ALTER TABLE play_by_play ALTER COLUMN team TYPE VARCHAR(100);
+
+ALTER TABLE play_by_play
+    ADD COLUMN event_type VARCHAR(30) NOT NULL DEFAULT 'other',
+    ADD COLUMN minute INT,
+    ADD COLUMN second INT,
+    ADD COLUMN team_id VARCHAR(50) REFERENCES team(id),
+    ADD COLUMN player_id UUID REFERENCES player(id) ON DELETE SET NULL,
+    ADD COLUMN other_player_id UUID REFERENCES player(id) ON DELETE SET NULL,
+    ADD COLUMN points INT NOT NULL DEFAULT 0;
+
+-- plays stored before this migration are typed when their match is scraped
+-- again
+CREATE INDEX play_by_play_event_type_idx ON play_by_play(event_type);
+CREATE INDEX play_by_play_player_id_idx ON play_by_play(player_id);
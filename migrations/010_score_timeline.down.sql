@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS match_score_event;
+DROP TABLE IF EXISTS match_score_timeline;
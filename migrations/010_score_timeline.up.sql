@@ -0,0 +1,33 @@
+-- derived from play_by_play once a match's plays are stored
+CREATE TABLE match_score_timeline (
+    match_id UUID PRIMARY KEY REFERENCES match(id) ON DELETE CASCADE,
+    half_time_home_score INT,
+    half_time_away_score INT,
+    home_score INT NOT NULL,
+    away_score INT NOT NULL,
+    biggest_home_lead INT NOT NULL DEFAULT 0,
+    biggest_away_lead INT NOT NULL DEFAULT 0,
+    lead_changes INT NOT NULL DEFAULT 0,
+    comeback BOOLEAN NOT NULL DEFAULT FALSE,
+    first_try_team_id VARCHAR(50) REFERENCES team(id),
+    first_try_player_id UUID REFERENCES player(id) ON DELETE SET NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- the score after each scoring play
+CREATE TABLE match_score_event (
+    match_id UUID NOT NULL REFERENCES match_score_timeline(match_id) ON DELETE CASCADE,
+    play_index INT NOT NULL,
+    minute INT,
+    second INT,
+    team_id VARCHAR(50) NOT NULL REFERENCES team(id),
+    event_type VARCHAR(30) NOT NULL,
+    player_id UUID REFERENCES player(id) ON DELETE SET NULL,
+    points INT NOT NULL,
+    home_score INT NOT NULL,
+    away_score INT NOT NULL,
+
+    PRIMARY KEY (match_id, play_index)
+);
+
+CREATE INDEX match_score_event_player_id_idx ON match_score_event(player_id);
@@ -0,0 +1,5 @@
+ALTER TABLE match_player
+    DROP COLUMN IF EXISTS late_change,
+    DROP COLUMN IF EXISTS vice_captain,
+    DROP COLUMN IF EXISTS captain,
+    DROP COLUMN IF EXISTS role;
@@ -0,0 +1,18 @@
+ALTER TABLE match_player
+    ADD COLUMN role VARCHAR(20) NOT NULL DEFAULT 'starter'
+        CHECK (role IN ('starter', 'interchange', 'reserve', '18th_man')),
+    ADD COLUMN captain BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN vice_captain BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN late_change BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- same rules as the scraper: the listed position first, then the number
+UPDATE match_player
+SET role = CASE
+    WHEN lower(position) LIKE '%18th%' THEN '18th_man'
+    WHEN lower(position) LIKE '%reserve%' THEN 'reserve'
+    WHEN lower(position) LIKE '%interchange%' OR lower(position) LIKE '%bench%' OR lower(position) LIKE '%replacement%' THEN 'interchange'
+    WHEN position <> '' AND number <= 13 THEN 'starter'
+    WHEN number >= 18 THEN 'reserve'
+    WHEN number >= 14 THEN 'interchange'
+    ELSE 'starter'
+END;
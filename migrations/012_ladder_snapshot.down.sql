@@ -0,0 +1 @@
+DROP TABLE IF EXISTS ladder_snapshot;
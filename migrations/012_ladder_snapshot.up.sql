@@ -0,0 +1,19 @@
+-- the ladder after each regular season round, worked out from match scores
+CREATE TABLE ladder_snapshot (
+    round_id UUID NOT NULL REFERENCES round(id) ON DELETE CASCADE,
+    team_id VARCHAR(50) NOT NULL REFERENCES team(id),
+    position INT NOT NULL,
+    played INT NOT NULL,
+    won INT NOT NULL,
+    drawn INT NOT NULL,
+    lost INT NOT NULL,
+    byes INT NOT NULL,
+    points INT NOT NULL,
+    points_for INT NOT NULL,
+    points_against INT NOT NULL,
+    differential INT NOT NULL,
+
+    PRIMARY KEY (round_id, team_id)
+);
+
+CREATE INDEX ladder_snapshot_team_id_idx ON ladder_snapshot(team_id);
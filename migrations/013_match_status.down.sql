@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS round_bye;
+
+DROP INDEX IF EXISTS match_status_idx;
+ALTER TABLE match DROP COLUMN IF EXISTS status;
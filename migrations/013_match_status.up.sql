@@ -0,0 +1,15 @@
+ALTER TABLE match
+    ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'scheduled'
+        CHECK (status IN ('scheduled', 'live', 'full_time', 'postponed'));
+
+-- a match with a score has been played
+UPDATE match SET status = 'full_time' WHERE home_score >= 0 AND away_score >= 0;
+
+CREATE INDEX match_status_idx ON match(status);
+
+CREATE TABLE round_bye (
+    round_id UUID NOT NULL REFERENCES round(id) ON DELETE CASCADE,
+    team_id VARCHAR(50) NOT NULL REFERENCES team(id),
+
+    PRIMARY KEY (round_id, team_id)
+);
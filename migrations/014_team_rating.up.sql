@@ -0,0 +1,11 @@
+-- each team's elo rating once a round has been played, replayed from the
+-- stored results
+CREATE TABLE team_rating (
+    round_id UUID NOT NULL REFERENCES round(id) ON DELETE CASCADE,
+    team_id VARCHAR(50) NOT NULL REFERENCES team(id),
+    rating DOUBLE PRECISION NOT NULL,
+
+    PRIMARY KEY (round_id, team_id)
+);
+
+CREATE INDEX team_rating_team_id_idx ON team_rating(team_id);
@@ -0,0 +1,14 @@
+-- each team's form going into a match, worked out only from the matches
+-- finished before it kicked off
+CREATE TABLE match_features (
+    match_id UUID NOT NULL REFERENCES match(id) ON DELETE CASCADE,
+    team_id VARCHAR(50) NOT NULL REFERENCES team(id),
+    opponent_id VARCHAR(50) NOT NULL REFERENCES team(id),
+    -- played games the features were taken from
+    games INT NOT NULL,
+    features JSONB NOT NULL,
+
+    PRIMARY KEY (match_id, team_id)
+);
+
+CREATE INDEX match_features_team_id_idx ON match_features(team_id);
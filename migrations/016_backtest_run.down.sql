@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS backtest_calibration;
+DROP TABLE IF EXISTS backtest_season;
+DROP TABLE IF EXISTS backtest_run;
@@ -0,0 +1,44 @@
+-- a walk-forward backtest of a model, with its scores over every predicted
+-- season together
+CREATE TABLE backtest_run (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    competition_id INT NOT NULL REFERENCES competition(id) ON DELETE CASCADE,
+    model VARCHAR(50) NOT NULL,
+    params TEXT NOT NULL DEFAULT '',
+    train_until VARCHAR(10) NOT NULL,
+    matches INT NOT NULL,
+    accuracy DOUBLE PRECISION NOT NULL,
+    log_loss DOUBLE PRECISION NOT NULL,
+    brier DOUBLE PRECISION NOT NULL,
+    margin_mae DOUBLE PRECISION NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX backtest_run_competition_id_idx ON backtest_run (competition_id, model, created_at);
+
+CREATE TABLE backtest_season (
+    run_id UUID NOT NULL REFERENCES backtest_run(id) ON DELETE CASCADE,
+    season VARCHAR(10) NOT NULL,
+    matches INT NOT NULL,
+    accuracy DOUBLE PRECISION NOT NULL,
+    log_loss DOUBLE PRECISION NOT NULL,
+    brier DOUBLE PRECISION NOT NULL,
+    margin_mae DOUBLE PRECISION NOT NULL,
+
+    PRIMARY KEY (run_id, season)
+);
+
+-- home win chances given in [bin_from, bin_to) against how often the home
+-- team won
+CREATE TABLE backtest_calibration (
+    run_id UUID NOT NULL REFERENCES backtest_run(id) ON DELETE CASCADE,
+    season VARCHAR(10) NOT NULL,
+    bin_from DOUBLE PRECISION NOT NULL,
+    bin_to DOUBLE PRECISION NOT NULL,
+    matches INT NOT NULL,
+    predicted DOUBLE PRECISION NOT NULL,
+    observed DOUBLE PRECISION NOT NULL,
+
+    PRIMARY KEY (run_id, season, bin_from),
+    FOREIGN KEY (run_id, season) REFERENCES backtest_season(run_id, season) ON DELETE CASCADE
+);
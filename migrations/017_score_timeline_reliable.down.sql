@@ -0,0 +1 @@
+ALTER TABLE match_score_timeline DROP COLUMN IF EXISTS reliable;
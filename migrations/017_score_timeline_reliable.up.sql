@@ -0,0 +1,8 @@
+-- a timeline whose plays don't add up to the final score is kept but marked
+ALTER TABLE match_score_timeline
+    ADD COLUMN reliable BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE match_score_timeline t
+SET reliable = (t.home_score = m.home_score AND t.away_score = m.away_score)
+FROM match m
+WHERE m.id = t.match_id;
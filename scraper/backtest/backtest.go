@@ -0,0 +1,169 @@
+// Package backtest scores prediction models by walking forward through past
+// results, pricing each round only from the rounds played before it.
+package backtest
+
+import (
+	"math"
+	"sort"
+
+	"github.com/0xlfl/nrl-predictor/scraper/elo"
+)
+
+// Prediction is a model's view of a match before it kicked off, next to how
+// it finished.
+type Prediction struct {
+	elo.Match
+	// chance of a home win, a draw counted as half
+	HomeWin float64
+	// expected home score less the away score
+	Margin float64
+}
+
+// Outcome is 1 for a home win, 0.5 for a draw and 0 for an away win.
+func (p Prediction) Outcome() float64 {
+	switch {
+	case p.HomeScore > p.AwayScore:
+		return 1
+	case p.HomeScore < p.AwayScore:
+		return 0
+	}
+
+	return 0.5
+}
+
+// Model is a prediction model that learns round by round.
+type Model interface {
+	// Predict prices the matches of a round from what has been learned so far.
+	Predict(round []elo.Match) []Prediction
+	// Learn takes the results of a round once every prediction for it is made.
+	Learn(round []elo.Match)
+}
+
+// Run steps through the matches round by round, predicting each round before
+// the model learns its results. Every season learns, but only the seasons
+// after trainUntil are predicted.
+func Run(model Model, matches []elo.Match, trainUntil string) []Prediction {
+	ordered := append([]elo.Match{}, matches...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		a, b := ordered[i], ordered[j]
+		if a.Season != b.Season {
+			return a.Season < b.Season
+		}
+		if a.Round != b.Round {
+			return a.Round < b.Round
+		}
+		return a.Kickoff.Before(b.Kickoff)
+	})
+
+	var predictions []Prediction
+	for start := 0; start < len(ordered); {
+		end := start + 1
+		for end < len(ordered) && ordered[end].Season == ordered[start].Season && ordered[end].Round == ordered[start].Round {
+			end++
+		}
+
+		round := ordered[start:end]
+		if round[0].Season > trainUntil {
+			predictions = append(predictions, model.Predict(round)...)
+		}
+		model.Learn(round)
+		start = end
+	}
+
+	return predictions
+}
+
+// Bin is a slice of the calibration table, holding the matches given a home
+// win chance in [From, To).
+type Bin struct {
+	From float64
+	To float64
+	Matches int
+	// mean chance the model gave, against the share the home team won
+	Predicted float64
+	Observed float64
+}
+
+// Report measures a set of predictions.
+type Report struct {
+	Season string
+	Matches int
+	// share of matches tipped right, with draws and even tips as half
+	Accuracy float64
+	LogLoss float64
+	Brier float64
+	MarginMAE float64
+	Calibration []Bin
+}
+
+// Bins is the number of calibration bins.
+const Bins = 10
+
+// Evaluate reports on the predictions of each season, followed by all of them
+// together under an empty season.
+func Evaluate(predictions []Prediction) []Report {
+	bySeason := map[string][]Prediction{}
+	var seasons []string
+	for _, p := range predictions {
+		if _, ok := bySeason[p.Season]; !ok {
+			seasons = append(seasons, p.Season)
+		}
+		bySeason[p.Season] = append(bySeason[p.Season], p)
+	}
+	sort.Strings(seasons)
+
+	reports := make([]Report, 0, len(seasons)+1)
+	for _, s := range seasons {
+		reports = append(reports, evaluate(s, bySeason[s]))
+	}
+
+	return append(reports, evaluate("", predictions))
+}
+
+func evaluate(season string, predictions []Prediction) Report {
+	r := Report{Season: season, Matches: len(predictions)}
+	r.Calibration = make([]Bin, Bins)
+	for i := range r.Calibration {
+		r.Calibration[i].From = float64(i) / Bins
+		r.Calibration[i].To = float64(i+1) / Bins
+	}
+	if len(predictions) == 0 {
+		return r
+	}
+
+	for _, p := range predictions {
+		y := p.Outcome()
+
+		switch {
+		case p.HomeWin == 0.5 || y == 0.5:
+			r.Accuracy += 0.5
+		case (p.HomeWin > 0.5) == (y == 1):
+			r.Accuracy++
+		}
+
+		// clipped so a confident miss doesn't make the loss infinite
+		q := math.Min(math.Max(p.HomeWin, 1e-6), 1-1e-6)
+		r.LogLoss -= y*math.Log(q) + (1-y)*math.Log(1-q)
+		r.Brier += (p.HomeWin - y) * (p.HomeWin - y)
+		r.MarginMAE += math.Abs(p.Margin - float64(p.HomeScore-p.AwayScore))
+
+		b := &r.Calibration[min(Bins-1, max(0, int(p.HomeWin*Bins)))]
+		b.Matches++
+		b.Predicted += p.HomeWin
+		b.Observed += y
+	}
+
+	n := float64(len(predictions))
+	r.Accuracy /= n
+	r.LogLoss /= n
+	r.Brier /= n
+	r.MarginMAE /= n
+	for i := range r.Calibration {
+		if b := &r.Calibration[i]; b.Matches > 0 {
+			b.Predicted /= float64(b.Matches)
+			b.Observed /= float64(b.Matches)
+		}
+	}
+
+	return r
+}
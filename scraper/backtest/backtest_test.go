@@ -0,0 +1,186 @@
+package backtest
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/0xlfl/nrl-predictor/scraper/elo"
+)
+
+// recorder logs the calls a model gets.
+type recorder struct {
+	calls []string
+}
+
+func roundName(round []elo.Match) string {
+	return fmt.Sprintf("%s/%d", round[0].Season, round[0].Round)
+}
+
+func (r *recorder) Predict(round []elo.Match) []Prediction {
+	r.calls = append(r.calls, "predict "+roundName(round))
+	predictions := make([]Prediction, 0, len(round))
+	for _, m := range round {
+		predictions = append(predictions, Prediction{Match: m, HomeWin: 0.5})
+	}
+	return predictions
+}
+
+func (r *recorder) Learn(round []elo.Match) {
+	r.calls = append(r.calls, fmt.Sprintf("learn %s x%d", roundName(round), len(round)))
+}
+
+func TestRun(t *testing.T) {
+	kickoff := time.Date(2023, time.March, 2, 9, 0, 0, 0, time.UTC)
+	matches := []elo.Match{
+		{Season: "2024", Round: 1, Kickoff: kickoff.AddDate(1, 0, 1), Home: "Storm", Away: "Panthers"},
+		{Season: "2023", Round: 2, Kickoff: kickoff.AddDate(0, 0, 7), Home: "Storm", Away: "Titans"},
+		{Season: "2023", Round: 1, Kickoff: kickoff, Home: "Panthers", Away: "Storm"},
+		{Season: "2024", Round: 1, Kickoff: kickoff.AddDate(1, 0, 0), Home: "Titans", Away: "Broncos"},
+		{Season: "2024", Round: 2, Kickoff: kickoff.AddDate(1, 0, 7), Home: "Broncos", Away: "Storm"},
+	}
+
+	model := &recorder{}
+	predictions := Run(model, matches, "2023")
+
+	want := []string{
+		"learn 2023/1 x1",
+		"learn 2023/2 x1",
+		"predict 2024/1",
+		"learn 2024/1 x2",
+		"predict 2024/2",
+		"learn 2024/2 x1",
+	}
+	if !reflect.DeepEqual(model.calls, want) {
+		t.Errorf("calls =\n%v\nwant\n%v", model.calls, want)
+	}
+
+	var got []string
+	for _, p := range predictions {
+		got = append(got, p.Home)
+	}
+	if !reflect.DeepEqual(got, []string{"Titans", "Storm", "Broncos"}) {
+		t.Errorf("predicted home teams = %v, want the 2024 matches by kickoff", got)
+	}
+
+	// a training cutoff past every season predicts nothing
+	if predictions := Run(&recorder{}, matches, "2024"); len(predictions) != 0 {
+		t.Errorf("got %d predictions with every season trained on", len(predictions))
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	prediction := func(homeWin, margin float64, homeScore, awayScore int) Prediction {
+		return Prediction{Match: elo.Match{Season: "2024", HomeScore: homeScore, AwayScore: awayScore}, HomeWin: homeWin, Margin: margin}
+	}
+	predictions := []Prediction{
+		prediction(0.8, 6, 20, 10),
+		// a draw is half right whatever the tip
+		prediction(0.3, -2, 12, 12),
+		// as is an even tip
+		prediction(0.5, 0, 6, 18),
+		// certain and wrong, clipped
+		prediction(1, 10, 0, 4),
+		prediction(0, -4, 8, 0),
+		prediction(0.999, 20, 30, 0),
+	}
+
+	r := evaluate("2024", predictions)
+
+	const n = 6.0
+	clip := math.Log(1e-6)
+	want := Report{
+		Season: "2024",
+		Matches: 6,
+		Accuracy: (1 + 0.5 + 0.5 + 0 + 0 + 1) / n,
+		LogLoss: -(math.Log(0.8) + 0.5*math.Log(0.3) + 0.5*math.Log(0.7) + math.Log(0.5) + clip + clip + math.Log(0.999)) / n,
+		Brier: (0.04 + 0.04 + 0.25 + 1 + 1 + 0.000001) / n,
+		MarginMAE: (4 + 2 + 12 + 14 + 12 + 10) / n,
+	}
+
+	near := func(name string, got, want float64) {
+		t.Helper()
+		if math.Abs(got-want) > 1e-9 {
+			t.Errorf("%s = %v, want %v", name, got, want)
+		}
+	}
+	if r.Season != want.Season || r.Matches != want.Matches {
+		t.Errorf("season, matches = %q, %d, want %q, %d", r.Season, r.Matches, want.Season, want.Matches)
+	}
+	near("accuracy", r.Accuracy, want.Accuracy)
+	near("log loss", r.LogLoss, want.LogLoss)
+	near("brier", r.Brier, want.Brier)
+	near("margin MAE", r.MarginMAE, want.MarginMAE)
+
+	if len(r.Calibration) != Bins {
+		t.Fatalf("got %d bins, want %d", len(r.Calibration), Bins)
+	}
+	bins := []struct {
+		index int
+		matches int
+		predicted float64
+		observed float64
+	}{
+		// 0.0 falls in the first bin, 0.999 and 1.0 in the last
+		{0, 1, 0, 1},
+		{3, 1, 0.3, 0.5},
+		{5, 1, 0.5, 0},
+		{8, 1, 0.8, 1},
+		{9, 2, (1 + 0.999) / 2, 0.5},
+	}
+	counted := 0
+	for _, b := range bins {
+		got := r.Calibration[b.index]
+		if got.Matches != b.matches {
+			t.Errorf("bin %d holds %d matches, want %d", b.index, got.Matches, b.matches)
+		}
+		near(fmt.Sprintf("bin %d predicted", b.index), got.Predicted, b.predicted)
+		near(fmt.Sprintf("bin %d observed", b.index), got.Observed, b.observed)
+		counted += got.Matches
+	}
+	if counted != len(predictions) {
+		t.Errorf("bins hold %d matches, want %d", counted, len(predictions))
+	}
+	for i, b := range r.Calibration {
+		near(fmt.Sprintf("bin %d from", i), b.From, float64(i)/10)
+		near(fmt.Sprintf("bin %d to", i), b.To, float64(i+1)/10)
+	}
+}
+
+func TestEvaluateEmpty(t *testing.T) {
+	reports := Evaluate(nil)
+	if len(reports) != 1 {
+		t.Fatalf("got %d reports, want only the overall one", len(reports))
+	}
+
+	r := reports[0]
+	if r.Season != "" || r.Matches != 0 || r.Accuracy != 0 || r.LogLoss != 0 || r.Brier != 0 || r.MarginMAE != 0 {
+		t.Errorf("report = %+v, want zeros", r)
+	}
+	if len(r.Calibration) != Bins {
+		t.Errorf("got %d bins, want %d", len(r.Calibration), Bins)
+	}
+	for _, b := range r.Calibration {
+		if b.Matches != 0 || math.IsNaN(b.Predicted) || math.IsNaN(b.Observed) {
+			t.Errorf("bin = %+v, want empty", b)
+		}
+	}
+}
+
+func TestEvaluateSeasons(t *testing.T) {
+	predictions := []Prediction{
+		{Match: elo.Match{Season: "2024", HomeScore: 10, AwayScore: 0}, HomeWin: 0.6},
+		{Match: elo.Match{Season: "2023", HomeScore: 0, AwayScore: 10}, HomeWin: 0.6},
+		{Match: elo.Match{Season: "2024", HomeScore: 10, AwayScore: 0}, HomeWin: 0.6},
+	}
+
+	var got []string
+	for _, r := range Evaluate(predictions) {
+		got = append(got, fmt.Sprintf("%s:%d:%.2f", r.Season, r.Matches, r.Accuracy))
+	}
+	if want := []string{"2023:1:0.00", "2024:2:1.00", ":3:0.67"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("reports = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,99 @@
+package backtest
+
+import (
+	"time"
+
+	"github.com/0xlfl/nrl-predictor/scraper/elo"
+	"github.com/0xlfl/nrl-predictor/scraper/poisson"
+)
+
+type eloModel struct {
+	model *elo.Model
+	// least squares fit of the margin on the win chance less a half, learned
+	// from the results seen so far
+	sxy float64
+	sxx float64
+}
+
+// NewElo backtests the elo ratings. Elo only gives a win chance, so the
+// margin is read off it by a line fitted to the results learned so far.
+func NewElo(cfg elo.Config) Model {
+	return &eloModel{model: elo.New(cfg)}
+}
+
+func (m *eloModel) Predict(round []elo.Match) []Prediction {
+	predictions := make([]Prediction, 0, len(round))
+	for _, match := range round {
+		// the regression between seasons is known before a ball is kicked
+		m.model.StartSeason(match.Season)
+		p := m.model.HomeWinProbability(match.Home, match.Away, match.Neutral)
+
+		var margin float64
+		if m.sxx > 0 {
+			margin = (p - 0.5) * m.sxy / m.sxx
+		}
+		predictions = append(predictions, Prediction{Match: match, HomeWin: p, Margin: margin})
+	}
+
+	return predictions
+}
+
+func (m *eloModel) Learn(round []elo.Match) {
+	for _, match := range round {
+		x := m.model.HomeWinProbability(match.Home, match.Away, match.Neutral) - 0.5
+		m.sxy += x * float64(match.HomeScore-match.AwayScore)
+		m.sxx += x * x
+		m.model.Update(match)
+	}
+}
+
+type poissonModel struct {
+	cfg poisson.Config
+	results []poisson.Match
+	latest time.Time
+}
+
+// NewPoisson backtests the score model, refitted before every round.
+func NewPoisson(cfg poisson.Config) Model {
+	return &poissonModel{cfg: cfg}
+}
+
+func (m *poissonModel) Predict(round []elo.Match) []Prediction {
+	// fitted at the first kickoff of the round, so a match moved to before
+	// the rest of its round is still priced blind
+	var at time.Time
+	for _, match := range round {
+		if !match.Kickoff.IsZero() && (at.IsZero() || match.Kickoff.Before(at)) {
+			at = match.Kickoff
+		}
+	}
+	if at.IsZero() {
+		at = m.latest.Add(time.Nanosecond)
+	}
+	model := poisson.Fit(m.cfg, m.results, at)
+
+	predictions := make([]Prediction, 0, len(round))
+	for _, match := range round {
+		homeMean, awayMean := model.Expected(match.Home, match.Away, match.Neutral)
+		homeWin, draw, _ := model.Predict(match.Home, match.Away, match.Neutral).Outcome()
+		predictions = append(predictions, Prediction{Match: match, HomeWin: homeWin + draw/2, Margin: homeMean - awayMean})
+	}
+
+	return predictions
+}
+
+func (m *poissonModel) Learn(round []elo.Match) {
+	for _, match := range round {
+		if match.Kickoff.After(m.latest) {
+			m.latest = match.Kickoff
+		}
+		m.results = append(m.results, poisson.Match{
+			Kickoff: match.Kickoff,
+			Home: match.Home,
+			Away: match.Away,
+			HomeScore: match.HomeScore,
+			AwayScore: match.AwayScore,
+			Neutral: match.Neutral,
+		})
+	}
+}
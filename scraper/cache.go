@@ -0,0 +1,274 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+type CacheConfig struct {
+	// CompletedTTL applies to pages where every match has finished.
+	CompletedTTL time.Duration
+	// UpcomingTTL applies to every other page, including live ones.
+	UpcomingTTL time.Duration
+	// For either TTL, zero means the pages never expire.
+}
+
+var DefaultCacheConfig = CacheConfig{
+	CompletedTTL: 30 * 24 * time.Hour,
+	UpcomingTTL: 6 * time.Hour,
+}
+
+type cacheEntry struct {
+	Hash string `json:"hash"`
+	FetchedAt time.Time `json:"fetchedAt"`
+	Completed bool `json:"completed"`
+}
+
+// PageCache is an on-disk, content-addressed store of fetched pages. Bodies
+// live under objects/ named by their sha256, and index.json maps each url to
+// the body it last resolved to and when it was fetched. Entries added since
+// the cache was opened are appended to index.log rather than rewriting the
+// whole index for every page, and folded into index.json on the next open.
+type PageCache struct {
+	dir string
+	config CacheConfig
+
+	mu sync.Mutex
+	index map[string]cacheEntry
+	log *os.File
+}
+
+type cacheLogEntry struct {
+	Key string `json:"key"`
+	cacheEntry
+}
+
+func NewPageCache(dir string, config CacheConfig) (*PageCache, error) {
+	if err := os.MkdirAll(filepath.Join(dir, "objects"), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create cache dir: %w", err)
+	}
+
+	c := &PageCache{
+		dir: dir,
+		config: config,
+		index: map[string]cacheEntry{},
+	}
+
+	content, err := os.ReadFile(c.indexPath())
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read cache index: %w", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(content, &c.index); err != nil {
+			return nil, fmt.Errorf("failed to parse cache index: %w", err)
+		}
+	}
+
+	if err := c.compact(); err != nil {
+		return nil, err
+	}
+
+	c.log, err = os.OpenFile(c.logPath(), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open cache log: %w", err)
+	}
+
+	return c, nil
+}
+
+// compact folds the entries logged by earlier runs into index.json. A line
+// cut short by a crash mid-write is skipped.
+func (c *PageCache) compact() error {
+	content, err := os.ReadFile(c.logPath())
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read cache log: %w", err)
+	}
+
+	for _, line := range strings.Split(string(content), "\n") {
+		var e cacheLogEntry
+		if err := json.Unmarshal([]byte(line), &e); err != nil || e.Key == "" {
+			continue
+		}
+		c.index[e.Key] = e.cacheEntry
+	}
+
+	encoded, err := json.MarshalIndent(c.index, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode cache index: %w", err)
+	}
+	if err := writeFileAtomic(c.indexPath(), encoded); err != nil {
+		return fmt.Errorf("failed to write cache index: %w", err)
+	}
+
+	return os.Remove(c.logPath())
+}
+
+func (c *PageCache) indexPath() string {
+	return filepath.Join(c.dir, "index.json")
+}
+
+func (c *PageCache) logPath() string {
+	return filepath.Join(c.dir, "index.log")
+}
+
+func (c *PageCache) objectPath(hash string) string {
+	return filepath.Join(c.dir, "objects", hash+".html")
+}
+
+func (c *PageCache) fresh(e cacheEntry) bool {
+	ttl := c.config.UpcomingTTL
+	if e.Completed {
+		ttl = c.config.CompletedTTL
+	}
+
+	return ttl == 0 || time.Since(e.FetchedAt) < ttl
+}
+
+// Get returns the cached body for key if it exists and has not expired.
+func (c *PageCache) Get(key string) (string, bool) {
+	c.mu.Lock()
+	e, ok := c.index[key]
+	c.mu.Unlock()
+
+	if !ok || !c.fresh(e) {
+		return "", false
+	}
+
+	content, err := os.ReadFile(c.objectPath(e.Hash))
+	if err != nil {
+		return "", false
+	}
+
+	return string(content), true
+}
+
+// CachedAt returns when key was fetched, or the zero time if it is missing or
+// expired.
+func (c *PageCache) CachedAt(key string) time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.index[key]
+	if !ok || !c.fresh(e) {
+		return time.Time{}
+	}
+
+	return e.FetchedAt
+}
+
+func (c *PageCache) Put(key string, html string) error {
+	sum := sha256.Sum256([]byte(html))
+	hash := hex.EncodeToString(sum[:])
+
+	path := c.objectPath(hash)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		if err := writeFileAtomic(path, []byte(html)); err != nil {
+			return fmt.Errorf("failed to write cache object: %w", err)
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e := cacheEntry{
+		Hash: hash,
+		FetchedAt: time.Now(),
+		// a page fetched with instructions is the season and round lists,
+		// which grow with every new season
+		Completed: !strings.HasSuffix(key, interactiveKey) && isCompletedPage(html),
+	}
+	c.index[key] = e
+
+	line, err := json.Marshal(cacheLogEntry{Key: key, cacheEntry: e})
+	if err != nil {
+		return fmt.Errorf("failed to encode cache entry: %w", err)
+	}
+	if _, err := c.log.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write cache log: %w", err)
+	}
+
+	return nil
+}
+
+// Close closes the log new entries are appended to.
+func (c *PageCache) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.log.Close()
+}
+
+// writeFileAtomic writes content to a temp file of its own before moving it
+// into place, so concurrent writers of the same path never share one.
+func writeFileAtomic(path string, content []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// isCompletedPage reports whether every match on a draw or match centre page
+// is over, meaning the page will not change again. Every match card on a draw
+// needs a full time state and both scores, and a match centre needs both
+// scores and a full time state or play. Any other page is never completed.
+func isCompletedPage(html string) bool {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return false
+	}
+
+	// a bye is drawn as a card with a single team
+	cards := doc.Find(".match").FilterFunction(func(_ int, s *goquery.Selection) bool {
+		return s.Find(".match-team__name--home").Length() > 0 && s.Find(".match-team__name--away").Length() > 0
+	})
+	if cards.Length() > 0 {
+		completed := true
+		cards.EachWithBreak(func(_ int, s *goquery.Selection) bool {
+			status, ok := shownStatus(s)
+			completed = ok && status == StatusFullTime && hasScore(s)
+			return completed
+		})
+
+		return completed
+	}
+
+	if doc.Find(".match").Length() > 0 || !hasScore(doc.Selection) {
+		return false
+	}
+	if status, ok := shownStatus(doc.Selection); ok {
+		return status == StatusFullTime
+	}
+	for _, p := range extractPlays(doc) {
+		if p.eventType == EventFullTime {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,247 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPageCacheReplaysLog(t *testing.T) {
+	dir := t.TempDir()
+
+	c, err := NewPageCache(dir, DefaultCacheConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Put("https://www.nrl.com/draw/", "<p>draw</p>"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Put("https://www.nrl.com/draw/"+interactiveKey, "<p>seasons</p>"); err != nil {
+		t.Fatal(err)
+	}
+	c.Close()
+
+	// a write cut short by a crash leaves a partial line behind
+	f, err := os.OpenFile(filepath.Join(dir, "index.log"), os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"key":"https://www.nrl.com/lad`)
+	f.Close()
+
+	c, err = NewPageCache(dir, DefaultCacheConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	if html, ok := c.Get("https://www.nrl.com/draw/"); !ok || html != "<p>draw</p>" {
+		t.Errorf("Get = %q, %v after reopening", html, ok)
+	}
+	if c.CachedAt("https://www.nrl.com/draw/"+interactiveKey).IsZero() {
+		t.Error("interactive page missing after reopening")
+	}
+	if len(c.index) != 2 {
+		t.Errorf("index has %d entries, want 2", len(c.index))
+	}
+
+	p := &PageFetcher{cache: c}
+	if p.IsCached("https://www.nrl.com/draw/") == 0 {
+		t.Error("IsCached = 0 for a cached page")
+	}
+}
+
+func TestIsCachedInteractiveOnly(t *testing.T) {
+	c, err := NewPageCache(t.TempDir(), DefaultCacheConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	url := "https://www.nrl.com/draw/?competition=111&round=1&season=2025"
+	if err := c.Put(url+interactiveKey, "<p>seasons</p>"); err != nil {
+		t.Fatal(err)
+	}
+
+	p := &PageFetcher{cache: c}
+	if p.IsCached(url) == 0 {
+		t.Error("IsCached = 0 for a page only fetched with instructions")
+	}
+	if p.IsCached("https://www.nrl.com/ladder/") != 0 {
+		t.Error("IsCached != 0 for a page never fetched")
+	}
+}
+
+func TestPageCacheTTL(t *testing.T) {
+	c, err := NewPageCache(t.TempDir(), CacheConfig{CompletedTTL: 24 * time.Hour, UpcomingTTL: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	tests := []struct {
+		name string
+		completed bool
+		age time.Duration
+		want bool
+	}{
+		{"upcoming page inside its TTL", false, 30 * time.Minute, true},
+		{"upcoming page past its TTL", false, 2 * time.Hour, false},
+		{"completed page past the upcoming TTL", true, 2 * time.Hour, true},
+		{"completed page past its TTL", true, 48 * time.Hour, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := cacheEntry{FetchedAt: time.Now().Add(-tt.age), Completed: tt.completed}
+			if got := c.fresh(e); got != tt.want {
+				t.Errorf("fresh = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if DefaultCacheConfig.CompletedTTL <= 0 {
+		t.Error("completed pages never expire by default")
+	}
+
+	never, err := NewPageCache(t.TempDir(), CacheConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer never.Close()
+	if !never.fresh(cacheEntry{FetchedAt: time.Now().AddDate(-1, 0, 0)}) {
+		t.Error("a zero TTL expired a page")
+	}
+}
+
+func TestIsCompletedPage(t *testing.T) {
+	card := func(state, home, away string) string {
+		return `<div class="match"><div class="match-state">` + state + `</div>` +
+			`<p class="match-team__name match-team__name--home">Storm</p>` +
+			`<p class="match-team__score match-team__score--home">` + home + `</p>` +
+			`<p class="match-team__score match-team__score--away">` + away + `</p>` +
+			`<p class="match-team__name match-team__name--away">Panthers</p></div>`
+	}
+	bye := `<div class="match match--bye"><p class="match-team__name match-team__name--home">Dolphins</p><p>BYE</p></div>`
+
+	tests := []struct {
+		name string
+		html string
+		want bool
+	}{
+		// full time on the page, but Knights v Raiders is postponed and
+		// Cowboys v Titans hasn't been played
+		{"draw with a postponed and an unplayed match", fixturePage(t, roundFixture), false},
+		{"draw with every match over", card("Full Time", "8", "0") + card("Full Time", "36", "24") + bye, true},
+		{"draw with a live match", card("Full Time", "8", "0") + card("2nd Half", "12", "6"), false},
+		{"draw with a match missing its score", card("Full Time", "8", "0") + card("Full Time", "", ""), false},
+		{"draw of byes", bye, false},
+		{"played match centre", fixturePage(t, matchFixture), true},
+		{"match centre without scores", fixturePage(t, stormFixture), false},
+		{"ladder", fixturePage(t, "https://www.nrl.com/ladder/?competition=111&round=1&season=2024"), false},
+		{"season list", `<select><option>2024</option></select><p>Full Time</p>`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isCompletedPage(tt.html); got != tt.want {
+				t.Errorf("isCompletedPage = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPutInteractiveNeverCompleted(t *testing.T) {
+	c, err := NewPageCache(t.TempDir(), DefaultCacheConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	html := fixturePage(t, matchFixture)
+	if err := c.Put(matchFixture, html); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Put(matchFixture+interactiveKey, html); err != nil {
+		t.Fatal(err)
+	}
+
+	if !c.index[matchFixture].Completed {
+		t.Error("played match centre not marked completed")
+	}
+	if c.index[matchFixture+interactiveKey].Completed {
+		t.Error("page fetched with instructions marked completed")
+	}
+}
+
+func TestWriteFileAtomicConcurrent(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "page.html")
+	body := strings.Repeat("<p>draw</p>", 10000)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- writeFileAtomic(path, []byte(body))
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != body {
+		t.Errorf("read back %d bytes, want %d", len(content), len(body))
+	}
+
+	// no temp file is left behind
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("%d files in the dir, want 1", len(entries))
+	}
+}
+
+func TestFetchRecordsCachedPages(t *testing.T) {
+	c, err := NewPageCache(t.TempDir(), DefaultCacheConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	url := "https://www.nrl.com/draw/?competition=111&round=1&season=2024"
+	if err := c.Put(url, "<p>draw</p>"); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	p := &PageFetcher{cache: c, recordDir: dir}
+	if _, err := p.Fetch(context.Background(), url, nil, true); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := os.ReadFile(fixturePath(dir, url))
+	if err != nil {
+		t.Fatalf("cached page not recorded: %v", err)
+	}
+	if string(content) != "<p>draw</p>" {
+		t.Errorf("recorded %q", content)
+	}
+}
@@ -0,0 +1,890 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"sync"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/0xlfl/nrl-predictor/scraper/backtest"
+	"github.com/0xlfl/nrl-predictor/scraper/elo"
+	"github.com/0xlfl/nrl-predictor/scraper/poisson"
+	"github.com/0xlfl/nrl-predictor/scraper/tryscorer"
+)
+
+const usage = `usage: scraper <command> [flags]
+
+commands:
+  scrape    scrape a competition from nrl.com into the database
+  resume    re-run the unfinished and failed tasks of a scrape
+  export    write a competition from the database to a json file
+  migrate   apply database migrations from -dir and seed the venue registry
+  stats     summarise what has been scraped for a competition
+  ladder    work out the ladder after each round and check it against nrl.com
+  elo       rate teams from the stored results and price upcoming fixtures
+  predict   predict the scores of upcoming fixtures from team strengths
+  features  work out each team's form going into every match from the stats
+  backtest  score a model by predicting past rounds from the rounds before them
+  tries     price the players named for upcoming matches to score a try
+  serve     serve competition exports over http
+
+run "scraper <command> -h" for the flags of a command
+`
+
+func run(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, usage)
+		return 2
+	}
+
+	commands := map[string]func(context.Context, []string) error{
+		"scrape": runScrape,
+		"resume": runResume,
+		"export": runExport,
+		"migrate": runMigrate,
+		"stats": runStats,
+		"ladder": runLadder,
+		"elo": runElo,
+		"predict": runPredict,
+		"features": runFeatures,
+		"backtest": runBacktest,
+		"tries": runTries,
+		"serve": runServe,
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", args[0], usage)
+		return 2
+	}
+
+	ctx, cancel := shutdownContext()
+	defer cancel()
+
+	if err := cmd(ctx, args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		return 1
+	}
+
+	return 0
+}
+
+// shutdownContext is cancelled on the first SIGINT or SIGTERM so commands can
+// wind down cleanly. A second signal falls through to the default handler and
+// kills the process.
+func shutdownContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		defer signal.Stop(sigs)
+
+		select {
+		case sig := <-sigs:
+			fmt.Printf("Received %s, finishing in-flight work. Send it again to quit immediately.\n", sig)
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	return ctx, cancel
+}
+
+// fetcherFlags are the flags shared by the commands that fetch pages.
+type fetcherFlags struct {
+	concurrency *int
+	fixtures *string
+	record *string
+	cacheDir *string
+}
+
+func addFetcherFlags(fs *flag.FlagSet) fetcherFlags {
+	return fetcherFlags{
+		concurrency: fs.Int("concurrency", 10, "maximum pages fetched at once"),
+		fixtures: fs.String("fixtures", "", "serve pages from this fixture dir instead of nrl.com"),
+		record: fs.String("record", "", "save every fetched page into this fixture dir"),
+		cacheDir: fs.String("cache", "", "cache fetched pages in this dir"),
+	}
+}
+
+func (ff fetcherFlags) fetcher() (Fetcher, error) {
+	if *ff.fixtures != "" {
+		return NewFixtureFetcher(*ff.fixtures)
+	}
+
+	pf, err := NewPageFetcher(*ff.concurrency)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create page fetcher: %w", err)
+	}
+	if *ff.record != "" {
+		if err := pf.Record(*ff.record); err != nil {
+			pf.Close()
+			return nil, err
+		}
+	}
+	if *ff.cacheDir != "" {
+		if err := pf.UseCache(*ff.cacheDir, DefaultCacheConfig); err != nil {
+			pf.Close()
+			return nil, err
+		}
+	}
+
+	return pf, nil
+}
+
+func runScrape(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("scrape", flag.ExitOnError)
+	compID := fs.Int("comp", 111, "competition id")
+	seasons := fs.String("seasons", "current", `seasons to scrape: "all", "current", a range such as "2015-2024" or a list such as "2019,2021"`)
+	rounds := fs.String("rounds", "all", `rounds to scrape: "all", a range such as "1-10" or a list such as "1,5,9"`)
+	fetch := addFetcherFlags(fs)
+	fs.Parse(args)
+
+	seasonSel, err := ParseSelection(*seasons)
+	if err != nil {
+		return fmt.Errorf("invalid -seasons: %w", err)
+	}
+	roundSel, err := ParseRoundSelection(*rounds)
+	if err != nil {
+		return fmt.Errorf("invalid -rounds: %w", err)
+	}
+
+	cfg := &ScrapeConfig{
+		seasons: seasonSel,
+		rounds: roundSel,
+	}
+
+	f, err := fetch.fetcher()
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	db, err := NewDB()
+	if err != nil {
+		return err
+	}
+	defer db.Conn.Close()
+
+	var wg sync.WaitGroup
+	return Scrape(ctx, db, *compID, f, &wg, cfg)
+}
+
+func runResume(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("resume", flag.ExitOnError)
+	compID := fs.Int("comp", 111, "competition id")
+	runSpec := fs.String("run", "", "id of the scrape run to resume, defaults to the latest run of the competition")
+	fetch := addFetcherFlags(fs)
+	fs.Parse(args)
+
+	runID := uuid.Nil
+	if *runSpec != "" {
+		id, err := uuid.Parse(*runSpec)
+		if err != nil {
+			return fmt.Errorf("invalid -run: %w", err)
+		}
+		runID = id
+	}
+
+	f, err := fetch.fetcher()
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	db, err := NewDB()
+	if err != nil {
+		return err
+	}
+	defer db.Conn.Close()
+
+	var wg sync.WaitGroup
+	return Resume(ctx, db, *compID, runID, f, &wg)
+}
+
+func runExport(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	compID := fs.Int("comp", 111, "competition id")
+	out := fs.String("out", "/app/output/results.json", "file to write the export to")
+	fs.Parse(args)
+
+	db, err := NewDB()
+	if err != nil {
+		return err
+	}
+	defer db.Conn.Close()
+
+	content, err := exportCompetition(ctx, db, *compID)
+	if err != nil {
+		return err
+	}
+
+	return writeToFile(string(content), *out)
+}
+
+func exportCompetition(ctx context.Context, db *DB, compID int) ([]byte, error) {
+	comp, err := db.GetCompetition(ctx, compID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load competition %d: %w", compID, err)
+	}
+
+	return NewExport(comp).Marshal()
+}
+
+func runMigrate(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("migrate", flag.ExitOnError)
+	dir := fs.String("dir", "", `directory holding the migration files, "../migrations" from scraper/ or "/migrations" in the container`)
+	fs.Parse(args)
+
+	// the binary runs from wherever it was installed, so there is no default
+	// that finds the migrations everywhere
+	if *dir == "" {
+		return fmt.Errorf("-dir is required")
+	}
+
+	db, err := NewDB()
+	if err != nil {
+		return err
+	}
+	defer db.Conn.Close()
+
+	applied, err := db.Migrate(ctx, *dir)
+	fmt.Printf("Applied %d migrations\n", applied)
+	if err != nil {
+		return err
+	}
+
+	return db.SeedVenues(ctx)
+}
+
+func runStats(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("stats", flag.ExitOnError)
+	compID := fs.Int("comp", 111, "competition id")
+	fs.Parse(args)
+
+	db, err := NewDB()
+	if err != nil {
+		return err
+	}
+	defer db.Conn.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
+	defer cancel()
+
+	summaries, err := db.GetSeasonSummaries(ctx, *compID)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "season\trounds\tmatches\tplayed\tstats\tteam lists\tplay by play\t")
+	for _, s := range summaries {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%d\t%d\t\n",
+			s.year, s.rounds, s.matches, s.played, s.withStats, s.withTeamLists, s.withPlayByPlay)
+	}
+
+	return w.Flush()
+}
+
+func runLadder(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("ladder", flag.ExitOnError)
+	compID := fs.Int("comp", 111, "competition id")
+	seasons := fs.String("seasons", "all", `seasons to work out: "all", "current", a range such as "2015-2024" or a list such as "2019,2021"`)
+	rounds := fs.String("rounds", "all", `rounds to check against nrl.com: "all", a range such as "1-10" or a list such as "1,5,9"`)
+	validate := fs.Bool("validate", false, "compare each ladder with the one on nrl.com")
+	fetch := addFetcherFlags(fs)
+	fs.Parse(args)
+
+	seasonSel, err := ParseSelection(*seasons)
+	if err != nil {
+		return fmt.Errorf("invalid -seasons: %w", err)
+	}
+	roundSel, err := ParseRoundSelection(*rounds)
+	if err != nil {
+		return fmt.Errorf("invalid -rounds: %w", err)
+	}
+	cfg := &ScrapeConfig{
+		seasons: seasonSel,
+		rounds: roundSel,
+	}
+
+	db, err := NewDB()
+	if err != nil {
+		return err
+	}
+	defer db.Conn.Close()
+
+	var f Fetcher
+	if *validate {
+		if f, err = fetch.fetcher(); err != nil {
+			return err
+		}
+		defer f.Close()
+	}
+
+	available, err := db.GetSeasonYears(ctx, *compID)
+	if err != nil {
+		return err
+	}
+
+	mismatched := 0
+	missingTeams := 0
+	for _, season := range cfg.selectSeasons(available) {
+		dbCtx, cancel := context.WithTimeout(ctx, 60*time.Second)
+		snapshots, missing, err := updateLadder(dbCtx, db, *compID, season)
+		cancel()
+		if err != nil {
+			return fmt.Errorf("season %s: %w", season, err)
+		}
+		fmt.Printf("Season %s: ladder after %d rounds\n", season, len(snapshots))
+		for _, m := range missing {
+			missingTeams++
+			fmt.Println("  ", missingFromRound(m))
+		}
+
+		if !*validate {
+			continue
+		}
+		for _, snapshot := range snapshots {
+			if !cfg.includesRound(snapshot.Round) {
+				continue
+			}
+
+			diffs, err := ValidateLadder(ctx, db, f, *compID, season, snapshot)
+			if err != nil {
+				return fmt.Errorf("season %s round %d: %w", season, snapshot.Round, err)
+			}
+			if len(diffs) == 0 {
+				continue
+			}
+
+			mismatched++
+			fmt.Printf("  round %d differs from nrl.com:\n", snapshot.Round)
+			for _, d := range diffs {
+				fmt.Println("   ", d)
+			}
+		}
+	}
+
+	if mismatched > 0 {
+		return fmt.Errorf("%d ladders differ from nrl.com", mismatched)
+	}
+	if missingTeams > 0 {
+		return fmt.Errorf("%d teams have neither a match nor the bye in a round", missingTeams)
+	}
+
+	return nil
+}
+
+func runElo(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("elo", flag.ExitOnError)
+	compID := fs.Int("comp", 111, "competition id")
+	k := fs.Float64("k", elo.DefaultConfig.K, "how far a single result moves a rating")
+	homeAdvantage := fs.Float64("home-advantage", elo.DefaultConfig.HomeAdvantage, "rating points given to the home team")
+	regression := fs.Float64("regression", elo.DefaultConfig.SeasonRegression, "share of the gap to the mean a rating loses between seasons")
+	mov := fs.Bool("mov", elo.DefaultConfig.MarginOfVictory, "scale updates by the margin of victory")
+	home := fs.String("home", "", "home team of a fixture to price")
+	away := fs.String("away", "", "away team of a fixture to price")
+	neutral := fs.Bool("neutral", false, "the fixture given by -home and -away is at a neutral venue")
+	fs.Parse(args)
+
+	cfg := elo.DefaultConfig
+	cfg.K, cfg.HomeAdvantage, cfg.SeasonRegression, cfg.MarginOfVictory = *k, *homeAdvantage, *regression, *mov
+
+	db, err := NewDB()
+	if err != nil {
+		return err
+	}
+	defer db.Conn.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
+	defer cancel()
+
+	model, err := UpdateRatings(ctx, db, *compID, cfg)
+	if err != nil {
+		return err
+	}
+
+	ratings := model.Ratings()
+	teams := make([]string, 0, len(ratings))
+	for team := range ratings {
+		teams = append(teams, team)
+	}
+	sort.Slice(teams, func(i, j int) bool { return ratings[teams[i]] > ratings[teams[j]] })
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "team\trating\t")
+	for _, team := range teams {
+		fmt.Fprintf(w, "%s\t%.1f\t\n", team, ratings[team])
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if *home != "" || *away != "" {
+		h, a, err := resolveFixture(ctx, db, *compID, *home, *away)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("\n%s v %s: %.1f%% home win\n", h.nickname, a.nickname, 100*model.HomeWinProbability(h.id, a.id, *neutral))
+		return nil
+	}
+
+	fixtures, _, err := db.GetRatingMatches(ctx, *compID, true)
+	if err != nil {
+		return err
+	}
+	if len(fixtures) == 0 {
+		return nil
+	}
+
+	fmt.Println()
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "season\tround\thome\taway\thome win\t")
+	for _, f := range fixtures {
+		// fixtures of a season yet to start are priced on regressed ratings
+		model.StartSeason(f.Season)
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%.1f%%\t\n", f.Season, f.Round, f.Home, f.Away, 100*model.HomeWinProbability(f.Home, f.Away, f.Neutral))
+	}
+
+	return w.Flush()
+}
+
+// resolveFixture finds the teams of a fixture given on the command line, as
+// named in the latest season.
+func resolveFixture(ctx context.Context, db *DB, compID int, home, away string) (Team, Team, error) {
+	if home == "" || away == "" {
+		return Team{}, Team{}, fmt.Errorf("-home and -away are needed to price a fixture")
+	}
+
+	seasons, err := db.GetSeasonYears(ctx, compID)
+	if err != nil {
+		return Team{}, Team{}, err
+	}
+	season := ""
+	if len(seasons) > 0 {
+		season = seasons[len(seasons)-1]
+	}
+
+	h, err := db.ResolveTeam(ctx, home, season)
+	if err != nil {
+		return Team{}, Team{}, err
+	}
+	a, err := db.ResolveTeam(ctx, away, season)
+	if err != nil {
+		return Team{}, Team{}, err
+	}
+
+	return h, a, nil
+}
+
+func runPredict(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("predict", flag.ExitOnError)
+	compID := fs.Int("comp", 111, "competition id")
+	halfLife := fs.Duration("half-life", poisson.DefaultConfig.HalfLife, "age at which a result counts half as much")
+	covariance := fs.Float64("covariance", poisson.DefaultConfig.Covariance, "points shared by both scores, zero for independent scores and negative to estimate it")
+	line := fs.Float64("line", 44.5, "total points line to price overs and unders at")
+	home := fs.String("home", "", "home team of a fixture to price")
+	away := fs.String("away", "", "away team of a fixture to price")
+	neutral := fs.Bool("neutral", false, "the fixture given by -home and -away is at a neutral venue")
+	fs.Parse(args)
+
+	cfg := poisson.DefaultConfig
+	cfg.HalfLife, cfg.Covariance = *halfLife, *covariance
+
+	db, err := NewDB()
+	if err != nil {
+		return err
+	}
+	defer db.Conn.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
+	defer cancel()
+
+	results, _, err := db.GetRatingMatches(ctx, *compID, false)
+	if err != nil {
+		return err
+	}
+	model := poisson.Fit(cfg, scoreMatches(results), time.Now())
+
+	var fixtures []elo.Match
+	if *home != "" || *away != "" {
+		h, a, err := resolveFixture(ctx, db, *compID, *home, *away)
+		if err != nil {
+			return err
+		}
+		fixtures = []elo.Match{{Home: h.id, Away: a.id, Neutral: *neutral}}
+	} else if fixtures, _, err = db.GetRatingMatches(ctx, *compID, true); err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "season\tround\thome\taway\tscore\thome win\tdraw\taway win\thome 1-12\thome 13+\taway 1-12\taway 13+\tover %.1f\tunder %.1f\t\n", *line, *line)
+	for _, f := range fixtures {
+		homeMean, awayMean := model.Expected(f.Home, f.Away, f.Neutral)
+		d := model.Predict(f.Home, f.Away, f.Neutral)
+		homeWin, draw, awayWin := d.Outcome()
+		homeBands, awayBands := d.BandProbabilities(poisson.MarginBands)
+
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%.1f-%.1f\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t\n",
+			f.Season, f.Round, f.Home, f.Away, homeMean, awayMean,
+			percent(homeWin), percent(draw), percent(awayWin),
+			percent(homeBands[0]), percent(homeBands[1]), percent(awayBands[0]), percent(awayBands[1]),
+			percent(d.Over(*line)), percent(d.Under(*line)))
+	}
+
+	return w.Flush()
+}
+
+func percent(p float64) string {
+	return fmt.Sprintf("%.1f%%", 100*p)
+}
+
+func runFeatures(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("features", flag.ExitOnError)
+	compID := fs.Int("comp", 111, "competition id")
+	match := fs.String("match", "", "id of a match to show the features of")
+	fs.Parse(args)
+
+	var matchID uuid.UUID
+	if *match != "" {
+		id, err := uuid.Parse(*match)
+		if err != nil {
+			return fmt.Errorf("invalid match id %q: %w", *match, err)
+		}
+		matchID = id
+	}
+
+	db, err := NewDB()
+	if err != nil {
+		return err
+	}
+	defer db.Conn.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
+	defer cancel()
+
+	rows, err := UpdateFeatures(ctx, db, *compID)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Stored features for %d team matches\n", len(rows))
+
+	if matchID == uuid.Nil {
+		return nil
+	}
+
+	teams, err := db.GetMatchFeatures(ctx, matchID)
+	if err != nil {
+		return err
+	}
+	if len(teams) == 0 {
+		return fmt.Errorf("no features stored for match %s", matchID)
+	}
+
+	names := map[string]bool{}
+	for _, t := range teams {
+		for name := range t.Features {
+			names[name] = true
+		}
+	}
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	fmt.Println()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprint(w, "feature\t")
+	for _, t := range teams {
+		fmt.Fprintf(w, "%s (%d games)\t", t.Team, t.Games)
+	}
+	fmt.Fprintln(w)
+	for _, name := range sorted {
+		fmt.Fprintf(w, "%s\t", name)
+		for _, t := range teams {
+			if v, ok := t.Features[name]; ok {
+				fmt.Fprintf(w, "%.2f\t", v)
+			} else {
+				fmt.Fprint(w, "-\t")
+			}
+		}
+		fmt.Fprintln(w)
+	}
+
+	return w.Flush()
+}
+
+func runBacktest(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("backtest", flag.ExitOnError)
+	compID := fs.Int("comp", 111, "competition id")
+	modelName := fs.String("model", "elo", "model to backtest, elo or poisson")
+	trainUntil := fs.String("train-until", "", "last season only trained on, defaults to the first stored season")
+	k := fs.Float64("k", elo.DefaultConfig.K, "elo: how far a single result moves a rating")
+	homeAdvantage := fs.Float64("home-advantage", elo.DefaultConfig.HomeAdvantage, "elo: rating points given to the home team")
+	regression := fs.Float64("regression", elo.DefaultConfig.SeasonRegression, "elo: share of the gap to the mean a rating loses between seasons")
+	mov := fs.Bool("mov", elo.DefaultConfig.MarginOfVictory, "elo: scale updates by the margin of victory")
+	halfLife := fs.Duration("half-life", poisson.DefaultConfig.HalfLife, "poisson: age at which a result counts half as much")
+	covariance := fs.Float64("covariance", poisson.DefaultConfig.Covariance, "poisson: points shared by both scores, negative to estimate it")
+	save := fs.Bool("save", true, "store the run for comparing models")
+	fs.Parse(args)
+
+	var (
+		model backtest.Model
+		params string
+	)
+	switch *modelName {
+	case "elo":
+		cfg := elo.DefaultConfig
+		cfg.K, cfg.HomeAdvantage, cfg.SeasonRegression, cfg.MarginOfVictory = *k, *homeAdvantage, *regression, *mov
+		model = backtest.NewElo(cfg)
+		params = fmt.Sprintf("k=%g home-advantage=%g regression=%g mov=%t", *k, *homeAdvantage, *regression, *mov)
+	case "poisson":
+		cfg := poisson.DefaultConfig
+		cfg.HalfLife, cfg.Covariance = *halfLife, *covariance
+		model = backtest.NewPoisson(cfg)
+		params = fmt.Sprintf("half-life=%s covariance=%g", *halfLife, *covariance)
+	default:
+		return fmt.Errorf("unknown model %q", *modelName)
+	}
+
+	db, err := NewDB()
+	if err != nil {
+		return err
+	}
+	defer db.Conn.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+
+	if *trainUntil == "" {
+		seasons, err := db.GetSeasonYears(ctx, *compID)
+		if err != nil {
+			return err
+		}
+		if len(seasons) == 0 {
+			return fmt.Errorf("no seasons stored for competition %d", *compID)
+		}
+		*trainUntil = seasons[0]
+	}
+
+	results, _, err := db.GetRatingMatches(ctx, *compID, false)
+	if err != nil {
+		return err
+	}
+
+	predictions := backtest.Run(model, results, *trainUntil)
+	if len(predictions) == 0 {
+		return fmt.Errorf("no matches played after %s to predict", *trainUntil)
+	}
+	reports := backtest.Evaluate(predictions)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "season\tmatches\taccuracy\tlog loss\tbrier\tmargin mae\t")
+	for _, r := range reports {
+		season := r.Season
+		if season == "" {
+			season = "all"
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\t%.4f\t%.4f\t%.2f\t\n", season, r.Matches, percent(r.Accuracy), r.LogLoss, r.Brier, r.MarginMAE)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	for _, r := range reports {
+		season := r.Season
+		if season == "" {
+			season = "all seasons"
+		}
+		fmt.Printf("\ncalibration, %s\n", season)
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "home win\tmatches\tpredicted\tobserved\t")
+		for _, b := range r.Calibration {
+			if b.Matches == 0 {
+				continue
+			}
+			fmt.Fprintf(w, "%.0f-%.0f%%\t%d\t%s\t%s\t\n", 100*b.From, 100*b.To, b.Matches, percent(b.Predicted), percent(b.Observed))
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+
+	if !*save {
+		return nil
+	}
+
+	runID, err := db.SaveBacktest(ctx, *compID, *modelName, params, *trainUntil, reports)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("\nSaved backtest run %s\n", runID)
+
+	return nil
+}
+
+func runTries(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("tries", flag.ExitOnError)
+	compID := fs.Int("comp", 111, "competition id")
+	match := fs.String("match", "", "id of a match to price, defaults to every upcoming match with team lists")
+	fs.Parse(args)
+
+	db, err := NewDB()
+	if err != nil {
+		return err
+	}
+	defer db.Conn.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
+	defer cancel()
+
+	var matchIDs []uuid.UUID
+	if *match != "" {
+		id, err := uuid.Parse(*match)
+		if err != nil {
+			return fmt.Errorf("invalid match id %q: %w", *match, err)
+		}
+		matchIDs = []uuid.UUID{id}
+	} else if matchIDs, err = db.GetNamedFixtures(ctx, *compID); err != nil {
+		return err
+	}
+	if len(matchIDs) == 0 {
+		fmt.Println("No upcoming matches have team lists yet")
+		return nil
+	}
+
+	results, _, err := db.GetRatingMatches(ctx, *compID, false)
+	if err != nil {
+		return err
+	}
+	apps, err := db.GetTryAppearances(ctx, *compID)
+	if err != nil {
+		return err
+	}
+
+	for i, id := range matchIDs {
+		card, err := priceTryScorers(ctx, db, id, results, apps)
+		if err != nil {
+			return err
+		}
+
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("%s round %d: %s v %s\n", card.fixture.Season, card.fixture.Round, card.fixture.Home, card.fixture.Away)
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "team\t#\tplayer\tposition\tminutes\ttries\tanytime\tfirst\t")
+		for _, side := range []struct {
+			team string
+			prices []tryscorer.Price
+		}{{card.fixture.Home, card.home}, {card.fixture.Away, card.away}} {
+			for _, p := range side.prices {
+				player := card.players[p.Player]
+				fmt.Fprintf(w, "%s\t%d\t%s %s\t%s\t%.0f\t%.2f\t%s\t%s\t\n",
+					side.team, player.number, player.nameFirst, player.nameLast, p.Position,
+					p.Minutes, p.Tries, percent(p.Anytime), percent(p.First))
+			}
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func runServe(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := fs.String("addr", ":8080", "address to listen on")
+	ttl := fs.Duration("ttl", 5*time.Minute, "how long an export is cached before it is rebuilt")
+	fs.Parse(args)
+
+	db, err := NewDB()
+	if err != nil {
+		return err
+	}
+	defer db.Conn.Close()
+
+	s := &exportServer{
+		db: db,
+		ttl: *ttl,
+		cache: map[int]cachedExport{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /competitions/{id}", s.handleCompetition)
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	server := &http.Server{Addr: *addr, Handler: mux}
+	context.AfterFunc(ctx, func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	})
+
+	fmt.Println("Listening on", *addr)
+	if err := server.ListenAndServe(); err != http.ErrServerClosed {
+		return err
+	}
+
+	return nil
+}
+
+type cachedExport struct {
+	content []byte
+	builtAt time.Time
+}
+
+type exportServer struct {
+	db *DB
+	ttl time.Duration
+
+	mu sync.Mutex
+	cache map[int]cachedExport
+}
+
+func (s *exportServer) handleCompetition(w http.ResponseWriter, r *http.Request) {
+	compID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "invalid competition id", http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	cached, ok := s.cache[compID]
+	s.mu.Unlock()
+
+	if !ok || time.Since(cached.builtAt) > s.ttl {
+		content, err := exportCompetition(r.Context(), s.db, compID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		cached = cachedExport{content: content, builtAt: time.Now()}
+		s.mu.Lock()
+		s.cache[compID] = cached
+		s.mu.Unlock()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Last-Modified", cached.builtAt.UTC().Format(http.TimeFormat))
+	w.Write(cached.content)
+}
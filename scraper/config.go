@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Selection picks seasons or rounds by number. It is parsed from "all", a
+// range such as "2015-2024", a list such as "2019,2021,2023", or "current"
+// for the latest available season.
+type Selection struct {
+	all bool
+	current bool
+	from int
+	to int
+	values map[int]bool
+}
+
+func ParseSelection(spec string) (Selection, error) {
+	spec = strings.ToLower(strings.TrimSpace(spec))
+
+	switch spec {
+	case "", "all":
+		return Selection{all: true}, nil
+	case "current":
+		return Selection{current: true}, nil
+	}
+
+	if from, to, ok := strings.Cut(spec, "-"); ok {
+		f, err := strconv.Atoi(strings.TrimSpace(from))
+		if err != nil {
+			return Selection{}, fmt.Errorf("invalid range start %q", from)
+		}
+		t, err := strconv.Atoi(strings.TrimSpace(to))
+		if err != nil {
+			return Selection{}, fmt.Errorf("invalid range end %q", to)
+		}
+		if f > t {
+			return Selection{}, fmt.Errorf("range %q ends before it starts", spec)
+		}
+
+		return Selection{from: f, to: t}, nil
+	}
+
+	values := map[int]bool{}
+	for _, v := range strings.Split(spec, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			return Selection{}, fmt.Errorf("invalid value %q", v)
+		}
+		values[n] = true
+	}
+
+	return Selection{values: values}, nil
+}
+
+// ParseRoundSelection parses a selection of rounds, which has no "current".
+func ParseRoundSelection(spec string) (Selection, error) {
+	sel, err := ParseSelection(spec)
+	if err != nil {
+		return Selection{}, err
+	}
+	if sel.current {
+		return Selection{}, fmt.Errorf(`"current" only applies to seasons`)
+	}
+
+	return sel, nil
+}
+
+// String gives back a spec ParseSelection accepts, so a selection can be
+// stored with a scrape run and restored when resuming it.
+func (s Selection) String() string {
+	switch {
+	case s.all:
+		return "all"
+	case s.current:
+		return "current"
+	case s.values != nil:
+		values := make([]int, 0, len(s.values))
+		for v := range s.values {
+			values = append(values, v)
+		}
+		sort.Ints(values)
+
+		parts := make([]string, len(values))
+		for i, v := range values {
+			parts[i] = strconv.Itoa(v)
+		}
+		return strings.Join(parts, ",")
+	}
+
+	return fmt.Sprintf("%d-%d", s.from, s.to)
+}
+
+// resolve turns "current" into the latest of the values it picks from.
+func (s Selection) resolve(latest int) Selection {
+	if !s.current {
+		return s
+	}
+
+	return Selection{values: map[int]bool{latest: true}}
+}
+
+// includes reports whether v is selected. A "current" selection has to be
+// resolved first and includes nothing until it is.
+func (s Selection) includes(v int) bool {
+	switch {
+	case s.all:
+		return true
+	case s.current:
+		return false
+	case s.values != nil:
+		return s.values[v]
+	}
+
+	return v >= s.from && v <= s.to
+}
+
+// ScrapeConfig selects which part of a competition a scrape covers.
+type ScrapeConfig struct {
+	seasons Selection
+	rounds Selection
+}
+
+// selectSeasons filters the seasons listed on nrl.com down to the ones the
+// config asks for, keeping their original order.
+func (c *ScrapeConfig) selectSeasons(available []string) []string {
+	latest := 0
+	for _, season := range available {
+		if year, err := strconv.Atoi(season); err == nil && year > latest {
+			latest = year
+		}
+	}
+
+	seasons := c.seasons.resolve(latest)
+
+	var selected []string
+	for _, season := range available {
+		year, err := strconv.Atoi(season)
+		if err != nil {
+			continue
+		}
+
+		if seasons.includes(year) {
+			selected = append(selected, season)
+		}
+	}
+
+	return selected
+}
+
+func (c *ScrapeConfig) includesRound(roundIndex int) bool {
+	return c.rounds.includes(roundIndex)
+}
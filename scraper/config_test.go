@@ -0,0 +1,54 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSelectSeasons(t *testing.T) {
+	available := []string{"2025", "2024", "2023", "2022"}
+
+	tests := []struct {
+		spec string
+		want []string
+	}{
+		{"all", []string{"2025", "2024", "2023", "2022"}},
+		{"current", []string{"2025"}},
+		{"2023-2024", []string{"2024", "2023"}},
+		{"2022,2025", []string{"2025", "2022"}},
+		{"2019", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			sel, err := ParseSelection(tt.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			cfg := &ScrapeConfig{seasons: sel}
+			if got := cfg.selectSeasons(available); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("seasons = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRoundSelection(t *testing.T) {
+	if _, err := ParseRoundSelection("current"); err == nil {
+		t.Error("no error for current rounds")
+	}
+
+	sel, err := ParseRoundSelection("1-3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sel.includes(3) || sel.includes(0) || sel.includes(4) {
+		t.Errorf("rounds 1-3 = %+v", sel)
+	}
+
+	// an unresolved current never falls through to the empty range
+	current, _ := ParseSelection("current")
+	if current.includes(0) {
+		t.Error("unresolved current includes 0")
+	}
+}
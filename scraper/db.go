@@ -3,25 +3,94 @@ package main
 import (
     "context"
     "database/sql"
+    "encoding/json"
+    "errors"
     _ "github.com/lib/pq" 
     "fmt"
     "os"
+    "path/filepath"
+    "sort"
+    "strconv"
     "strings"
     "time"
 
 	"github.com/google/uuid"
+
+	"github.com/0xlfl/nrl-predictor/scraper/backtest"
+	"github.com/0xlfl/nrl-predictor/scraper/elo"
+	"github.com/0xlfl/nrl-predictor/scraper/features"
+	"github.com/0xlfl/nrl-predictor/scraper/ladder"
+	"github.com/0xlfl/nrl-predictor/scraper/tryscorer"
 )
 
 type DB struct {
     Conn *sql.DB
 }
 
+// querier is satisfied by both *sql.DB and *sql.Tx, letting helpers run inside
+// a caller's transaction instead of taking a second pooled connection.
+type querier interface {
+    ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+    QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+    QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+type PoolConfig struct {
+    MaxOpenConns int
+    MaxIdleConns int
+    ConnMaxLifetime time.Duration
+}
+
+// PoolConfigFromEnv reads DB_MAX_OPEN_CONNS, DB_MAX_IDLE_CONNS and
+// DB_CONN_MAX_LIFETIME, falling back to limits that sit well under Postgres'
+// default of 100 connections.
+func PoolConfigFromEnv() (PoolConfig, error) {
+    cfg := PoolConfig{
+        MaxOpenConns: 20,
+        MaxIdleConns: 10,
+        ConnMaxLifetime: 30 * time.Minute,
+    }
+
+    if v := os.Getenv("DB_MAX_OPEN_CONNS"); v != "" {
+        n, err := strconv.Atoi(v)
+        if err != nil {
+            return cfg, fmt.Errorf("invalid DB_MAX_OPEN_CONNS: %w", err)
+        }
+        cfg.MaxOpenConns = n
+    }
+
+    if v := os.Getenv("DB_MAX_IDLE_CONNS"); v != "" {
+        n, err := strconv.Atoi(v)
+        if err != nil {
+            return cfg, fmt.Errorf("invalid DB_MAX_IDLE_CONNS: %w", err)
+        }
+        cfg.MaxIdleConns = n
+    }
+
+    if v := os.Getenv("DB_CONN_MAX_LIFETIME"); v != "" {
+        d, err := time.ParseDuration(v)
+        if err != nil {
+            return cfg, fmt.Errorf("invalid DB_CONN_MAX_LIFETIME: %w", err)
+        }
+        cfg.ConnMaxLifetime = d
+    }
+
+    return cfg, nil
+}
+
+// NewDB opens the connection pool shared by everything in the process. It
+// should be called once at startup and passed down to whatever needs it.
 func NewDB() (*DB, error) {
     host := os.Getenv("DB_HOST")
     user := os.Getenv("DB_USER")
     password := os.Getenv("DB_PASSWORD")
     dbname := os.Getenv("DB_NAME")
 
+    pool, err := PoolConfigFromEnv()
+    if err != nil {
+        return nil, err
+    }
+
     dsn := fmt.Sprintf(
         "host=%s user=%s password=%s dbname=%s sslmode=disable",
         host, user, password, dbname,
@@ -32,28 +101,16 @@ func NewDB() (*DB, error) {
         return nil, err
     }
 
-    // Retry loop in case of "too many clients" or transient errors
-    maxRetries := 10
-    for i := 0; i < maxRetries; i++ {
-        err = conn.Ping()
-        if err == nil {
-            return &DB{Conn: conn}, nil
-        }
-
-        // Detect "too many clients" explicitly (Postgres code: 53300)
-        if strings.Contains(err.Error(), "too many clients") {
-            wait := time.Duration(i+1) * time.Second // simple backoff
-            fmt.Printf("Database has too many clients, retrying in %v...\n", wait)
-            time.Sleep(wait)
-            maxRetries++
-            continue
-        }
+    conn.SetMaxOpenConns(pool.MaxOpenConns)
+    conn.SetMaxIdleConns(pool.MaxIdleConns)
+    conn.SetConnMaxLifetime(pool.ConnMaxLifetime)
 
-        // Other errors -> return immediately
-        return nil, err
+    if err := conn.Ping(); err != nil {
+        conn.Close()
+        return nil, fmt.Errorf("could not connect to database: %w", err)
     }
 
-    return nil, fmt.Errorf("could not connect to database after retries: %w", err)
+    return &DB{Conn: conn}, nil
 }
 
 func (db *DB) CreateCompIfNotExist(ctx context.Context, name string, id int) error {
@@ -109,49 +166,131 @@ func (db *DB) CreateSeasonIfNotExist(ctx context.Context, competitionID int, yea
     return seasonID, nil
 }
 
-func (db *DB) CreateRound(ctx context.Context, roundIndex int, roundName string, seasonID uuid.UUID) (uuid.UUID, bool, error) {
-    var (
-        roundID      uuid.UUID
-        datesAreSet  bool
-    )
+func (db *DB) CreateRound(ctx context.Context, roundIndex int, roundName string, seasonID uuid.UUID) (uuid.UUID, error) {
+    var roundID uuid.UUID
 
     err := db.Conn.QueryRowContext(ctx, `
         INSERT INTO round (id, season_id, round_index, round_name)
         VALUES ($1, $2, $3, $4)
         ON CONFLICT (season_id, round_index) 
         DO UPDATE SET round_name = EXCLUDED.round_name
-        RETURNING id, (start_day IS NOT NULL AND end_day IS NOT NULL) AS dates_set
+        RETURNING id
     `,
         uuid.New(), seasonID, roundIndex, roundName,
-    ).Scan(&roundID, &datesAreSet)
+    ).Scan(&roundID)
 
     if err != nil {
-        return uuid.Nil, false, err
+        return uuid.Nil, err
     }
-    return roundID, datesAreSet, nil
+    return roundID, nil
 }
 
-func (db *DB) SetRoundDates(ctx context.Context, roundID uuid.UUID, startDay, endDay string) error {
-    _, err := db.Conn.ExecContext(ctx, `
-        UPDATE round
-        SET start_day = $1, end_day = $2
-        WHERE id = $3
-    `, startDay, endDay, roundID)
+// ResolveTeam finds the club a name refers to in the given season through the
+// team registry's aliases.
+func (db *DB) ResolveTeam(ctx context.Context, name, season string) (Team, error) {
+    var year sql.NullInt64
+    if y, err := strconv.Atoi(season); err == nil {
+        year = sql.NullInt64{Int64: int64(y), Valid: true}
+    }
 
-    return err
+    var t Team
+    err := db.Conn.QueryRowContext(ctx, `
+        SELECT t.id, t.name, t.nickname
+        FROM team_alias a
+        JOIN team t ON t.id = a.team_id
+        WHERE a.alias = $1
+          AND ($2::int IS NULL OR a.first_season IS NULL OR a.first_season <= $2)
+          AND ($2::int IS NULL OR a.last_season IS NULL OR a.last_season >= $2)
+        ORDER BY a.first_season DESC NULLS LAST
+        LIMIT 1
+    `, normaliseName(name), year).Scan(&t.id, &t.name, &t.nickname)
+    if err == sql.ErrNoRows {
+        return t, fmt.Errorf("%w %q in season %s, add it to team_alias", ErrUnknownTeam, name, season)
+    }
+    if err != nil {
+        return t, fmt.Errorf("failed to resolve team %q: %w", name, err)
+    }
+
+    return t, nil
 }
 
-func (db *DB) CreateMatch(ctx context.Context, roundID uuid.UUID, homeTeam, awayTeam string) (uuid.UUID, error) {
+// CreateMatch stores a match between the clubs the names resolve to, under
+// their canonical nicknames. A name team_alias doesn't know is stored as
+// nrl.com showed it with a NULL team id, and the match id is returned along
+// with an ErrUnknownTeam error naming it, so the match is still scraped.
+func (db *DB) CreateMatch(ctx context.Context, roundID uuid.UUID, season, homeTeam, awayTeam string) (uuid.UUID, error) {
     var matchID uuid.UUID
 
-    err := db.Conn.QueryRowContext(ctx, `
-        INSERT INTO match (id, round_id, home_team, away_team)
-        VALUES ($1, $2, $3, $4)
-        ON CONFLICT (round_id, home_team, away_team)
-        DO UPDATE SET home_team = EXCLUDED.home_team
+    home, homeErr := db.ResolveTeam(ctx, homeTeam, season)
+    if homeErr != nil && !errors.Is(homeErr, ErrUnknownTeam) {
+        return uuid.Nil, homeErr
+    }
+    away, awayErr := db.ResolveTeam(ctx, awayTeam, season)
+    if awayErr != nil && !errors.Is(awayErr, ErrUnknownTeam) {
+        return uuid.Nil, awayErr
+    }
+
+    unknown := errors.Join(homeErr, awayErr)
+    if unknown != nil {
+        var homeID, awayID any
+        homeName, awayName := homeTeam, awayTeam
+        if homeErr == nil {
+            homeID, homeName = home.id, home.nickname
+        }
+        if awayErr == nil {
+            awayID, awayName = away.id, away.nickname
+        }
+
+        // no unique key covers a NULL team id, so the match is found by the
+        // names it was stored under
+        err := db.Conn.QueryRowContext(ctx, `
+            SELECT id FROM match
+            WHERE round_id = $1 AND home_team = $2 AND away_team = $3
+        `, roundID, homeName, awayName).Scan(&matchID)
+        if err == nil {
+            return matchID, unknown
+        }
+        if err != sql.ErrNoRows {
+            return uuid.Nil, err
+        }
+
+        err = db.Conn.QueryRowContext(ctx, `
+            INSERT INTO match (id, round_id, home_team, away_team, home_team_id, away_team_id)
+            VALUES ($1, $2, $3, $4, $5, $6)
+            RETURNING id
+        `, uuid.New(), roundID, homeName, awayName, homeID, awayID).Scan(&matchID)
+        if err != nil {
+            return uuid.Nil, err
+        }
+
+        return matchID, unknown
+    }
+
+    // a match stored before its names were added to team_alias takes the
+    // resolved teams, unless they already have a match in the round
+    _, err := db.Conn.ExecContext(ctx, `
+        UPDATE match
+        SET home_team = $4, away_team = $5, home_team_id = $6, away_team_id = $7
+        WHERE round_id = $1 AND home_team = $2 AND away_team = $3
+          AND (home_team_id IS NULL OR away_team_id IS NULL)
+          AND NOT EXISTS (
+              SELECT 1 FROM match
+              WHERE round_id = $1 AND home_team_id = $6 AND away_team_id = $7
+          )
+    `, roundID, homeTeam, awayTeam, home.nickname, away.nickname, home.id, away.id)
+    if err != nil {
+        return uuid.Nil, err
+    }
+
+    err = db.Conn.QueryRowContext(ctx, `
+        INSERT INTO match (id, round_id, home_team, away_team, home_team_id, away_team_id)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        ON CONFLICT (round_id, home_team_id, away_team_id)
+        DO UPDATE SET home_team = EXCLUDED.home_team,
+            away_team = EXCLUDED.away_team
         RETURNING id
     `,
-        uuid.New(), roundID, homeTeam, awayTeam,
+        uuid.New(), roundID, home.nickname, away.nickname, home.id, away.id,
     ).Scan(&matchID)
 
     if err != nil {
@@ -161,21 +300,39 @@ func (db *DB) CreateMatch(ctx context.Context, roundID uuid.UUID, homeTeam, away
     return matchID, nil
 }
 
-func (db *DB) CreatePlay(ctx context.Context, matchID uuid.UUID, playIndex int, timeStr, playText, team, notes string) (uuid.UUID, error) {
+func (db *DB) CreatePlay(ctx context.Context, matchID uuid.UUID, playIndex int, play *Play) (uuid.UUID, error) {
     var playID uuid.UUID
 
+    var minute, second any
+    if play.timed {
+        minute, second = play.minute, play.second
+    }
+    var teamID any
+    if play.teamID != "" {
+        teamID = play.teamID
+    }
+
     err := db.Conn.QueryRowContext(ctx, `
-        INSERT INTO play_by_play (id, match_id, play_index, time, play, team, notes)
-        VALUES ($1, $2, $3, $4, $5, $6, $7)
+        INSERT INTO play_by_play (id, match_id, play_index, time, play, team, notes,
+            event_type, minute, second, team_id, player_id, other_player_id, points)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
         ON CONFLICT (match_id, play_index) 
         DO UPDATE 
         SET time = EXCLUDED.time,
             play = EXCLUDED.play,
             team = EXCLUDED.team,
-            notes = EXCLUDED.notes
+            notes = EXCLUDED.notes,
+            event_type = EXCLUDED.event_type,
+            minute = EXCLUDED.minute,
+            second = EXCLUDED.second,
+            team_id = EXCLUDED.team_id,
+            player_id = EXCLUDED.player_id,
+            other_player_id = EXCLUDED.other_player_id,
+            points = EXCLUDED.points
         RETURNING id
     `,
-        uuid.New(), matchID, playIndex, timeStr, playText, team, notes,
+        uuid.New(), matchID, playIndex, play.time, play.play, play.team, play.notes,
+        string(play.eventType), minute, second, teamID, nullUUID(play.playerID), nullUUID(play.otherPlayerID), play.points,
     ).Scan(&playID)
 
     if err != nil {
@@ -184,6 +341,102 @@ func (db *DB) CreatePlay(ctx context.Context, matchID uuid.UUID, playIndex int,
     return playID, nil
 }
 
+// GetMatchTeams returns the registry ids of the home and away team.
+func (db *DB) GetMatchTeams(ctx context.Context, matchID uuid.UUID) (string, string, error) {
+    var home, away string
+    err := db.Conn.QueryRowContext(ctx, `
+        SELECT COALESCE(home_team_id, ''), COALESCE(away_team_id, '')
+        FROM match
+        WHERE id = $1
+    `, matchID).Scan(&home, &away)
+
+    return home, away, err
+}
+
+// GetMatchScore returns the final score of a match, -1 for a side without
+// one.
+func (db *DB) GetMatchScore(ctx context.Context, matchID uuid.UUID) (int, int, error) {
+    var home, away int
+    err := db.Conn.QueryRowContext(ctx, `
+        SELECT home_score, away_score
+        FROM match
+        WHERE id = $1
+    `, matchID).Scan(&home, &away)
+
+    return home, away, err
+}
+
+// SetScoreTimeline replaces the score timeline of a match.
+func (db *DB) SetScoreTimeline(ctx context.Context, matchID uuid.UUID, tl *ScoreTimeline) error {
+    tx, err := db.Conn.BeginTx(ctx, nil)
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
+    var halfTimeHome, halfTimeAway any
+    if tl.halfTimeKnown {
+        halfTimeHome, halfTimeAway = tl.halfTimeHome, tl.halfTimeAway
+    }
+    var firstTryTeamID any
+    if tl.firstTryTeamID != "" {
+        firstTryTeamID = tl.firstTryTeamID
+    }
+
+    if _, err := tx.ExecContext(ctx, `
+        INSERT INTO match_score_timeline (match_id, half_time_home_score, half_time_away_score,
+            home_score, away_score, biggest_home_lead, biggest_away_lead, lead_changes, comeback,
+            first_try_team_id, first_try_player_id, reliable, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, now())
+        ON CONFLICT (match_id)
+        DO UPDATE
+        SET half_time_home_score = EXCLUDED.half_time_home_score,
+            half_time_away_score = EXCLUDED.half_time_away_score,
+            home_score = EXCLUDED.home_score,
+            away_score = EXCLUDED.away_score,
+            biggest_home_lead = EXCLUDED.biggest_home_lead,
+            biggest_away_lead = EXCLUDED.biggest_away_lead,
+            lead_changes = EXCLUDED.lead_changes,
+            comeback = EXCLUDED.comeback,
+            first_try_team_id = EXCLUDED.first_try_team_id,
+            first_try_player_id = EXCLUDED.first_try_player_id,
+            reliable = EXCLUDED.reliable,
+            updated_at = EXCLUDED.updated_at
+    `,
+        matchID, halfTimeHome, halfTimeAway, tl.homeScore, tl.awayScore, tl.biggestHomeLead, tl.biggestAwayLead,
+        tl.leadChanges, tl.comeback, firstTryTeamID, nullUUID(tl.firstTryPlayerID), tl.reliable,
+    ); err != nil {
+        return fmt.Errorf("failed to store score timeline: %w", err)
+    }
+
+    if _, err := tx.ExecContext(ctx, `DELETE FROM match_score_event WHERE match_id = $1`, matchID); err != nil {
+        return fmt.Errorf("failed to clear score events: %w", err)
+    }
+
+    for _, e := range tl.events {
+        var minute, second any
+        if e.timed {
+            minute, second = e.minute, e.second
+        }
+
+        if _, err := tx.ExecContext(ctx, `
+            INSERT INTO match_score_event (match_id, play_index, minute, second, team_id, event_type,
+                player_id, points, home_score, away_score)
+            VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+        `,
+            matchID, e.playIndex, minute, second, e.teamID, string(e.eventType),
+            nullUUID(e.playerID), e.points, e.homeScore, e.awayScore,
+        ); err != nil {
+            return fmt.Errorf("failed to store score event %d: %w", e.playIndex, err)
+        }
+    }
+
+    return tx.Commit()
+}
+
+// SetTeamLists stores the players named for a match. When the match already
+// has a team list, players that were dropped from it are removed and the ones
+// brought in are marked as late changes.
 func (db *DB) SetTeamLists(ctx context.Context, matchID uuid.UUID, homeTeamList, awayTeamList []*Player) error {
 	tx, err := db.Conn.BeginTx(ctx, nil)
 	if err != nil {
@@ -191,59 +444,666 @@ func (db *DB) SetTeamLists(ctx context.Context, matchID uuid.UUID, homeTeamList,
 	}
 	defer tx.Rollback()
 
+    previous := map[string]string{}
+    rows, err := tx.QueryContext(ctx, `SELECT player_id, team FROM match_player WHERE match_id = $1`, matchID)
+    if err != nil {
+        return fmt.Errorf("failed to get previous team lists: %w", err)
+    }
+    for rows.Next() {
+        var playerID, team string
+        if err := rows.Scan(&playerID, &team); err != nil {
+            rows.Close()
+            return err
+        }
+        previous[playerID] = team
+    }
+    rows.Close()
+    if err := rows.Err(); err != nil {
+        return err
+    }
+
     // helper to link player to match
-    insertMatchPlayer := func(playerID, team string) error {
+    insertMatchPlayer := func(playerID, team string, p *Player, lateChange bool) error {
         _, err := tx.ExecContext(ctx,
-            `INSERT INTO match_player (match_id, player_id, team)
-             VALUES ($1, $2, $3)
-             ON CONFLICT DO NOTHING`, // so we don't double insert
-            matchID, playerID, team,
+            `INSERT INTO match_player (match_id, player_id, team, position, number, role, captain, vice_captain, late_change)
+             VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+             ON CONFLICT (match_id, player_id)
+             DO UPDATE SET
+                team = EXCLUDED.team,
+                position = EXCLUDED.position,
+                number = EXCLUDED.number,
+                role = EXCLUDED.role,
+                captain = EXCLUDED.captain,
+                vice_captain = EXCLUDED.vice_captain,
+                late_change = match_player.late_change OR EXCLUDED.late_change`,
+            matchID, playerID, team, p.position, p.number, string(p.role), p.captain, p.viceCaptain, lateChange,
         )
         return err
     }
 
-    for _, p := range homeTeamList {
-        pid, err := db.InsertPlayer(ctx, matchID, p.nameFirst, p.nameLast, p.position, p.number)
-        if err != nil {
-            return err
+    type namedPlayer struct {
+        id string
+        team string
+        player *Player
+    }
+    var named []namedPlayer
+    current := map[string]string{}
+    for _, side := range []struct {
+        team string
+        list []*Player
+    }{{"home", homeTeamList}, {"away", awayTeamList}} {
+        for _, p := range side.list {
+            pid, err := insertPlayer(ctx, tx, matchID, side.team, p)
+            if err != nil {
+                return err
+            }
+            named = append(named, namedPlayer{pid, side.team, p})
+            current[pid] = side.team
         }
-        if err := insertMatchPlayer(pid, "home"); err != nil {
+    }
+
+    late, dropped := teamListChanges(previous, current)
+
+    for _, n := range named {
+        if err := insertMatchPlayer(n.id, n.team, n.player, n.player.lateChange || late[n.id]); err != nil {
             return err
         }
+        if n.player.playerStats != nil {
+            if err := setPlayerMatchStats(ctx, tx, matchID, n.id, n.player.playerStats); err != nil {
+                return err
+            }
+        }
+    }
+
+    for _, pid := range dropped {
+        if _, err := tx.ExecContext(ctx, `DELETE FROM player_match_stats WHERE match_id = $1 AND player_id = $2`, matchID, pid); err != nil {
+            return fmt.Errorf("failed to remove dropped player stats: %w", err)
+        }
+        if _, err := tx.ExecContext(ctx, `DELETE FROM match_player WHERE match_id = $1 AND player_id = $2`, matchID, pid); err != nil {
+            return fmt.Errorf("failed to remove dropped player: %w", err)
+        }
+    }
+
+    return tx.Commit()
+}
+
+// InsertPlayer resolves p to its match independent player row, keyed by the
+// NRL profile id when the team list links one and by name otherwise. A name
+// only matches a player who has played for the same club, as the side of
+// matchID given by team, in the same or an adjacent season, and is settled
+// between namesakes by canonicalPlayer as ReconcilePlayers settles it. A player without
+// a name is refused, as every blank slot would otherwise become one player.
+func (db *DB) InsertPlayer(ctx context.Context, matchID uuid.UUID, team string, p *Player) (string, error) {
+    return insertPlayer(ctx, db.Conn, matchID, team, p)
+}
+
+func insertPlayer(ctx context.Context, q querier, matchID uuid.UUID, team string, p *Player) (string, error) {
+    var id string
+
+    if strings.TrimSpace(p.nameFirst) == "" && strings.TrimSpace(p.nameLast) == "" {
+        return "", fmt.Errorf("insert player failed: player has no name")
+    }
+
+    if p.nrlID != "" {
+        err := q.QueryRowContext(ctx, `
+            INSERT INTO player (nrl_id, profile_url, name_first, name_last)
+            VALUES ($1, $2, $3, $4)
+            ON CONFLICT (nrl_id)
+            DO UPDATE SET
+                profile_url = EXCLUDED.profile_url,
+                name_first = EXCLUDED.name_first,
+                name_last = EXCLUDED.name_last
+            RETURNING id;
+        `, p.nrlID, p.profileURL, p.nameFirst, p.nameLast).Scan(&id)
+        if err != nil {
+            return "", fmt.Errorf("insert player failed: %w", err)
+        }
+
+        return id, nil
+    }
+
+    // the namesakes are ordered by first appearance, as ReconcilePlayers
+    // orders them
+    rows, err := q.QueryContext(ctx, `
+        WITH namesake AS (
+            SELECT DISTINCT p.id, COALESCE(p.nrl_id, '') AS nrl_id
+            FROM
+                match m
+                JOIN round r ON r.id = m.round_id
+                JOIN season s ON s.id = r.season_id,
+                player p
+                JOIN match_player mp ON mp.player_id = p.id
+                JOIN match om ON om.id = mp.match_id
+                JOIN round orr ON orr.id = om.round_id
+                JOIN season os ON os.id = orr.season_id
+            WHERE
+                m.id = $1
+                AND lower(p.name_first) = lower($3) AND lower(p.name_last) = lower($4)
+                AND CASE WHEN mp.team = 'home' THEN COALESCE(om.home_team_id, om.home_team) ELSE COALESCE(om.away_team_id, om.away_team) END
+                    = CASE WHEN $2 = 'home' THEN COALESCE(m.home_team_id, m.home_team) ELSE COALESCE(m.away_team_id, m.away_team) END
+                AND abs(substring(s.year from '^\d{4}$')::int - substring(os.year from '^\d{4}$')::int) <= 1
+        )
+        SELECT n.id, n.nrl_id
+        FROM
+            namesake n
+            JOIN match_player mp ON mp.player_id = n.id
+            JOIN match m ON m.id = mp.match_id
+            JOIN round r ON r.id = m.round_id
+            JOIN season s ON s.id = r.season_id
+        WHERE s.year ~ '^\d{4}$'
+        GROUP BY n.id, n.nrl_id
+        ORDER BY
+            min(substring(s.year from '^\d{4}$')::int),
+            min(m.kickoff_at) NULLS LAST,
+            n.id
+    `, matchID, team, p.nameFirst, p.nameLast)
+    if err != nil {
+        return "", fmt.Errorf("find player failed: %w", err)
+    }
+
+    var namesakes []playerCandidate
+    for rows.Next() {
+        var c playerCandidate
+        if err := rows.Scan(&c.id, &c.nrlID); err != nil {
+            rows.Close()
+            return "", fmt.Errorf("find player failed: %w", err)
+        }
+        namesakes = append(namesakes, c)
+    }
+    rows.Close()
+    if err := rows.Err(); err != nil {
+        return "", fmt.Errorf("find player failed: %w", err)
+    }
+
+    if id, ok := canonicalPlayer(namesakes); ok {
+        return id, nil
+    }
+
+    err = q.QueryRowContext(ctx, `
+        INSERT INTO player (name_first, name_last)
+        VALUES ($1, $2)
+        RETURNING id;
+    `, p.nameFirst, p.nameLast).Scan(&id)
+    if err != nil {
+        return "", fmt.Errorf("insert player failed: %w", err)
+    }
+
+    return id, nil
+}
+
+type playerCandidate struct {
+    id string
+    nrlID string
+}
+
+// canonicalPlayer picks the player a name-only appearance belongs to from the
+// namesakes that could be them, ordered by first appearance: the single
+// profiled one when there is one, none when more than one profiled player
+// shares the name, or otherwise the first to appear.
+func canonicalPlayer(namesakes []playerCandidate) (string, bool) {
+    var profiled []playerCandidate
+    for _, c := range namesakes {
+        if c.nrlID != "" {
+            profiled = append(profiled, c)
+        }
+    }
+
+    switch {
+    case len(profiled) == 1:
+        return profiled[0].id, true
+    case len(profiled) == 0 && len(namesakes) > 0:
+        return namesakes[0].id, true
+    }
+
+    return "", false
+}
+
+// ReconcilePlayers merges duplicate player rows. A row without a profile id
+// is folded into a row sharing its name that played for the same club in the
+// same or an adjacent season: the single profiled one when there is one, or
+// otherwise the unprofiled one that appeared first, by season and then
+// kickoff. Rows with profile ids are never merged, and a name shared by more
+// than one such profiled player is left alone as they can't be told apart.
+func (db *DB) ReconcilePlayers(ctx context.Context) (int, error) {
+    rows, err := db.Conn.QueryContext(ctx, `
+        SELECT
+            p.id,
+            lower(p.name_first),
+            lower(p.name_last),
+            COALESCE(p.nrl_id, ''),
+            CASE WHEN mp.team = 'home' THEN COALESCE(m.home_team_id, m.home_team) ELSE COALESCE(m.away_team_id, m.away_team) END,
+            substring(s.year from '^\d{4}$')::int
+        FROM
+            player p
+            JOIN match_player mp ON mp.player_id = p.id
+            JOIN match m ON m.id = mp.match_id
+            JOIN round r ON r.id = m.round_id
+            JOIN season s ON s.id = r.season_id
+        WHERE s.year ~ '^\d{4}$'
+        ORDER BY
+            min(substring(s.year from '^\d{4}$')::int) OVER (PARTITION BY p.id),
+            min(m.kickoff_at) OVER (PARTITION BY p.id) NULLS LAST,
+            p.id
+    `)
+    if err != nil {
+        return 0, err
+    }
+
+    type playerRow struct {
+        id string
+        nrlID string
+        // seasons played for each club
+        clubs map[string][]int
+    }
+
+    byName := map[string][]*playerRow{}
+    byID := map[string]*playerRow{}
+    for rows.Next() {
+        var (
+            id, first, last, nrlID, club string
+            season int
+        )
+        if err := rows.Scan(&id, &first, &last, &nrlID, &club, &season); err != nil {
+            rows.Close()
+            return 0, err
+        }
+        p, ok := byID[id]
+        if !ok {
+            p = &playerRow{id: id, nrlID: nrlID, clubs: map[string][]int{}}
+            byID[id] = p
+            key := first + " " + last
+            byName[key] = append(byName[key], p)
+        }
+        p.clubs[club] = append(p.clubs[club], season)
+    }
+    rows.Close()
+    if err := rows.Err(); err != nil {
+        return 0, err
+    }
+
+    overlap := func(a, b *playerRow) bool {
+        for club, seasons := range a.clubs {
+            for _, x := range seasons {
+                for _, y := range b.clubs[club] {
+                    if x-y <= 1 && y-x <= 1 {
+                        return true
+                    }
+                }
+            }
+        }
+        return false
+    }
+
+    merges := map[string]string{}
+    for _, players := range byName {
+        for i, p := range players {
+            if p.nrlID != "" {
+                continue
+            }
+
+            // unprofiled rows only fold into one that appeared before them
+            var namesakes []playerCandidate
+            for j, o := range players {
+                if j == i || !overlap(p, o) || (o.nrlID == "" && j > i) {
+                    continue
+                }
+                namesakes = append(namesakes, playerCandidate{id: o.id, nrlID: o.nrlID})
+            }
+
+            if id, ok := canonicalPlayer(namesakes); ok {
+                merges[p.id] = id
+            }
+        }
+    }
+
+    // a row folded into another unprofiled row ends up wherever that one goes
+    for dup, canonical := range merges {
+        for {
+            next, ok := merges[canonical]
+            if !ok {
+                break
+            }
+            canonical = next
+        }
+        merges[dup] = canonical
+    }
+
+    tx, err := db.Conn.BeginTx(ctx, nil)
+    if err != nil {
+        return 0, err
     }
+    defer tx.Rollback()
+
+    for dup, canonical := range merges {
+        // a duplicate can't be moved into a match the canonical row already
+        // appears in, so drop those rows first
+        if _, err := tx.ExecContext(ctx, `
+            DELETE FROM player_match_stats d
+            USING player_match_stats c
+            WHERE d.player_id = $1 AND c.player_id = $2 AND d.match_id = c.match_id
+        `, dup, canonical); err != nil {
+            return 0, fmt.Errorf("failed to merge player stats: %w", err)
+        }
+        if _, err := tx.ExecContext(ctx, `
+            DELETE FROM match_player d
+            USING match_player c
+            WHERE d.player_id = $1 AND c.player_id = $2 AND d.match_id = c.match_id
+        `, dup, canonical); err != nil {
+            return 0, fmt.Errorf("failed to merge player appearances: %w", err)
+        }
 
-    for _, p := range awayTeamList {
-        pid, err := db.InsertPlayer(ctx, matchID, p.nameFirst, p.nameLast, p.position, p.number)
+        if _, err := tx.ExecContext(ctx, `UPDATE player_match_stats SET player_id = $2 WHERE player_id = $1`, dup, canonical); err != nil {
+            return 0, fmt.Errorf("failed to merge player stats: %w", err)
+        }
+        if _, err := tx.ExecContext(ctx, `UPDATE match_player SET player_id = $2 WHERE player_id = $1`, dup, canonical); err != nil {
+            return 0, fmt.Errorf("failed to merge player appearances: %w", err)
+        }
+        if _, err := tx.ExecContext(ctx, `UPDATE play_by_play SET player_id = $2 WHERE player_id = $1`, dup, canonical); err != nil {
+            return 0, fmt.Errorf("failed to merge player plays: %w", err)
+        }
+        if _, err := tx.ExecContext(ctx, `UPDATE play_by_play SET other_player_id = $2 WHERE other_player_id = $1`, dup, canonical); err != nil {
+            return 0, fmt.Errorf("failed to merge player plays: %w", err)
+        }
+        if _, err := tx.ExecContext(ctx, `UPDATE match_score_event SET player_id = $2 WHERE player_id = $1`, dup, canonical); err != nil {
+            return 0, fmt.Errorf("failed to merge player scoring: %w", err)
+        }
+        if _, err := tx.ExecContext(ctx, `UPDATE match_score_timeline SET first_try_player_id = $2 WHERE first_try_player_id = $1`, dup, canonical); err != nil {
+            return 0, fmt.Errorf("failed to merge player scoring: %w", err)
+        }
+        if _, err := tx.ExecContext(ctx, `DELETE FROM player WHERE id = $1`, dup); err != nil {
+            return 0, fmt.Errorf("failed to delete duplicate player: %w", err)
+        }
+    }
+
+    if err := tx.Commit(); err != nil {
+        return 0, err
+    }
+
+    return len(merges), nil
+}
+
+func (db *DB) SetPlayerMatchStats(ctx context.Context, matchID uuid.UUID, playerID string, ps *PlayerStats) error {
+    return setPlayerMatchStats(ctx, db.Conn, matchID, playerID, ps)
+}
+
+func setPlayerMatchStats(ctx context.Context, q querier, matchID uuid.UUID, playerID string, ps *PlayerStats) error {
+    query := `
+        INSERT INTO player_match_stats (
+            match_id, player_id,
+            minutes_played, points, tries, conversions,
+            conversion_attempts, penalty_goals, goal_conversion_rate, field_goals_1,
+            field_goals_2, runs, run_meters, kick_return_meters,
+            post_contact_meters, line_breaks, line_break_assists, try_assists,
+            line_engaged_runs, tackle_breaks, hit_ups, avg_play_the_ball_speed,
+            dummy_half_runs, dummy_half_run_meters, one_on_one_steals, offloads,
+            dummy_passes, passes, receipts, passes_to_run_ratio,
+            tackle_efficiency, tackles_made, missed_tackles, ineffective_tackles,
+            intercepts, kicks_defused, kicks, kick_meters,
+            forced_drop_outs, bomb_kicks, grubbers, forty_twenty,
+            twenty_forty, cross_field_kicks, kicked_dead, errors,
+            handling_errors, one_on_one_lost, penalties, ruck_infringements,
+            inside_10_metres, on_report, sin_bins, send_offs
+        )
+        VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18,$19,$20,$21,$22,$23,$24,$25,$26,$27,$28,$29,$30,$31,$32,$33,$34,$35,$36,$37,$38,$39,$40,$41,$42,$43,$44,$45,$46,$47,$48,$49,$50,$51,$52,$53,$54)
+        ON CONFLICT (match_id, player_id)
+        DO UPDATE SET
+            minutes_played = EXCLUDED.minutes_played,
+            points = EXCLUDED.points,
+            tries = EXCLUDED.tries,
+            conversions = EXCLUDED.conversions,
+            conversion_attempts = EXCLUDED.conversion_attempts,
+            penalty_goals = EXCLUDED.penalty_goals,
+            goal_conversion_rate = EXCLUDED.goal_conversion_rate,
+            field_goals_1 = EXCLUDED.field_goals_1,
+            field_goals_2 = EXCLUDED.field_goals_2,
+            runs = EXCLUDED.runs,
+            run_meters = EXCLUDED.run_meters,
+            kick_return_meters = EXCLUDED.kick_return_meters,
+            post_contact_meters = EXCLUDED.post_contact_meters,
+            line_breaks = EXCLUDED.line_breaks,
+            line_break_assists = EXCLUDED.line_break_assists,
+            try_assists = EXCLUDED.try_assists,
+            line_engaged_runs = EXCLUDED.line_engaged_runs,
+            tackle_breaks = EXCLUDED.tackle_breaks,
+            hit_ups = EXCLUDED.hit_ups,
+            avg_play_the_ball_speed = EXCLUDED.avg_play_the_ball_speed,
+            dummy_half_runs = EXCLUDED.dummy_half_runs,
+            dummy_half_run_meters = EXCLUDED.dummy_half_run_meters,
+            one_on_one_steals = EXCLUDED.one_on_one_steals,
+            offloads = EXCLUDED.offloads,
+            dummy_passes = EXCLUDED.dummy_passes,
+            passes = EXCLUDED.passes,
+            receipts = EXCLUDED.receipts,
+            passes_to_run_ratio = EXCLUDED.passes_to_run_ratio,
+            tackle_efficiency = EXCLUDED.tackle_efficiency,
+            tackles_made = EXCLUDED.tackles_made,
+            missed_tackles = EXCLUDED.missed_tackles,
+            ineffective_tackles = EXCLUDED.ineffective_tackles,
+            intercepts = EXCLUDED.intercepts,
+            kicks_defused = EXCLUDED.kicks_defused,
+            kicks = EXCLUDED.kicks,
+            kick_meters = EXCLUDED.kick_meters,
+            forced_drop_outs = EXCLUDED.forced_drop_outs,
+            bomb_kicks = EXCLUDED.bomb_kicks,
+            grubbers = EXCLUDED.grubbers,
+            forty_twenty = EXCLUDED.forty_twenty,
+            twenty_forty = EXCLUDED.twenty_forty,
+            cross_field_kicks = EXCLUDED.cross_field_kicks,
+            kicked_dead = EXCLUDED.kicked_dead,
+            errors = EXCLUDED.errors,
+            handling_errors = EXCLUDED.handling_errors,
+            one_on_one_lost = EXCLUDED.one_on_one_lost,
+            penalties = EXCLUDED.penalties,
+            ruck_infringements = EXCLUDED.ruck_infringements,
+            inside_10_metres = EXCLUDED.inside_10_metres,
+            on_report = EXCLUDED.on_report,
+            sin_bins = EXCLUDED.sin_bins,
+            send_offs = EXCLUDED.send_offs
+    `
+
+    _, err := q.ExecContext(ctx, query,
+        matchID, playerID,
+        ps.minutesPlayed, ps.points, ps.tries, ps.conversions,
+        ps.conversionAttempted, ps.penGoals, ps.conversionRate, ps.feildGoal1,
+        ps.feildGoal2, ps.runs, ps.runMeters, ps.kickReturnMeters,
+        ps.postContactMeters, ps.lineBreaks, ps.lineBreakAssists, ps.tryAssists,
+        ps.lineEngagedRuns, ps.tackleBreaks, ps.hitUps, ps.AvgPlayBallSpeed,
+        ps.dummyHalfRuns, ps.dummyHalfRunMeters, ps.oneOnOneSteal, ps.offloads,
+        ps.dummyPasses, ps.passes, ps.receipts, ps.passesToRunRatio,
+        ps.tackleEff, ps.tacklesMade, ps.tacklesMissed, ps.ineffectiveTackles,
+        ps.intercepts, ps.kicksDefused, ps.kicks, ps.kickMeters,
+        ps.forcedDropOuts, ps.bombKicks, ps.grubbers, ps.fourtyTwenty,
+        ps.twentyFourty, ps.crossFieldKicks, ps.kicksDead, ps.errors,
+        ps.handlingErr, ps.OneOnOneLost, ps.pen, ps.ruckInf,
+        ps.inside10, ps.onReport, ps.sinBins, ps.sendOffs,
+    )
+    if err != nil {
+        return fmt.Errorf("failed to set player match stats: %w", err)
+    }
+
+    return nil
+}
+
+func (db *DB) SetMatchOfficials(ctx context.Context, matchID uuid.UUID, officials []*MatchOffical) error {
+    tx, err := db.Conn.BeginTx(ctx, nil)
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
+    // officials have no natural key, so replace the whole set for the match
+    _, err = tx.ExecContext(ctx, `DELETE FROM match_official WHERE match_id = $1`, matchID)
+    if err != nil {
+        return fmt.Errorf("failed to clear match officials: %w", err)
+    }
+
+    for _, o := range officials {
+        _, err := tx.ExecContext(ctx, `
+            INSERT INTO match_official (match_id, name_first, name_last, role)
+            VALUES ($1, $2, $3, $4)
+        `, matchID, o.nameFirst, o.nameLast, o.role)
         if err != nil {
-            return err
+            return fmt.Errorf("failed to insert match official: %w", err)
         }
-        if err := insertMatchPlayer(pid, "away"); err != nil {
-            return err
+    }
+
+    return tx.Commit()
+}
+
+// SaveScrapeErrors stores the failures collected during a scrape of a
+// competition.
+func (db *DB) SaveScrapeErrors(ctx context.Context, compID int, runID uuid.UUID, errs []ScrapeError) error {
+    tx, err := db.Conn.BeginTx(ctx, nil)
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
+    for _, e := range errs {
+        var matchID any
+        if e.matchID != uuid.Nil {
+            matchID = e.matchID
+        }
+
+        _, err := tx.ExecContext(ctx, `
+            INSERT INTO scrape_error (competition_id, run_id, stage, url, season, round_index, match_id, message, occurred_at)
+            VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+        `, compID, nullUUID(runID), string(e.stage), e.url, e.season, e.roundIndex, matchID, e.err.Error(), e.at)
+        if err != nil {
+            return fmt.Errorf("failed to insert scrape error: %w", err)
         }
     }
 
     return tx.Commit()
 }
 
-func (db *DB) InsertPlayer(ctx context.Context, matchID uuid.UUID, first, last, position string, number int) (string, error) {
+// nullUUID stores uuid.Nil as NULL for optional references.
+func nullUUID(id uuid.UUID) any {
+    if id == uuid.Nil {
+        return nil
+    }
+
+    return id
+}
+
+type ScrapeRun struct {
+    id uuid.UUID
+    compID int
+    seasons string
+    rounds string
+    status string
+    startedAt time.Time
+}
+
+func (db *DB) CreateScrapeRun(ctx context.Context, compID int, seasons, rounds string, resumedFrom uuid.UUID) (uuid.UUID, error) {
+    var runID uuid.UUID
+
+    err := db.Conn.QueryRowContext(ctx, `
+        INSERT INTO scrape_run (id, competition_id, seasons, rounds, resumed_from, status)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        RETURNING id
+    `, uuid.New(), compID, seasons, rounds, nullUUID(resumedFrom), RunRunning).Scan(&runID)
+    if err != nil {
+        return uuid.Nil, fmt.Errorf("failed to create scrape run: %w", err)
+    }
+
+    return runID, nil
+}
+
+func (db *DB) FinishScrapeRun(ctx context.Context, runID uuid.UUID, status string) error {
+    _, err := db.Conn.ExecContext(ctx, `
+        UPDATE scrape_run
+        SET status = $1,
+            finished_at = now()
+        WHERE id = $2
+    `, status, runID)
+    if err != nil {
+        return fmt.Errorf("failed to finish scrape run: %w", err)
+    }
+
+    return nil
+}
+
+func (db *DB) SetScrapeTask(ctx context.Context, runID uuid.UUID, t Task, stage Stage, status TaskStatus, message string) error {
+    _, err := db.Conn.ExecContext(ctx, `
+        INSERT INTO scrape_task (run_id, task_key, kind, season, round_index, round_id, match_id, url, stage, status, message)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+        ON CONFLICT (run_id, task_key, stage)
+        DO UPDATE
+        SET status = EXCLUDED.status,
+            message = EXCLUDED.message,
+            updated_at = now()
+    `, runID, t.key(), string(t.kind), t.season, t.roundIndex, nullUUID(t.roundID), nullUUID(t.matchID), t.url, string(stage), string(status), message)
+    if err != nil {
+        return fmt.Errorf("failed to set scrape task: %w", err)
+    }
+
+    return nil
+}
+
+// GetScrapeRun loads a run by id, or when runID is uuid.Nil the latest run of
+// the competition.
+func (db *DB) GetScrapeRun(ctx context.Context, compID int, runID uuid.UUID) (ScrapeRun, error) {
     query := `
-        INSERT INTO player (match_id, name_first, name_last, position, number)
-        VALUES ($1, $2, $3, $4, $5)
-        ON CONFLICT (match_id, name_first, name_last) DO NOTHING
-        RETURNING id;
+        SELECT id, competition_id, seasons, rounds, status, started_at
+        FROM scrape_run
+        WHERE id = $1
     `
+    args := []any{runID}
+    if runID == uuid.Nil {
+        query = `
+            SELECT id, competition_id, seasons, rounds, status, started_at
+            FROM scrape_run
+            WHERE competition_id = $1
+            ORDER BY started_at DESC
+            LIMIT 1
+        `
+        args = []any{compID}
+    }
 
-    var id string
-    err := db.Conn.QueryRowContext(ctx, query, matchID, first, last, position, number).Scan(&id)
+    var r ScrapeRun
+    err := db.Conn.QueryRowContext(ctx, query, args...).Scan(&r.id, &r.compID, &r.seasons, &r.rounds, &r.status, &r.startedAt)
     if err == sql.ErrNoRows {
-        // Means conflict happened and DO NOTHING triggered
-        return "", nil
+        return r, fmt.Errorf("no scrape run to resume")
     }
     if err != nil {
-        return "", fmt.Errorf("insert player failed: %w", err)
+        return r, fmt.Errorf("failed to load scrape run: %w", err)
     }
 
-    return id, nil
+    return r, nil
+}
+
+// GetUnfinishedTasks returns every task of a run with a stage that is not done,
+// seasons first, then rounds, then matches.
+func (db *DB) GetUnfinishedTasks(ctx context.Context, runID uuid.UUID) ([]Task, error) {
+    rows, err := db.Conn.QueryContext(ctx, `
+        SELECT DISTINCT ON (kind, task_key) kind, season, round_index, round_id, match_id, url
+        FROM scrape_task
+        WHERE run_id = $1
+          AND status <> $2
+        ORDER BY kind DESC, task_key
+    `, runID, string(TaskDone))
+    if err != nil {
+        return nil, fmt.Errorf("failed to load unfinished tasks: %w", err)
+    }
+    defer rows.Close()
+
+    var tasks []Task
+    for rows.Next() {
+        var t Task
+        var kind string
+        var roundID, matchID uuid.NullUUID
+        if err := rows.Scan(&kind, &t.season, &t.roundIndex, &roundID, &matchID, &t.url); err != nil {
+            return nil, err
+        }
+
+        t.kind = TaskKind(kind)
+        t.roundID = roundID.UUID
+        t.matchID = matchID.UUID
+        tasks = append(tasks, t)
+    }
+
+    return tasks, rows.Err()
 }
 
 func (db *DB) setScore(ctx context.Context, matchID uuid.UUID, column string, score int) error {
@@ -274,14 +1134,86 @@ func (db *DB) SetAwayScore(ctx context.Context, matchID uuid.UUID, score int) er
     return db.setScore(ctx, matchID, "away_score", score)
 }
 
+func (db *DB) SetMatchStatus(ctx context.Context, matchID uuid.UUID, status MatchStatus) error {
+    res, err := db.Conn.ExecContext(ctx, `UPDATE match SET status = $1 WHERE id = $2`, string(status), matchID)
+    if err != nil {
+        return fmt.Errorf("failed to update status: %w", err)
+    }
+
+    rowsAffected, err := res.RowsAffected()
+    if err != nil {
+        return fmt.Errorf("failed to check rows affected: %w", err)
+    }
+
+    if rowsAffected == 0 {
+        return fmt.Errorf("no match found with id %s", matchID)
+    }
+
+    return nil
+}
+
+// SetByes replaces the teams that have the bye in a round. Names team_alias
+// doesn't know are left out, and returned as ErrUnknownTeam errors once the
+// rest are stored.
+func (db *DB) SetByes(ctx context.Context, roundID uuid.UUID, season string, teams []string) error {
+    // resolved up front, the transaction holds its own connection
+    resolved, unknown, err := resolveByes(teams, func(name string) (Team, error) {
+        return db.ResolveTeam(ctx, name, season)
+    })
+    if err != nil {
+        return err
+    }
+
+    tx, err := db.Conn.BeginTx(ctx, nil)
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
+    if _, err := tx.ExecContext(ctx, `DELETE FROM round_bye WHERE round_id = $1`, roundID); err != nil {
+        return fmt.Errorf("failed to clear byes: %w", err)
+    }
+
+    for _, team := range resolved {
+        if _, err := tx.ExecContext(ctx, `
+            INSERT INTO round_bye (round_id, team_id)
+            VALUES ($1, $2)
+            ON CONFLICT DO NOTHING
+        `, roundID, team.id); err != nil {
+            return fmt.Errorf("failed to store bye for %s: %w", team.id, err)
+        }
+    }
+
+    if err := tx.Commit(); err != nil {
+        return err
+    }
+
+    return unknown
+}
+
+// SetLocation stores the location as scraped and links the match to the
+// venue it resolves to. An unknown venue still keeps the location text, and
+// an ErrUnknownVenue error naming it is returned after the write.
 func (db *DB) SetLocation(ctx context.Context, matchID uuid.UUID, location string) error {
+    var venueID sql.NullString
+    for _, name := range venueNames(location) {
+        err := db.Conn.QueryRowContext(ctx, `SELECT venue_id FROM venue_alias WHERE alias = $1`, name).Scan(&venueID)
+        if err == nil {
+            break
+        }
+        if err != sql.ErrNoRows {
+            return fmt.Errorf("failed to resolve venue %q: %w", location, err)
+        }
+    }
+
     query := `
         UPDATE match
-        SET location = $1
-        WHERE id = $2;
+        SET location = $1,
+            venue_id = $2
+        WHERE id = $3;
     `
 
-    res, err := db.Conn.ExecContext(ctx, query, location, matchID)
+    res, err := db.Conn.ExecContext(ctx, query, location, venueID, matchID)
     if err != nil {
         return fmt.Errorf("failed to update location: %w", err)
     }
@@ -295,19 +1227,98 @@ func (db *DB) SetLocation(ctx context.Context, matchID uuid.UUID, location strin
         return fmt.Errorf("no match found with id %s", matchID)
     }
 
+    if !venueID.Valid {
+        return fmt.Errorf("%w %q, add it to data/venues.json", ErrUnknownVenue, location)
+    }
+
     return nil
 }
 
-func (db *DB) SetDatePlayed(ctx context.Context, matchID uuid.UUID, dateStr string) error {
+// SeedVenues loads the bundled venue registry into the database, replacing
+// the home ground mapping, and links any match whose location now resolves.
+func (db *DB) SeedVenues(ctx context.Context) error {
+    data, err := LoadVenueData()
+    if err != nil {
+        return err
+    }
+
+    tx, err := db.Conn.BeginTx(ctx, nil)
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
+    for _, v := range data.Venues {
+        _, err := tx.ExecContext(ctx, `
+            INSERT INTO venue (id, name, city, state, country, latitude, longitude, capacity, surface)
+            VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+            ON CONFLICT (id)
+            DO UPDATE
+            SET name = EXCLUDED.name,
+                city = EXCLUDED.city,
+                state = EXCLUDED.state,
+                country = EXCLUDED.country,
+                latitude = EXCLUDED.latitude,
+                longitude = EXCLUDED.longitude,
+                capacity = EXCLUDED.capacity,
+                surface = EXCLUDED.surface
+        `, v.ID, v.Name, v.City, v.State, v.Country, v.Latitude, v.Longitude, v.Capacity, v.Surface)
+        if err != nil {
+            return fmt.Errorf("failed to seed venue %s: %w", v.ID, err)
+        }
+
+        for _, alias := range append([]string{v.Name}, v.Aliases...) {
+            _, err := tx.ExecContext(ctx, `
+                INSERT INTO venue_alias (alias, venue_id)
+                VALUES ($1, $2)
+                ON CONFLICT (alias) DO UPDATE SET venue_id = EXCLUDED.venue_id
+            `, normaliseName(alias), v.ID)
+            if err != nil {
+                return fmt.Errorf("failed to seed venue alias %q: %w", alias, err)
+            }
+        }
+    }
+
+    _, err = tx.ExecContext(ctx, `DELETE FROM team_home_ground`)
+    if err != nil {
+        return fmt.Errorf("failed to clear home grounds: %w", err)
+    }
+
+    for _, h := range data.HomeGrounds {
+        _, err := tx.ExecContext(ctx, `
+            INSERT INTO team_home_ground (team_id, venue_id, first_season, last_season)
+            VALUES ($1, $2, $3, $4)
+        `, h.Team, h.Venue, h.FirstSeason, h.LastSeason)
+        if err != nil {
+            return fmt.Errorf("failed to seed home ground %s at %s: %w", h.Team, h.Venue, err)
+        }
+    }
+
+    _, err = tx.ExecContext(ctx, `
+        UPDATE match m
+        SET venue_id = a.venue_id
+        FROM venue_alias a
+        WHERE m.venue_id IS NULL
+          AND m.location <> ''
+          AND a.alias = lower(regexp_replace(regexp_replace(trim(m.location), '[.''’]', '', 'g'), '\s+', ' ', 'g'))
+    `)
+    if err != nil {
+        return fmt.Errorf("failed to link match venues: %w", err)
+    }
+
+    return tx.Commit()
+}
+
+func (db *DB) SetKickoff(ctx context.Context, matchID uuid.UUID, kickoff time.Time) error {
     query := `
         UPDATE match
-        SET date_played = $1
+        SET kickoff_at = $1
         WHERE id = $2;
     `
 
-    res, err := db.Conn.ExecContext(ctx, query, dateStr, matchID)
+    res, err := db.Conn.ExecContext(ctx, query, kickoff, matchID)
     if err != nil {
-        return fmt.Errorf("failed to update date_played: %w", err)
+        return fmt.Errorf("failed to update kickoff_at: %w", err)
     }
 
     rowsAffected, err := res.RowsAffected()
@@ -575,8 +1586,8 @@ func (db *DB) SetNegPlayStats(ctx context.Context, matchID uuid.UUID, ng *NegPla
     return err
 }
 
-func (db *DB) GetCompetition(id int) ([]Competition, error) {
-	rows, err := db.Conn.Query("SELECT id, name FROM competition WHERE id = $1", id)
+func (db *DB) GetCompetition(ctx context.Context, id int) ([]Competition, error) {
+	rows, err := db.Conn.QueryContext(ctx, "SELECT id, name FROM competition WHERE id = $1", id)
 	if err != nil {
 		return nil, err
 	}
@@ -589,7 +1600,7 @@ func (db *DB) GetCompetition(id int) ([]Competition, error) {
             return []Competition{}, err
         }
     
-        c.seasons, err = db.GetSeasons(c.id)
+        c.seasons, err = db.GetSeasons(ctx, c.id)
         if err != nil {
             return []Competition{}, err
         }
@@ -602,8 +1613,8 @@ func (db *DB) GetCompetition(id int) ([]Competition, error) {
 	return comps, nil
 }
 
-func (db *DB) GetSeasons(compId int) ([]*Season, error) {
-	rows, err := db.Conn.Query("SELECT year, id FROM season WHERE competition_id = $1", compId)
+func (db *DB) GetSeasons(ctx context.Context, compId int) ([]*Season, error) {
+	rows, err := db.Conn.QueryContext(ctx, "SELECT year, id FROM season WHERE competition_id = $1", compId)
 	if err != nil {
 		return []*Season{}, err
 	}
@@ -615,7 +1626,7 @@ func (db *DB) GetSeasons(compId int) ([]*Season, error) {
 		if err := rows.Scan(&s.year, &s.id); err != nil {
 			return []*Season{}, err
 		}
-		s.rounds, err = db.GetRounds(s.id)
+		s.rounds, err = db.GetRounds(ctx, s.id)
 		seasons = append(seasons, &s)
 	}
 	if err := rows.Err(); err != nil {
@@ -625,95 +1636,568 @@ func (db *DB) GetSeasons(compId int) ([]*Season, error) {
 	return seasons, nil
 }
 
-func (db *DB) GetRounds(seasonId uuid.UUID) ([]*Round, error) {
-	rows, err := db.Conn.Query("SELECT id, start_day, end_day, round_name, round_index FROM round WHERE season_id = $1", seasonId)
+func (db *DB) GetRounds(ctx context.Context, seasonId uuid.UUID) ([]*Round, error) {
+	// a round runs from its first kickoff to its last
+	rows, err := db.Conn.QueryContext(ctx, `
+		SELECT
+			r.id,
+			MIN(m.kickoff_at),
+			MAX(m.kickoff_at),
+			r.round_name,
+			r.round_index
+		FROM
+			round r
+			LEFT JOIN match m ON m.round_id = r.id
+		WHERE
+			r.season_id = $1
+		GROUP BY
+			r.id
+		ORDER BY
+			r.round_index
+	`, seasonId)
 	if err != nil {
 		return []*Round{}, err
 	}
 	defer rows.Close()
 
-	var rounds []*Round
+	var rounds []*Round
+	for rows.Next() {
+		var r Round
+		var start, end sql.NullTime
+		if err := rows.Scan(&r.id, &start, &end, &r.roundName, &r.roundIndex); err != nil {
+			return []*Round{}, err
+		}
+		r.start, r.end = start.Time, end.Time
+		r.matches, err = db.GetMatches(ctx, r.id)
+		if err != nil {
+			return []*Round{}, err
+		}
+		r.ladder, err = db.GetLadder(ctx, r.id)
+		if err != nil {
+			return []*Round{}, err
+		}
+		r.byes, err = db.GetByes(ctx, r.id)
+		if err != nil {
+			return []*Round{}, err
+		}
+		rounds = append(rounds, &r)
+	}
+	if err := rows.Err(); err != nil {
+		return []*Round{}, err
+	}
+
+	return rounds, nil
+}
+
+func (db *DB) GetByes(ctx context.Context, roundId uuid.UUID) ([]string, error) {
+	rows, err := db.Conn.QueryContext(ctx, `SELECT team_id FROM round_bye WHERE round_id = $1 ORDER BY team_id`, roundId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var byes []string
+	for rows.Next() {
+		var team string
+		if err := rows.Scan(&team); err != nil {
+			return nil, err
+		}
+		byes = append(byes, team)
+	}
+
+	return byes, rows.Err()
+}
+
+func (db *DB) GetLadder(ctx context.Context, roundId uuid.UUID) ([]ladder.Standing, error) {
+	rows, err := db.Conn.QueryContext(ctx, `
+		SELECT
+			position,
+			team_id,
+			played,
+			won,
+			drawn,
+			lost,
+			byes,
+			points,
+			points_for,
+			points_against
+		FROM
+			ladder_snapshot
+		WHERE
+			round_id = $1
+		ORDER BY
+			position
+	`, roundId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var standings []ladder.Standing
+	for rows.Next() {
+		var s ladder.Standing
+		if err := rows.Scan(&s.Position, &s.Team, &s.Played, &s.Won, &s.Drawn, &s.Lost, &s.Byes, &s.Points, &s.For, &s.Against); err != nil {
+			return nil, err
+		}
+		standings = append(standings, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return standings, nil
+}
+
+func (db *DB) GetMatches(ctx context.Context, roundId uuid.UUID) ([]*Match, error) {
+	rows, err := db.Conn.QueryContext(ctx, `
+		SELECT
+			id,
+			home_team,
+			away_team,
+			COALESCE(home_team_id, ''),
+			COALESCE(away_team_id, ''),
+			home_score,
+			away_score,
+			location,
+			COALESCE(venue_id, ''),
+			kickoff_at,
+			status,
+			weather
+		FROM
+			match
+		WHERE
+			round_id = $1
+		ORDER BY
+			kickoff_at
+	`, roundId)
+
+	if err != nil {
+		return []*Match{}, err
+	}
+	defer rows.Close()
+
+	var matches []*Match
+	for rows.Next() {
+		var m Match
+		var kickoff sql.NullTime
+		var status string
+		if err := rows.Scan(
+				&m.id,
+				&m.homeTeam,
+				&m.awayTeam,
+				&m.homeTeamID,
+				&m.awayTeamID,
+				&m.homeScore,
+				&m.awayScore,
+				&m.location,
+				&m.venueID,
+				&kickoff,
+				&status,
+				&m.weather,
+			); err != nil {
+			return []*Match{}, err
+		}
+		m.kickoff = kickoff.Time
+		m.status = MatchStatus(status)
+	
+        m.stats = db.GetMatchStats(ctx, m.id)
+		m.homeTeamList, m.awayTeamList, err = db.GetTeamLists(ctx, m.id)
+		if err != nil {
+			return []*Match{}, err
+		}
+		m.matchOfficals, err = db.GetMatchOfficials(ctx, m.id)
+		if err != nil {
+			return []*Match{}, err
+		}
+		m.playByPlay, err = db.GetPlayByPlay(ctx, m.id)
+		if err != nil {
+			return []*Match{}, err
+		}
+		m.scoreTimeline, err = db.GetScoreTimeline(ctx, m.id)
+		if err != nil {
+			return []*Match{}, err
+		}
+		matches = append(matches, &m)
+	}
+	if err := rows.Err(); err != nil {
+		return []*Match{}, err
+	}
+
+	return matches, nil
+}
+
+func (db *DB) GetPlayByPlay(ctx context.Context, matchId uuid.UUID) ([]*Play, error) {
+	rows, err := db.Conn.QueryContext(ctx, `
+		SELECT
+			play_index,
+			time,
+			play,
+			COALESCE(team, ''),
+			COALESCE(notes, ''),
+			event_type,
+			minute,
+			second,
+			COALESCE(team_id, ''),
+			player_id,
+			other_player_id,
+			points
+		FROM
+			play_by_play
+		WHERE
+			match_id = $1
+		ORDER BY
+			play_index
+	`, matchId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var plays []*Play
+	for rows.Next() {
+		var (
+			p Play
+			eventType string
+			minute, second sql.NullInt64
+			playerID, otherPlayerID uuid.NullUUID
+		)
+		if err := rows.Scan(&p.index, &p.time, &p.play, &p.team, &p.notes, &eventType, &minute, &second, &p.teamID, &playerID, &otherPlayerID, &p.points); err != nil {
+			return nil, err
+		}
+		p.eventType = EventType(eventType)
+		p.minute, p.second, p.timed = int(minute.Int64), int(second.Int64), minute.Valid
+		p.playerID, p.otherPlayerID = playerID.UUID, otherPlayerID.UUID
+
+		plays = append(plays, &p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return plays, nil
+}
+
+// GetScoreTimeline returns nil for a match without one.
+func (db *DB) GetScoreTimeline(ctx context.Context, matchId uuid.UUID) (*ScoreTimeline, error) {
+	var (
+		tl ScoreTimeline
+		halfTimeHome, halfTimeAway sql.NullInt64
+		firstTryPlayerID uuid.NullUUID
+	)
+	err := db.Conn.QueryRowContext(ctx, `
+		SELECT
+			half_time_home_score,
+			half_time_away_score,
+			home_score,
+			away_score,
+			biggest_home_lead,
+			biggest_away_lead,
+			lead_changes,
+			comeback,
+			COALESCE(first_try_team_id, ''),
+			first_try_player_id,
+			reliable
+		FROM
+			match_score_timeline
+		WHERE
+			match_id = $1
+	`, matchId).Scan(
+		&halfTimeHome,
+		&halfTimeAway,
+		&tl.homeScore,
+		&tl.awayScore,
+		&tl.biggestHomeLead,
+		&tl.biggestAwayLead,
+		&tl.leadChanges,
+		&tl.comeback,
+		&tl.firstTryTeamID,
+		&firstTryPlayerID,
+		&tl.reliable,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	tl.halfTimeKnown = halfTimeHome.Valid && halfTimeAway.Valid
+	tl.halfTimeHome, tl.halfTimeAway = int(halfTimeHome.Int64), int(halfTimeAway.Int64)
+	tl.firstTryPlayerID = firstTryPlayerID.UUID
+
+	rows, err := db.Conn.QueryContext(ctx, `
+		SELECT
+			play_index,
+			minute,
+			second,
+			team_id,
+			event_type,
+			player_id,
+			points,
+			home_score,
+			away_score
+		FROM
+			match_score_event
+		WHERE
+			match_id = $1
+		ORDER BY
+			home_score + away_score, play_index
+	`, matchId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			e ScoreEvent
+			eventType string
+			minute, second sql.NullInt64
+			playerID uuid.NullUUID
+		)
+		if err := rows.Scan(&e.playIndex, &minute, &second, &e.teamID, &eventType, &playerID, &e.points, &e.homeScore, &e.awayScore); err != nil {
+			return nil, err
+		}
+		e.eventType = EventType(eventType)
+		e.minute, e.second, e.timed = int(minute.Int64), int(second.Int64), minute.Valid
+		e.playerID = playerID.UUID
+
+		tl.events = append(tl.events, &e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &tl, nil
+}
+
+func (db *DB) GetTeamLists(ctx context.Context, matchId uuid.UUID) ([]*Player, []*Player, error) {
+	rows, err := db.Conn.QueryContext(ctx, `
+		SELECT
+			p.id,
+			p.name_first,
+			p.name_last,
+			COALESCE(p.nrl_id, ''),
+			p.profile_url,
+			mp.position,
+			mp.number,
+			mp.role,
+			mp.captain,
+			mp.vice_captain,
+			mp.late_change,
+			mp.team
+		FROM
+			match_player mp
+			JOIN player p ON p.id = mp.player_id
+		WHERE
+			mp.match_id = $1
+		ORDER BY
+			mp.team, mp.number
+	`, matchId)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	var home, away []*Player
 	for rows.Next() {
-		var r Round
-		if err := rows.Scan(&r.id, &r.startDay, &r.endDay, &r.roundName, &r.roundIndex); err != nil {
-			return []*Round{}, err
+		var (
+			p Player
+			role string
+			team string
+		)
+		if err := rows.Scan(&p.id, &p.nameFirst, &p.nameLast, &p.nrlID, &p.profileURL, &p.position, &p.number, &role, &p.captain, &p.viceCaptain, &p.lateChange, &team); err != nil {
+			return nil, nil, err
+		}
+
+		p.role = PlayerRole(role)
+
+		p.playerStats, err = db.GetPlayerMatchStats(ctx, matchId, p.id)
+		if err != nil && err != sql.ErrNoRows {
+			return nil, nil, err
+		}
+
+		if team == "home" {
+			home = append(home, &p)
+		} else {
+			away = append(away, &p)
 		}
-		r.matches, err = db.GetMatches(r.id)
-		rounds = append(rounds, &r)
 	}
 	if err := rows.Err(); err != nil {
-		return []*Round{}, err
+		return nil, nil, err
 	}
 
-	return rounds, nil
+	return home, away, nil
 }
+func (db *DB) GetPlayerMatchStats(ctx context.Context, matchId, playerID uuid.UUID) (*PlayerStats, error) {
+	stats := &PlayerStats{}
 
-func (db *DB) GetMatches(roundId uuid.UUID) ([]*Match, error) {
-	rows, err := db.Conn.Query(`
+	err := db.Conn.QueryRowContext(ctx, `
 		SELECT
-			id,
-			home_team,
-			away_team,
-			home_score,
-			away_score,
-			location,
-			kickoff_time,
-			date_played,
-			weather
+			minutes_played,
+			points,
+			tries,
+			conversions,
+			conversion_attempts,
+			penalty_goals,
+			goal_conversion_rate,
+			field_goals_1,
+			field_goals_2,
+			runs,
+			run_meters,
+			kick_return_meters,
+			post_contact_meters,
+			line_breaks,
+			line_break_assists,
+			try_assists,
+			line_engaged_runs,
+			tackle_breaks,
+			hit_ups,
+			avg_play_the_ball_speed,
+			dummy_half_runs,
+			dummy_half_run_meters,
+			one_on_one_steals,
+			offloads,
+			dummy_passes,
+			passes,
+			receipts,
+			passes_to_run_ratio,
+			tackle_efficiency,
+			tackles_made,
+			missed_tackles,
+			ineffective_tackles,
+			intercepts,
+			kicks_defused,
+			kicks,
+			kick_meters,
+			forced_drop_outs,
+			bomb_kicks,
+			grubbers,
+			forty_twenty,
+			twenty_forty,
+			cross_field_kicks,
+			kicked_dead,
+			errors,
+			handling_errors,
+			one_on_one_lost,
+			penalties,
+			ruck_infringements,
+			inside_10_metres,
+			on_report,
+			sin_bins,
+			send_offs
 		FROM
-			match
+			player_match_stats
 		WHERE
-			round_id = $1
-	`, roundId)
+			match_id = $1 AND player_id = $2
+	`, matchId, playerID).Scan(
+		&stats.minutesPlayed,
+		&stats.points,
+		&stats.tries,
+		&stats.conversions,
+		&stats.conversionAttempted,
+		&stats.penGoals,
+		&stats.conversionRate,
+		&stats.feildGoal1,
+		&stats.feildGoal2,
+		&stats.runs,
+		&stats.runMeters,
+		&stats.kickReturnMeters,
+		&stats.postContactMeters,
+		&stats.lineBreaks,
+		&stats.lineBreakAssists,
+		&stats.tryAssists,
+		&stats.lineEngagedRuns,
+		&stats.tackleBreaks,
+		&stats.hitUps,
+		&stats.AvgPlayBallSpeed,
+		&stats.dummyHalfRuns,
+		&stats.dummyHalfRunMeters,
+		&stats.oneOnOneSteal,
+		&stats.offloads,
+		&stats.dummyPasses,
+		&stats.passes,
+		&stats.receipts,
+		&stats.passesToRunRatio,
+		&stats.tackleEff,
+		&stats.tacklesMade,
+		&stats.tacklesMissed,
+		&stats.ineffectiveTackles,
+		&stats.intercepts,
+		&stats.kicksDefused,
+		&stats.kicks,
+		&stats.kickMeters,
+		&stats.forcedDropOuts,
+		&stats.bombKicks,
+		&stats.grubbers,
+		&stats.fourtyTwenty,
+		&stats.twentyFourty,
+		&stats.crossFieldKicks,
+		&stats.kicksDead,
+		&stats.errors,
+		&stats.handlingErr,
+		&stats.OneOnOneLost,
+		&stats.pen,
+		&stats.ruckInf,
+		&stats.inside10,
+		&stats.onReport,
+		&stats.sinBins,
+		&stats.sendOffs,
+	)
 
 	if err != nil {
-		return []*Match{}, err
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+func (db *DB) GetMatchOfficials(ctx context.Context, matchId uuid.UUID) ([]*MatchOffical, error) {
+	rows, err := db.Conn.QueryContext(ctx, `
+		SELECT
+			name_first,
+			name_last,
+			COALESCE(role, '')
+		FROM
+			match_official
+		WHERE
+			match_id = $1
+	`, matchId)
+	if err != nil {
+		return []*MatchOffical{}, err
 	}
 	defer rows.Close()
 
-	var matches []*Match
+	var officials []*MatchOffical
 	for rows.Next() {
-		var m Match
-		if err := rows.Scan(
-				&m.id,
-				&m.homeTeam,
-				&m.awayTeam,
-				&m.homeScore,
-				&m.awayScore,
-				&m.location,
-				&m.kickoffTime,
-				&m.datePlayed,
-				&m.weather,
-			); err != nil {
-			return []*Match{}, err
+		var o MatchOffical
+		if err := rows.Scan(&o.nameFirst, &o.nameLast, &o.role); err != nil {
+			return []*MatchOffical{}, err
 		}
-	
-        m.stats = db.GetMatchStats(m.id)
-		matches = append(matches, &m)
+		officials = append(officials, &o)
 	}
 	if err := rows.Err(); err != nil {
-		return []*Match{}, err
+		return []*MatchOffical{}, err
 	}
 
-	return matches, nil
+	return officials, nil
 }
 
-func (db *DB) GetMatchStats(matchId uuid.UUID) (s *MatchStats) {
+func (db *DB) GetMatchStats(ctx context.Context, matchId uuid.UUID) (s *MatchStats) {
     s = &MatchStats{}
-    s.posAndComp, _ = db.GetPosAndCompStats(matchId)
-	s.attack, _ = db.GetAttackStats(matchId)
-	s.passing, _ = db.GetPassingStats(matchId)
-	s.kicking, _ = db.GetKickingStats(matchId)
-	s.defence, _ = db.GetDefenceStats(matchId)
-	s.negPlays, _ = db.GetNegPlaysStats(matchId)
+    s.posAndComp, _ = db.GetPosAndCompStats(ctx, matchId)
+	s.attack, _ = db.GetAttackStats(ctx, matchId)
+	s.passing, _ = db.GetPassingStats(ctx, matchId)
+	s.kicking, _ = db.GetKickingStats(ctx, matchId)
+	s.defence, _ = db.GetDefenceStats(ctx, matchId)
+	s.negPlays, _ = db.GetNegPlaysStats(ctx, matchId)
 
 	return
 }
 
-func (db *DB) GetPosAndCompStats(matchId uuid.UUID) (*PosAndComp, error) {
+func (db *DB) GetPosAndCompStats(ctx context.Context, matchId uuid.UUID) (*PosAndComp, error) {
 	stats := &PosAndComp{}
 
-	err := db.Conn.QueryRow(`
+	err := db.Conn.QueryRowContext(ctx, `
 		SELECT
             home_pos_per,
 			away_pos_per,
@@ -745,10 +2229,10 @@ func (db *DB) GetPosAndCompStats(matchId uuid.UUID) (*PosAndComp, error) {
 	return stats, nil
 }
 
-func (db *DB) GetAttackStats(matchId uuid.UUID) (*Attack, error) {
+func (db *DB) GetAttackStats(ctx context.Context, matchId uuid.UUID) (*Attack, error) {
 	stats := &Attack{}
 
-	err := db.Conn.QueryRow(`
+	err := db.Conn.QueryRowContext(ctx, `
 		SELECT
             home_runs,
 			away_runs,
@@ -789,7 +2273,6 @@ func (db *DB) GetAttackStats(matchId uuid.UUID) (*Attack, error) {
 		&stats.awayAvgPlayTheBallSpeed,
 	)
 
-    fmt.Println(stats, err)
 	if err != nil {
 		return &Attack{}, err
 	}
@@ -797,10 +2280,10 @@ func (db *DB) GetAttackStats(matchId uuid.UUID) (*Attack, error) {
 	return stats, nil
 }
 
-func (db *DB) GetPassingStats(matchId uuid.UUID) (*Passing, error) {
+func (db *DB) GetPassingStats(ctx context.Context, matchId uuid.UUID) (*Passing, error) {
 	stats := &Passing{}
 
-	err := db.Conn.QueryRow(`
+	err := db.Conn.QueryRowContext(ctx, `
 		SELECT
             home_offloads,
             away_offloads,
@@ -832,10 +2315,10 @@ func (db *DB) GetPassingStats(matchId uuid.UUID) (*Passing, error) {
 	return stats, nil
 }
 
-func (db *DB) GetKickingStats(matchId uuid.UUID) (*Kicking, error) {
+func (db *DB) GetKickingStats(ctx context.Context, matchId uuid.UUID) (*Kicking, error) {
 	stats := &Kicking{}
     
-	err := db.Conn.QueryRow(`
+	err := db.Conn.QueryRowContext(ctx, `
 		SELECT
             home_kicks,
             away_kicks,
@@ -875,10 +2358,10 @@ func (db *DB) GetKickingStats(matchId uuid.UUID) (*Kicking, error) {
 	return stats, nil
 }
 
-func (db *DB) GetDefenceStats(matchId uuid.UUID) (*Defence, error) {
+func (db *DB) GetDefenceStats(ctx context.Context, matchId uuid.UUID) (*Defence, error) {
 	stats := &Defence{}
 
-	err := db.Conn.QueryRow(`
+	err := db.Conn.QueryRowContext(ctx, `
 		SELECT
             home_effec_tackle,
             away_effec_tackle,
@@ -914,10 +2397,10 @@ func (db *DB) GetDefenceStats(matchId uuid.UUID) (*Defence, error) {
 	return stats, nil
 }
 
-func (db *DB) GetNegPlaysStats(matchId uuid.UUID) (*NegPlays, error) {
+func (db *DB) GetNegPlaysStats(ctx context.Context, matchId uuid.UUID) (*NegPlays, error) {
 	stats := &NegPlays{}
 
-	err := db.Conn.QueryRow(`
+	err := db.Conn.QueryRowContext(ctx, `
 		SELECT
             home_errors,
             away_errors,
@@ -952,3 +2435,673 @@ func (db *DB) GetNegPlaysStats(matchId uuid.UUID) (*NegPlays, error) {
 
 	return stats, nil
 }
+
+// Migrate applies every *.up.sql file in dir newer than the recorded schema
+// version. Versions are tracked in golang-migrate's schema_migrations table so
+// the two can be used interchangeably.
+func (db *DB) Migrate(ctx context.Context, dir string) (int, error) {
+    _, err := db.Conn.ExecContext(ctx, `
+        CREATE TABLE IF NOT EXISTS schema_migrations (
+            version BIGINT NOT NULL PRIMARY KEY,
+            dirty BOOLEAN NOT NULL
+        )
+    `)
+    if err != nil {
+        return 0, fmt.Errorf("failed to create schema_migrations: %w", err)
+    }
+
+    var (
+        current int
+        dirty bool
+    )
+    err = db.Conn.QueryRowContext(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&current, &dirty)
+    if err != nil && err != sql.ErrNoRows {
+        return 0, fmt.Errorf("failed to read schema version: %w", err)
+    }
+    if dirty {
+        return 0, fmt.Errorf("schema version %d is dirty, fix it manually before migrating", current)
+    }
+
+    files, err := filepath.Glob(filepath.Join(dir, "*.up.sql"))
+    if err != nil {
+        return 0, err
+    }
+    sort.Strings(files)
+
+    applied := 0
+    for _, file := range files {
+        version, err := strconv.Atoi(strings.SplitN(filepath.Base(file), "_", 2)[0])
+        if err != nil {
+            return applied, fmt.Errorf("migration %s has no version prefix", file)
+        }
+        if version <= current {
+            continue
+        }
+
+        content, err := os.ReadFile(file)
+        if err != nil {
+            return applied, err
+        }
+
+        tx, err := db.Conn.BeginTx(ctx, nil)
+        if err != nil {
+            return applied, err
+        }
+
+        if _, err := tx.ExecContext(ctx, string(content)); err != nil {
+            tx.Rollback()
+            return applied, fmt.Errorf("migration %s failed: %w", filepath.Base(file), err)
+        }
+
+        _, err = tx.ExecContext(ctx, `DELETE FROM schema_migrations`)
+        if err == nil {
+            _, err = tx.ExecContext(ctx, `INSERT INTO schema_migrations (version, dirty) VALUES ($1, false)`, version)
+        }
+        if err != nil {
+            tx.Rollback()
+            return applied, fmt.Errorf("failed to record schema version: %w", err)
+        }
+
+        if err := tx.Commit(); err != nil {
+            return applied, err
+        }
+        applied++
+    }
+
+    return applied, nil
+}
+
+type SeasonSummary struct {
+    year string
+    rounds int
+    matches int
+    played int
+    withStats int
+    withTeamLists int
+    withPlayByPlay int
+}
+
+// GetSeasonYears lists the seasons of a competition stored so far.
+func (db *DB) GetSeasonYears(ctx context.Context, compID int) ([]string, error) {
+    rows, err := db.Conn.QueryContext(ctx, `SELECT year FROM season WHERE competition_id = $1 ORDER BY year`, compID)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    var years []string
+    for rows.Next() {
+        var year string
+        if err := rows.Scan(&year); err != nil {
+            return nil, err
+        }
+        years = append(years, year)
+    }
+
+    return years, rows.Err()
+}
+
+// GetLadderResults returns the regular season matches of a season keyed by
+// team id, along with the ids of their rounds.
+func (db *DB) GetLadderResults(ctx context.Context, compID int, season string) ([]ladder.Result, map[int]uuid.UUID, error) {
+    rows, err := db.Conn.QueryContext(ctx, `
+        SELECT
+            r.id,
+            r.round_index,
+            r.round_name,
+            m.home_team_id,
+            m.away_team_id,
+            m.home_score,
+            m.away_score,
+            m.status
+        FROM
+            season s
+            JOIN round r ON r.season_id = s.id
+            JOIN match m ON m.round_id = r.id
+        WHERE
+            s.competition_id = $1
+            AND s.year = $2
+            AND m.home_team_id IS NOT NULL
+            AND m.away_team_id IS NOT NULL
+        ORDER BY
+            r.round_index
+    `, compID, season)
+    if err != nil {
+        return nil, nil, err
+    }
+    defer rows.Close()
+
+    var results []ladder.Result
+    roundIDs := map[int]uuid.UUID{}
+    for rows.Next() {
+        var (
+            r ladder.Result
+            roundID uuid.UUID
+            roundName string
+            status string
+        )
+        if err := rows.Scan(&roundID, &r.Round, &roundName, &r.Home, &r.Away, &r.HomeScore, &r.AwayScore, &status); err != nil {
+            return nil, nil, err
+        }
+        if !isRegularRound(roundName) {
+            continue
+        }
+        r.Played = MatchStatus(status) == StatusFullTime && r.HomeScore >= 0 && r.AwayScore >= 0
+        roundIDs[r.Round] = roundID
+        results = append(results, r)
+    }
+
+    return results, roundIDs, rows.Err()
+}
+
+// SetLadderSnapshots replaces the ladders stored for the given rounds.
+func (db *DB) SetLadderSnapshots(ctx context.Context, roundIDs map[int]uuid.UUID, snapshots []ladder.Snapshot) error {
+    tx, err := db.Conn.BeginTx(ctx, nil)
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
+    for _, roundID := range roundIDs {
+        if _, err := tx.ExecContext(ctx, `DELETE FROM ladder_snapshot WHERE round_id = $1`, roundID); err != nil {
+            return fmt.Errorf("failed to clear ladder: %w", err)
+        }
+    }
+
+    for _, snapshot := range snapshots {
+        roundID, ok := roundIDs[snapshot.Round]
+        if !ok {
+            continue
+        }
+        for _, st := range snapshot.Standings {
+            if _, err := tx.ExecContext(ctx, `
+                INSERT INTO ladder_snapshot (round_id, team_id, position, played, won, drawn, lost,
+                    byes, points, points_for, points_against, differential)
+                VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+            `,
+                roundID, st.Team, st.Position, st.Played, st.Won, st.Drawn, st.Lost,
+                st.Byes, st.Points, st.For, st.Against, st.Differential(),
+            ); err != nil {
+                return fmt.Errorf("failed to store ladder for round %d: %w", snapshot.Round, err)
+            }
+        }
+    }
+
+    return tx.Commit()
+}
+
+// neutralVenue is true for a match at a known venue that isn't one of the
+// home team's grounds that season. Teams without home grounds on record, or
+// seasons not named by a year, never have a neutral venue.
+const neutralVenue = `(
+    m.venue_id IS NOT NULL
+    AND EXISTS (
+        SELECT 1 FROM team_home_ground g
+        WHERE g.team_id = m.home_team_id
+          AND g.first_season <= substring(s.year from '^\d{4}$')::int
+          AND (g.last_season IS NULL OR g.last_season >= substring(s.year from '^\d{4}$')::int)
+    )
+    AND NOT EXISTS (
+        SELECT 1 FROM team_home_ground g
+        WHERE g.team_id = m.home_team_id
+          AND g.venue_id = m.venue_id
+          AND g.first_season <= substring(s.year from '^\d{4}$')::int
+          AND (g.last_season IS NULL OR g.last_season >= substring(s.year from '^\d{4}$')::int)
+    )
+)`
+
+// GetRatingMatches returns the played matches of a competition keyed by team
+// id, along with the ids of their rounds. With fixtures set it returns the
+// matches still to be played instead.
+func (db *DB) GetRatingMatches(ctx context.Context, compID int, fixtures bool) ([]elo.Match, map[elo.RoundKey]uuid.UUID, error) {
+    played := `m.status = 'full_time' AND m.home_score >= 0 AND m.away_score >= 0`
+    if fixtures {
+        played = `m.status IN ('scheduled', 'postponed')`
+    }
+
+    rows, err := db.Conn.QueryContext(ctx, `
+        SELECT
+            r.id,
+            s.year,
+            r.round_index,
+            m.kickoff_at,
+            m.home_team_id,
+            m.away_team_id,
+            m.home_score,
+            m.away_score,
+            `+neutralVenue+`
+        FROM
+            season s
+            JOIN round r ON r.season_id = s.id
+            JOIN match m ON m.round_id = r.id
+        WHERE
+            s.competition_id = $1
+            AND m.home_team_id IS NOT NULL
+            AND m.away_team_id IS NOT NULL
+            AND `+played+`
+        ORDER BY
+            s.year, r.round_index, m.kickoff_at
+    `, compID)
+    if err != nil {
+        return nil, nil, err
+    }
+    defer rows.Close()
+
+    var matches []elo.Match
+    roundIDs := map[elo.RoundKey]uuid.UUID{}
+    for rows.Next() {
+        var (
+            m elo.Match
+            roundID uuid.UUID
+            kickoff sql.NullTime
+        )
+        if err := rows.Scan(&roundID, &m.Season, &m.Round, &kickoff, &m.Home, &m.Away, &m.HomeScore, &m.AwayScore, &m.Neutral); err != nil {
+            return nil, nil, err
+        }
+        m.Kickoff = kickoff.Time
+        roundIDs[elo.RoundKey{Season: m.Season, Round: m.Round}] = roundID
+        matches = append(matches, m)
+    }
+
+    return matches, roundIDs, rows.Err()
+}
+
+// SetTeamRatings replaces the rating history of the given rounds.
+func (db *DB) SetTeamRatings(ctx context.Context, roundIDs map[elo.RoundKey]uuid.UUID, snapshots []elo.Snapshot) error {
+    tx, err := db.Conn.BeginTx(ctx, nil)
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
+    for _, roundID := range roundIDs {
+        if _, err := tx.ExecContext(ctx, `DELETE FROM team_rating WHERE round_id = $1`, roundID); err != nil {
+            return fmt.Errorf("failed to clear ratings: %w", err)
+        }
+    }
+
+    for _, snapshot := range snapshots {
+        roundID, ok := roundIDs[snapshot.RoundKey]
+        if !ok {
+            continue
+        }
+        for team, rating := range snapshot.Ratings {
+            if _, err := tx.ExecContext(ctx, `
+                INSERT INTO team_rating (round_id, team_id, rating)
+                VALUES ($1, $2, $3)
+            `, roundID, team, rating); err != nil {
+                return fmt.Errorf("failed to store rating of %s: %w", team, err)
+            }
+        }
+    }
+
+    return tx.Commit()
+}
+
+// GetFeatureGames returns both sides of every match of a competition with the
+// team stats recorded for them. Stats that weren't scraped are left out.
+func (db *DB) GetFeatureGames(ctx context.Context, compID int) ([]features.Game, error) {
+    var columns, joins []string
+    for _, t := range featureStats {
+        joins = append(joins, fmt.Sprintf("LEFT JOIN %[1]s ON %[1]s.match_id = m.id", t.table))
+        for _, c := range t.columns {
+            columns = append(columns, fmt.Sprintf("%[1]s.home_%[2]s, %[1]s.away_%[2]s", t.table, c))
+        }
+    }
+
+    rows, err := db.Conn.QueryContext(ctx, `
+        SELECT
+            m.id,
+            s.year,
+            r.round_index,
+            m.kickoff_at,
+            m.home_team_id,
+            m.away_team_id,
+            m.status,
+            `+strings.Join(columns, ",\n            ")+`
+        FROM
+            season s
+            JOIN round r ON r.season_id = s.id
+            JOIN match m ON m.round_id = r.id
+            `+strings.Join(joins, "\n            ")+`
+        WHERE
+            s.competition_id = $1
+            AND m.home_team_id IS NOT NULL
+            AND m.away_team_id IS NOT NULL
+        ORDER BY
+            s.year, r.round_index, m.kickoff_at
+    `, compID)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    var games []features.Game
+    for rows.Next() {
+        var (
+            home, away features.Game
+            kickoff sql.NullTime
+            status string
+        )
+        values := make([]sql.NullFloat64, 2*len(columns))
+        dest := []any{&home.MatchID, &home.Season, &home.Round, &kickoff, &home.Team, &away.Team, &status}
+        for i := range values {
+            dest = append(dest, &values[i])
+        }
+        if err := rows.Scan(dest...); err != nil {
+            return nil, err
+        }
+
+        home.Kickoff = kickoff.Time
+        home.Played = MatchStatus(status) == StatusFullTime
+        home.Opponent = away.Team
+        away.MatchID, away.Season, away.Round, away.Kickoff, away.Played, away.Opponent = home.MatchID, home.Season, home.Round, home.Kickoff, home.Played, home.Team
+        home.Stats, away.Stats = map[string]float64{}, map[string]float64{}
+
+        i := 0
+        for _, t := range featureStats {
+            for _, c := range t.columns {
+                // unscraped stats are stored as -1
+                if v := values[i]; v.Valid && v.Float64 >= 0 {
+                    home.Stats[c] = v.Float64
+                }
+                if v := values[i+1]; v.Valid && v.Float64 >= 0 {
+                    away.Stats[c] = v.Float64
+                }
+                i += 2
+            }
+        }
+        games = append(games, home, away)
+    }
+
+    return games, rows.Err()
+}
+
+// SetMatchFeatures stores the features of each team going into a match,
+// replacing what was worked out before.
+func (db *DB) SetMatchFeatures(ctx context.Context, rows []features.Row) error {
+    tx, err := db.Conn.BeginTx(ctx, nil)
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
+    for _, r := range rows {
+        f, err := json.Marshal(r.Features)
+        if err != nil {
+            return fmt.Errorf("failed to encode features of %s: %w", r.Team, err)
+        }
+        if _, err := tx.ExecContext(ctx, `
+            INSERT INTO match_features (match_id, team_id, opponent_id, games, features)
+            VALUES ($1, $2, $3, $4, $5)
+            ON CONFLICT (match_id, team_id) DO UPDATE SET
+                opponent_id = EXCLUDED.opponent_id,
+                games = EXCLUDED.games,
+                features = EXCLUDED.features
+        `, r.MatchID, r.Team, r.Opponent, r.Games, f); err != nil {
+            return fmt.Errorf("failed to store features of %s: %w", r.Team, err)
+        }
+    }
+
+    return tx.Commit()
+}
+
+// GetMatchFeatures returns the stored features of both teams of a match.
+func (db *DB) GetMatchFeatures(ctx context.Context, matchId uuid.UUID) ([]features.Row, error) {
+	rows, err := db.Conn.QueryContext(ctx, `
+		SELECT
+			team_id,
+			opponent_id,
+			games,
+			features
+		FROM
+			match_features
+		WHERE
+			match_id = $1
+		ORDER BY
+			team_id
+	`, matchId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []features.Row
+	for rows.Next() {
+		r := features.Row{MatchID: matchId}
+		var f []byte
+		if err := rows.Scan(&r.Team, &r.Opponent, &r.Games, &f); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(f, &r.Features); err != nil {
+			return nil, fmt.Errorf("failed to decode features of %s: %w", r.Team, err)
+		}
+		result = append(result, r)
+	}
+
+	return result, rows.Err()
+}
+
+// SaveBacktest stores a backtest run. The report without a season holds the
+// scores over every season and goes on the run itself.
+func (db *DB) SaveBacktest(ctx context.Context, compID int, model, params, trainUntil string, reports []backtest.Report) (uuid.UUID, error) {
+    tx, err := db.Conn.BeginTx(ctx, nil)
+    if err != nil {
+        return uuid.Nil, err
+    }
+    defer tx.Rollback()
+
+    var overall backtest.Report
+    for _, r := range reports {
+        if r.Season == "" {
+            overall = r
+        }
+    }
+
+    var runID uuid.UUID
+    err = tx.QueryRowContext(ctx, `
+        INSERT INTO backtest_run (id, competition_id, model, params, train_until, matches, accuracy, log_loss, brier, margin_mae)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+        RETURNING id
+    `, uuid.New(), compID, model, params, trainUntil, overall.Matches, overall.Accuracy, overall.LogLoss, overall.Brier, overall.MarginMAE).Scan(&runID)
+    if err != nil {
+        return uuid.Nil, fmt.Errorf("failed to create backtest run: %w", err)
+    }
+
+    for _, r := range reports {
+        if r.Season == "" {
+            continue
+        }
+        if _, err := tx.ExecContext(ctx, `
+            INSERT INTO backtest_season (run_id, season, matches, accuracy, log_loss, brier, margin_mae)
+            VALUES ($1, $2, $3, $4, $5, $6, $7)
+        `, runID, r.Season, r.Matches, r.Accuracy, r.LogLoss, r.Brier, r.MarginMAE); err != nil {
+            return uuid.Nil, fmt.Errorf("failed to store backtest of %s: %w", r.Season, err)
+        }
+
+        for _, b := range r.Calibration {
+            if _, err := tx.ExecContext(ctx, `
+                INSERT INTO backtest_calibration (run_id, season, bin_from, bin_to, matches, predicted, observed)
+                VALUES ($1, $2, $3, $4, $5, $6, $7)
+            `, runID, r.Season, b.From, b.To, b.Matches, b.Predicted, b.Observed); err != nil {
+                return uuid.Nil, fmt.Errorf("failed to store calibration of %s: %w", r.Season, err)
+            }
+        }
+    }
+
+    return runID, tx.Commit()
+}
+
+// GetTryAppearances returns every appearance in the played matches of a
+// competition with the tries scored. Tries come from the player's stats, or
+// the typed play by play when the stats weren't scraped, and appearances with
+// neither are left out.
+func (db *DB) GetTryAppearances(ctx context.Context, compID int) ([]tryscorer.Appearance, error) {
+    rows, err := db.Conn.QueryContext(ctx, `
+        SELECT
+            m.id,
+            m.kickoff_at,
+            mp.player_id,
+            CASE WHEN mp.team = 'home' THEN m.home_team_id ELSE m.away_team_id END,
+            CASE WHEN mp.team = 'home' THEN m.away_team_id ELSE m.home_team_id END,
+            CASE WHEN mp.team = 'home' THEN m.home_score ELSE m.away_score END,
+            mp.position,
+            mp.number,
+            mp.role,
+            COALESCE(ps.minutes_played, -1),
+            CASE
+                WHEN ps.tries >= 0 THEN ps.tries
+                WHEN EXISTS (SELECT 1 FROM play_by_play p WHERE p.match_id = m.id AND p.event_type = 'try') THEN (
+                    SELECT COUNT(*) FROM play_by_play p
+                    WHERE p.match_id = m.id AND p.event_type = 'try' AND p.player_id = mp.player_id
+                )
+            END
+        FROM
+            season s
+            JOIN round r ON r.season_id = s.id
+            JOIN match m ON m.round_id = r.id
+            JOIN match_player mp ON mp.match_id = m.id
+            LEFT JOIN player_match_stats ps ON ps.match_id = m.id AND ps.player_id = mp.player_id
+        WHERE
+            s.competition_id = $1
+            AND m.status = 'full_time'
+            AND m.home_team_id IS NOT NULL
+            AND m.away_team_id IS NOT NULL
+            AND m.kickoff_at IS NOT NULL
+    `, compID)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    var apps []tryscorer.Appearance
+    for rows.Next() {
+        var (
+            a tryscorer.Appearance
+            position string
+            number int
+            role string
+            tries sql.NullInt64
+        )
+        if err := rows.Scan(&a.MatchID, &a.Kickoff, &a.Player, &a.Team, &a.Opponent, &a.TeamScore, &position, &number, &role, &a.Minutes, &tries); err != nil {
+            return nil, err
+        }
+        if !tries.Valid {
+            continue
+        }
+        a.Tries = int(tries.Int64)
+        a.Position = tryPosition(position, number, PlayerRole(role))
+        apps = append(apps, a)
+    }
+
+    return apps, rows.Err()
+}
+
+// GetFixture returns a match keyed by team id, whether or not it has been
+// played.
+func (db *DB) GetFixture(ctx context.Context, matchID uuid.UUID) (elo.Match, error) {
+	var (
+		m elo.Match
+		kickoff sql.NullTime
+	)
+
+	err := db.Conn.QueryRowContext(ctx, `
+		SELECT
+			s.year,
+			r.round_index,
+			m.kickoff_at,
+			m.home_team_id,
+			m.away_team_id,
+			m.home_score,
+			m.away_score,
+			`+neutralVenue+`
+		FROM
+			season s
+			JOIN round r ON r.season_id = s.id
+			JOIN match m ON m.round_id = r.id
+		WHERE
+			m.id = $1
+			AND m.home_team_id IS NOT NULL
+			AND m.away_team_id IS NOT NULL
+	`, matchID).Scan(&m.Season, &m.Round, &kickoff, &m.Home, &m.Away, &m.HomeScore, &m.AwayScore, &m.Neutral)
+	if err != nil {
+		return elo.Match{}, err
+	}
+	m.Kickoff = kickoff.Time
+
+	return m, nil
+}
+
+// GetNamedFixtures returns the upcoming matches of a competition that have a
+// team list.
+func (db *DB) GetNamedFixtures(ctx context.Context, compID int) ([]uuid.UUID, error) {
+	rows, err := db.Conn.QueryContext(ctx, `
+		SELECT
+			m.id
+		FROM
+			season s
+			JOIN round r ON r.season_id = s.id
+			JOIN match m ON m.round_id = r.id
+		WHERE
+			s.competition_id = $1
+			AND m.status = 'scheduled'
+			AND EXISTS (SELECT 1 FROM match_player mp WHERE mp.match_id = m.id)
+		ORDER BY
+			s.year, r.round_index, m.kickoff_at
+	`, compID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// GetSeasonSummaries counts how much of each season of a competition has been
+// scraped.
+func (db *DB) GetSeasonSummaries(ctx context.Context, compID int) ([]SeasonSummary, error) {
+    rows, err := db.Conn.QueryContext(ctx, `
+        SELECT
+            s.year,
+            COUNT(DISTINCT r.id),
+            COUNT(m.id),
+            COUNT(m.id) FILTER (WHERE m.home_score >= 0 AND m.away_score >= 0),
+            COUNT(m.id) FILTER (WHERE EXISTS (SELECT 1 FROM attack a WHERE a.match_id = m.id)),
+            COUNT(m.id) FILTER (WHERE EXISTS (SELECT 1 FROM match_player mp WHERE mp.match_id = m.id)),
+            COUNT(m.id) FILTER (WHERE EXISTS (SELECT 1 FROM play_by_play p WHERE p.match_id = m.id))
+        FROM
+            season s
+            LEFT JOIN round r ON r.season_id = s.id
+            LEFT JOIN match m ON m.round_id = r.id
+        WHERE
+            s.competition_id = $1
+        GROUP BY
+            s.year
+        ORDER BY
+            s.year
+    `, compID)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    var summaries []SeasonSummary
+    for rows.Next() {
+        var s SeasonSummary
+        if err := rows.Scan(&s.year, &s.rounds, &s.matches, &s.played, &s.withStats, &s.withTeamLists, &s.withPlayByPlay); err != nil {
+            return nil, err
+        }
+        summaries = append(summaries, s)
+    }
+
+    return summaries, rows.Err()
+}
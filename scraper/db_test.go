@@ -0,0 +1,27 @@
+package main
+
+import "testing"
+
+func TestCanonicalPlayer(t *testing.T) {
+	tests := []struct {
+		name string
+		namesakes []playerCandidate
+		want string
+		ok bool
+	}{
+		{"nobody", nil, "", false},
+		{"one profiled", []playerCandidate{{id: "a"}, {id: "b", nrlID: "jack-smith"}}, "b", true},
+		// two profiled players can't be told apart by name
+		{"two profiled", []playerCandidate{{id: "a", nrlID: "jack-smith"}, {id: "b", nrlID: "jack-smith-2"}, {id: "c"}}, "", false},
+		{"first unprofiled", []playerCandidate{{id: "b"}, {id: "a"}}, "b", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := canonicalPlayer(tt.namesakes)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("canonicalPlayer() = %q, %v, want %q, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
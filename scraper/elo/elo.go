@@ -0,0 +1,174 @@
+// Package elo rates teams from match results with an Elo model.
+package elo
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+type Config struct {
+	// rating a team starts on, and the mean ratings regress to
+	Initial float64
+	K float64
+	// rating points added to the home team, not applied at neutral venues
+	HomeAdvantage float64
+	// scale the margin of victory into the update
+	MarginOfVictory bool
+	// share of the distance to the mean a rating loses between seasons
+	SeasonRegression float64
+}
+
+var DefaultConfig = Config{
+	Initial: 1500,
+	K: 30,
+	HomeAdvantage: 50,
+	MarginOfVictory: true,
+	SeasonRegression: 0.3,
+}
+
+// Match is a played match. Season and Round place it when kickoffs are
+// missing and group the rating history.
+type Match struct {
+	Season string
+	Round int
+	Kickoff time.Time
+	Home string
+	Away string
+	HomeScore int
+	AwayScore int
+	Neutral bool
+}
+
+type RoundKey struct {
+	Season string
+	Round int
+}
+
+// Snapshot holds every rating once a round has been played.
+type Snapshot struct {
+	RoundKey
+	Ratings map[string]float64
+}
+
+type Model struct {
+	cfg Config
+	ratings map[string]float64
+	season string
+}
+
+func New(cfg Config) *Model {
+	return &Model{cfg: cfg, ratings: map[string]float64{}}
+}
+
+func (m *Model) Rating(team string) float64 {
+	if r, ok := m.ratings[team]; ok {
+		return r
+	}
+
+	return m.cfg.Initial
+}
+
+// Ratings returns a copy of the current ratings.
+func (m *Model) Ratings() map[string]float64 {
+	ratings := make(map[string]float64, len(m.ratings))
+	for team, r := range m.ratings {
+		ratings[team] = r
+	}
+
+	return ratings
+}
+
+func expected(diff float64) float64 {
+	return 1 / (1 + math.Pow(10, -diff/400))
+}
+
+func (m *Model) diff(home, away string, neutral bool) float64 {
+	diff := m.Rating(home) - m.Rating(away)
+	if !neutral {
+		diff += m.cfg.HomeAdvantage
+	}
+
+	return diff
+}
+
+// HomeWinProbability is the chance the home team wins a fixture on current
+// ratings, with a draw counted as half a win.
+func (m *Model) HomeWinProbability(home, away string, neutral bool) float64 {
+	return expected(m.diff(home, away, neutral))
+}
+
+// StartSeason pulls every rating towards the mean.
+func (m *Model) StartSeason(season string) {
+	if season == m.season {
+		return
+	}
+	if m.season != "" {
+		for team, r := range m.ratings {
+			m.ratings[team] = r - (r-m.cfg.Initial)*m.cfg.SeasonRegression
+		}
+	}
+	m.season = season
+}
+
+// Update rates a played match and returns the points the home team gained.
+func (m *Model) Update(match Match) float64 {
+	m.StartSeason(match.Season)
+
+	diff := m.diff(match.Home, match.Away, match.Neutral)
+	actual := 0.5
+	switch {
+	case match.HomeScore > match.AwayScore:
+		actual = 1
+	case match.HomeScore < match.AwayScore:
+		actual = 0
+	}
+
+	multiplier := 1.0
+	if m.cfg.MarginOfVictory && actual != 0.5 {
+		// damped by the winner's rating edge so favourites running up the
+		// score don't keep inflating their rating
+		winnerDiff := diff
+		if actual == 0 {
+			winnerDiff = -diff
+		}
+		margin := math.Abs(float64(match.HomeScore - match.AwayScore))
+		multiplier = math.Log(margin+1) * 2.2 / (winnerDiff*0.001 + 2.2)
+	}
+
+	delta := m.cfg.K * multiplier * (actual - expected(diff))
+	m.ratings[match.Home] = m.Rating(match.Home) + delta
+	m.ratings[match.Away] = m.Rating(match.Away) - delta
+
+	return delta
+}
+
+// Replay rates matches round by round, by kickoff within a round, and returns
+// the model along with the ratings after each round. A postponed match stays
+// in the round it was drawn in.
+func Replay(cfg Config, matches []Match) (*Model, []Snapshot) {
+	ordered := append([]Match{}, matches...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		a, b := ordered[i], ordered[j]
+		if a.Season != b.Season {
+			return a.Season < b.Season
+		}
+		if a.Round != b.Round {
+			return a.Round < b.Round
+		}
+		return a.Kickoff.Before(b.Kickoff)
+	})
+
+	m := New(cfg)
+	var snapshots []Snapshot
+	for i, match := range ordered {
+		m.Update(match)
+
+		key := RoundKey{Season: match.Season, Round: match.Round}
+		if i == len(ordered)-1 || (RoundKey{Season: ordered[i+1].Season, Round: ordered[i+1].Round}) != key {
+			snapshots = append(snapshots, Snapshot{RoundKey: key, Ratings: m.Ratings()})
+		}
+	}
+
+	return m, snapshots
+}
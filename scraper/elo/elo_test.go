@@ -0,0 +1,174 @@
+package elo
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const tolerance = 1e-9
+
+func TestUpdate(t *testing.T) {
+	cfg := DefaultConfig
+
+	tests := []struct {
+		name string
+		match Match
+		want float64
+	}{
+		{
+			name: "neutral draw between equals",
+			match: Match{Home: "Storm", Away: "Panthers", HomeScore: 18, AwayScore: 18, Neutral: true},
+			want: 0,
+		},
+		{
+			// drawing at home is below what the home advantage expects
+			name: "home draw between equals",
+			match: Match{Home: "Storm", Away: "Panthers", HomeScore: 18, AwayScore: 18},
+			want: cfg.K * (0.5 - expected(cfg.HomeAdvantage)),
+		},
+		{
+			name: "neutral home win",
+			match: Match{Home: "Storm", Away: "Panthers", HomeScore: 12, AwayScore: 0, Neutral: true},
+			want: cfg.K * math.Log(13) * 0.5,
+		},
+		{
+			name: "neutral away win",
+			match: Match{Home: "Storm", Away: "Panthers", HomeScore: 0, AwayScore: 12, Neutral: true},
+			want: -cfg.K * math.Log(13) * 0.5,
+		},
+		{
+			name: "home win",
+			match: Match{Home: "Storm", Away: "Panthers", HomeScore: 12, AwayScore: 0},
+			want: cfg.K * math.Log(13) * 2.2 / (cfg.HomeAdvantage*0.001 + 2.2) * (1 - expected(cfg.HomeAdvantage)),
+		},
+		{
+			// the away winner overcame the home advantage, so the damping
+			// works in their favour
+			name: "away win",
+			match: Match{Home: "Storm", Away: "Panthers", HomeScore: 0, AwayScore: 12},
+			want: -cfg.K * math.Log(13) * 2.2 / (-cfg.HomeAdvantage*0.001 + 2.2) * expected(cfg.HomeAdvantage),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := New(cfg)
+			got := m.Update(tt.match)
+			if math.Abs(got-tt.want) > tolerance {
+				t.Errorf("Update = %v, want %v", got, tt.want)
+			}
+			if home, away := m.Rating("Storm"), m.Rating("Panthers"); math.Abs(home-cfg.Initial-got) > tolerance || math.Abs(away-cfg.Initial+got) > tolerance {
+				t.Errorf("ratings = %v, %v, want %v either side of %v", home, away, got, cfg.Initial)
+			}
+		})
+	}
+}
+
+func TestUpdateMarginOfVictory(t *testing.T) {
+	update := func(cfg Config, homeScore, awayScore int) float64 {
+		m := New(cfg)
+		// a strong favourite at home
+		m.ratings["Storm"] = 1800
+		return m.Update(Match{Home: "Storm", Away: "Titans", HomeScore: homeScore, AwayScore: awayScore})
+	}
+
+	cfg := DefaultConfig
+	narrow, wide := update(cfg, 14, 12), update(cfg, 50, 0)
+	if narrow <= 0 || wide <= narrow {
+		t.Errorf("favourite's gains = %v by 2, %v by 50, want positive and growing with the margin", narrow, wide)
+	}
+
+	upset := update(cfg, 12, 14)
+	if upset >= 0 {
+		t.Errorf("favourite's gain from a loss = %v, want negative", upset)
+	}
+
+	cfg.MarginOfVictory = false
+	if flat, flatWide := update(cfg, 14, 12), update(cfg, 50, 0); flat != flatWide {
+		t.Errorf("without the margin gains = %v by 2, %v by 50, want equal", flat, flatWide)
+	}
+}
+
+func TestStartSeason(t *testing.T) {
+	cfg := DefaultConfig
+	m := New(cfg)
+	m.StartSeason("2023")
+	m.ratings["Storm"] = 1600
+	m.ratings["Titans"] = 1400
+
+	// the first season has nothing to regress from, and restarting it is a
+	// no-op
+	m.StartSeason("2023")
+	if m.Rating("Storm") != 1600 {
+		t.Fatalf("rating after restarting the season = %v, want 1600", m.Rating("Storm"))
+	}
+
+	m.StartSeason("2024")
+	if got, want := m.Rating("Storm"), 1600-100*cfg.SeasonRegression; math.Abs(got-want) > tolerance {
+		t.Errorf("Storm = %v, want %v", got, want)
+	}
+	if got, want := m.Rating("Titans"), 1400+100*cfg.SeasonRegression; math.Abs(got-want) > tolerance {
+		t.Errorf("Titans = %v, want %v", got, want)
+	}
+	if got := m.Rating("Dolphins"); got != cfg.Initial {
+		t.Errorf("unrated team = %v, want %v", got, cfg.Initial)
+	}
+}
+
+func TestHomeWinProbability(t *testing.T) {
+	m := New(DefaultConfig)
+	m.ratings["Storm"] = 1620
+	m.ratings["Titans"] = 1450
+
+	if p := m.HomeWinProbability("Broncos", "Dolphins", true); p != 0.5 {
+		t.Errorf("neutral match between equals = %v, want 0.5", p)
+	}
+	if sum := m.HomeWinProbability("Storm", "Titans", true) + m.HomeWinProbability("Titans", "Storm", true); math.Abs(sum-1) > tolerance {
+		t.Errorf("neutral probabilities both ways sum to %v, want 1", sum)
+	}
+	if home, neutral := m.HomeWinProbability("Titans", "Storm", false), m.HomeWinProbability("Titans", "Storm", true); home <= neutral {
+		t.Errorf("home probability %v isn't above the neutral %v", home, neutral)
+	}
+	if p := m.HomeWinProbability("Storm", "Titans", false); p <= 0.5 || p >= 1 {
+		t.Errorf("favourite at home = %v, want between 0.5 and 1", p)
+	}
+}
+
+func TestReplay(t *testing.T) {
+	kickoff := time.Date(2024, time.March, 7, 20, 0, 0, 0, time.UTC)
+	matches := []Match{
+		{Season: "2024", Round: 2, Kickoff: kickoff.AddDate(0, 0, 7), Home: "Panthers", Away: "Titans", HomeScore: 30, AwayScore: 10},
+		{Season: "2023", Round: 27, Kickoff: kickoff.AddDate(0, -6, 0), Home: "Storm", Away: "Titans", HomeScore: 6, AwayScore: 20},
+		{Season: "2024", Round: 1, Kickoff: kickoff.Add(48 * time.Hour), Home: "Titans", Away: "Storm", HomeScore: 10, AwayScore: 12},
+		{Season: "2024", Round: 1, Kickoff: kickoff, Home: "Storm", Away: "Panthers", HomeScore: 8, AwayScore: 0},
+		// postponed to after round 2, but still rated with round 1
+		{Season: "2024", Round: 1, Kickoff: kickoff.AddDate(0, 0, 10), Home: "Panthers", Away: "Broncos", HomeScore: 18, AwayScore: 18},
+	}
+
+	model, snapshots := Replay(DefaultConfig, matches)
+
+	// the same matches updated one by one in the order they should be rated
+	want := New(DefaultConfig)
+	var wantSnapshots []Snapshot
+	for _, i := range []int{1, 3, 2, 4, 0} {
+		want.Update(matches[i])
+		switch i {
+		case 1, 4, 0:
+			wantSnapshots = append(wantSnapshots, Snapshot{RoundKey{matches[i].Season, matches[i].Round}, want.Ratings()})
+		}
+	}
+
+	if !reflect.DeepEqual(model.Ratings(), want.Ratings()) {
+		t.Errorf("ratings =\n%v\nwant\n%v", model.Ratings(), want.Ratings())
+	}
+	if !reflect.DeepEqual(snapshots, wantSnapshots) {
+		t.Errorf("snapshots =\n%v\nwant\n%v", snapshots, wantSnapshots)
+	}
+
+	// snapshots are copies, so later rounds don't change earlier ones
+	if snapshots[0].Ratings["Panthers"] != 0 {
+		t.Errorf("round 27 of 2023 has Panthers rated %v before they played", snapshots[0].Ratings["Panthers"])
+	}
+}
@@ -0,0 +1,254 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Stage names the step of the scrape an error came from.
+type Stage string
+
+const (
+	StageSeasons Stage = "seasons"
+	StageSeason Stage = "season"
+	StageRounds Stage = "rounds"
+	StageRound Stage = "round"
+	StageMatch Stage = "match"
+	StageMatchFetch Stage = "match_fetch"
+	StageMatchStats Stage = "match_stats"
+	StagePosAndComp Stage = "pos_and_comp"
+	StageAttack Stage = "attack"
+	StagePassing Stage = "passing"
+	StageKicking Stage = "kicking"
+	StageDefence Stage = "defence"
+	StageNegPlays Stage = "neg_plays"
+	StagePlayByPlay Stage = "play_by_play"
+	StageScoreTimeline Stage = "score_timeline"
+	StageTeamList Stage = "team_list"
+	StagePlayerStats Stage = "player_stats"
+	StageMatchDetails Stage = "match_details"
+	StageOfficials Stage = "officials"
+	StageVenue Stage = "venue"
+	StageReconcile Stage = "reconcile"
+	StageLadder Stage = "ladder"
+	StageRatings Stage = "ratings"
+)
+
+type ScrapeError struct {
+	stage Stage
+	url string
+	season string
+	roundIndex int
+	matchID uuid.UUID
+	err error
+	at time.Time
+}
+
+func (e ScrapeError) Error() string {
+	return fmt.Sprintf("%s %s: %v", e.stage, e.url, e.err)
+}
+
+type matchRef struct {
+	url string
+	season string
+	roundIndex int
+}
+
+// ErrorCollector gathers the failures of a scrape run so they can be stored
+// and summarised once it finishes, rather than being dropped where they occur.
+// Warnings are kept apart: they are stored and summarised too, but never fail
+// a task or the run.
+type ErrorCollector struct {
+	mu sync.Mutex
+	errs []ScrapeError
+	warnings []ScrapeError
+	matches map[uuid.UUID]matchRef
+}
+
+func NewErrorCollector() *ErrorCollector {
+	return &ErrorCollector{
+		matches: map[uuid.UUID]matchRef{},
+	}
+}
+
+// registerMatch remembers where a match came from, so errors raised while
+// parsing it only need to carry its id.
+func (c *ErrorCollector) registerMatch(matchID uuid.UUID, url string, season string, roundIndex int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.matches[matchID] = matchRef{url: url, season: season, roundIndex: roundIndex}
+}
+
+func (c *ErrorCollector) Add(e ScrapeError) {
+	c.add(&c.errs, e)
+}
+
+func (c *ErrorCollector) AddMatch(matchID uuid.UUID, stage Stage, err error) {
+	c.Add(ScrapeError{stage: stage, matchID: matchID, err: err})
+}
+
+// Warn records something worth fixing that didn't stop the data being stored.
+func (c *ErrorCollector) Warn(e ScrapeError) {
+	c.add(&c.warnings, e)
+}
+
+func (c *ErrorCollector) WarnMatch(matchID uuid.UUID, stage Stage, err error) {
+	c.Warn(ScrapeError{stage: stage, matchID: matchID, err: err})
+}
+
+func (c *ErrorCollector) add(list *[]ScrapeError, e ScrapeError) {
+	// a shutdown is reported on its own, not as a failure of every page it
+	// interrupted
+	if e.err == nil || errors.Is(e.err, context.Canceled) {
+		return
+	}
+	if e.at.IsZero() {
+		e.at = time.Now()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if ref, ok := c.matches[e.matchID]; ok && e.matchID != uuid.Nil {
+		if e.url == "" {
+			e.url = ref.url
+		}
+		if e.season == "" {
+			e.season = ref.season
+		}
+		if e.roundIndex == 0 {
+			e.roundIndex = ref.roundIndex
+		}
+	}
+
+	*list = append(*list, e)
+}
+
+// matchError joins the errors a match raised in any of the given stages.
+func (c *ErrorCollector) matchError(matchID uuid.UUID, stages ...Stage) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var errs []error
+	for _, e := range c.errs {
+		if e.matchID == matchID && slices.Contains(stages, e.stage) {
+			errs = append(errs, fmt.Errorf("%s: %w", e.stage, e.err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (c *ErrorCollector) Errors() []ScrapeError {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]ScrapeError(nil), c.errs...)
+}
+
+func (c *ErrorCollector) Warnings() []ScrapeError {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]ScrapeError(nil), c.warnings...)
+}
+
+// Err is non-nil when anything was collected, so a command that scraped with
+// failures exits non-zero once the summary has been printed.
+func (c *ErrorCollector) Err() error {
+	if n := len(c.Errors()); n > 0 {
+		return fmt.Errorf("scrape finished with %d errors", n)
+	}
+
+	return nil
+}
+
+// Summary lists the error count per stage followed by every match left
+// incomplete and the stages it is missing, then any warnings.
+func (c *ErrorCollector) Summary() string {
+	var sb strings.Builder
+	sb.WriteString(c.errorSummary())
+
+	warnings := c.Warnings()
+	if len(warnings) > 0 {
+		fmt.Fprintf(&sb, "Warnings: %d\n", len(warnings))
+		for _, w := range warnings {
+			fmt.Fprintf(&sb, "  %s %s: %v\n", w.stage, w.url, w.err)
+		}
+	}
+
+	return sb.String()
+}
+
+func (c *ErrorCollector) errorSummary() string {
+	errs := c.Errors()
+	if len(errs) == 0 {
+		return "No scrape errors.\n"
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Scrape finished with %d errors\n", len(errs))
+
+	byStage := map[Stage]int{}
+	for _, e := range errs {
+		byStage[e.stage]++
+	}
+
+	stages := make([]string, 0, len(byStage))
+	for stage := range byStage {
+		stages = append(stages, string(stage))
+	}
+	sort.Strings(stages)
+	for _, stage := range stages {
+		fmt.Fprintf(&sb, "  %-14s %d\n", stage, byStage[Stage(stage)])
+	}
+
+	type incomplete struct {
+		ref matchRef
+		stages []string
+	}
+	matches := map[uuid.UUID]*incomplete{}
+	for _, e := range errs {
+		if e.matchID == uuid.Nil {
+			continue
+		}
+		m, ok := matches[e.matchID]
+		if !ok {
+			m = &incomplete{ref: matchRef{url: e.url, season: e.season, roundIndex: e.roundIndex}}
+			matches[e.matchID] = m
+		}
+		m.stages = append(m.stages, string(e.stage))
+	}
+
+	if len(matches) > 0 {
+		list := make([]*incomplete, 0, len(matches))
+		for _, m := range matches {
+			list = append(list, m)
+		}
+		sort.Slice(list, func(i, j int) bool {
+			if list[i].ref.season != list[j].ref.season {
+				return list[i].ref.season < list[j].ref.season
+			}
+			if list[i].ref.roundIndex != list[j].ref.roundIndex {
+				return list[i].ref.roundIndex < list[j].ref.roundIndex
+			}
+			return list[i].ref.url < list[j].ref.url
+		})
+
+		fmt.Fprintf(&sb, "Incomplete matches: %d\n", len(list))
+		for _, m := range list {
+			fmt.Fprintf(&sb, "  %s round %d %s: %s\n", m.ref.season, m.ref.roundIndex, m.ref.url, strings.Join(m.stages, ", "))
+		}
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestErrorCollectorAdd(t *testing.T) {
+	errFetch := errors.New("fetch failed")
+
+	tests := []struct {
+		name string
+		err error
+		want int
+	}{
+		{"error", errFetch, 1},
+		{"nil", nil, 0},
+		{"canceled", context.Canceled, 0},
+		{"wrapped canceled", fmt.Errorf("fetch: %w", context.Canceled), 0},
+		{"deadline", context.DeadlineExceeded, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewErrorCollector()
+			c.Add(ScrapeError{stage: StageRound, err: tt.err})
+			if got := len(c.Errors()); got != tt.want {
+				t.Errorf("collected %d errors, want %d", got, tt.want)
+			}
+			if (c.Err() != nil) != (tt.want > 0) {
+				t.Errorf("Err() = %v", c.Err())
+			}
+		})
+	}
+}
+
+func TestErrorCollectorMatchError(t *testing.T) {
+	c := NewErrorCollector()
+	m := uuid.New()
+	other := uuid.New()
+
+	c.registerMatch(m, "https://www.nrl.com/draw/m/", "2024", 3)
+	c.AddMatch(m, StageAttack, errors.New("no attack table"))
+	c.AddMatch(m, StageOfficials, errors.New("no officials"))
+	c.AddMatch(other, StageKicking, errors.New("no kicking table"))
+
+	if e := c.Errors()[0]; e.url != "https://www.nrl.com/draw/m/" || e.season != "2024" || e.roundIndex != 3 {
+		t.Errorf("registered match not filled in: %+v", e)
+	}
+
+	tests := []struct {
+		name string
+		matchID uuid.UUID
+		stage Stage
+		want string
+	}{
+		{"stats", m, StageMatchStats, "attack: no attack table"},
+		{"team list", m, StageTeamList, "officials: no officials"},
+		{"clean stage", m, StagePlayByPlay, ""},
+		{"other match", other, StageMatchStats, "kicking: no kicking table"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ""
+			if err := c.matchError(tt.matchID, matchStages[tt.stage]...); err != nil {
+				got = err.Error()
+			}
+			if got != tt.want {
+				t.Errorf("matchError() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrorCollectorSummary(t *testing.T) {
+	if got := NewErrorCollector().Summary(); got != "No scrape errors.\n" {
+		t.Errorf("empty summary = %q", got)
+	}
+
+	c := NewErrorCollector()
+	m1 := uuid.New()
+	m2 := uuid.New()
+	c.registerMatch(m1, "https://www.nrl.com/draw/b/", "2024", 2)
+	c.registerMatch(m2, "https://www.nrl.com/draw/a/", "2024", 1)
+
+	c.Add(ScrapeError{stage: StageRound, url: "https://www.nrl.com/draw/", season: "2024", roundIndex: 4, err: errors.New("no matches")})
+	c.AddMatch(m1, StageAttack, errors.New("no attack table"))
+	c.AddMatch(m1, StageDefence, errors.New("no defence table"))
+	c.AddMatch(m2, StageAttack, errors.New("no attack table"))
+	c.AddMatch(m2, StageOfficials, context.Canceled)
+
+	want := strings.Join([]string{
+		"Scrape finished with 4 errors",
+		"  attack         2",
+		"  defence        1",
+		"  round          1",
+		"Incomplete matches: 2",
+		"  2024 round 1 https://www.nrl.com/draw/a/: attack",
+		"  2024 round 2 https://www.nrl.com/draw/b/: attack, defence",
+		"",
+	}, "\n")
+	if got := c.Summary(); got != want {
+		t.Errorf("Summary() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+// a match stored with a venue missing from the registry is only warned about,
+// so its ledger task still finishes and the run doesn't fail
+func TestErrorCollectorUnknownVenueWarning(t *testing.T) {
+	c := NewErrorCollector()
+	m := uuid.New()
+	c.registerMatch(m, "https://www.nrl.com/draw/m/", "2024", 1)
+	c.WarnMatch(m, StageVenue, fmt.Errorf("%w %q, add it to data/venues.json", ErrUnknownVenue, "Somewhere Oval"))
+
+	for _, stage := range taskStages(Task{kind: TaskMatch}) {
+		if err := c.matchError(m, matchStages[stage]...); err != nil {
+			t.Errorf("%s stage failed: %v", stage, err)
+		}
+	}
+	if err := c.Err(); err != nil {
+		t.Errorf("Err() = %v", err)
+	}
+	if len(c.Warnings()) != 1 {
+		t.Fatalf("%d warnings, want 1", len(c.Warnings()))
+	}
+
+	want := "No scrape errors.\nWarnings: 1\n  venue https://www.nrl.com/draw/m/: unknown venue \"Somewhere Oval\", add it to data/venues.json\n"
+	if got := c.Summary(); got != want {
+		t.Errorf("Summary() = %q, want %q", got, want)
+	}
+}
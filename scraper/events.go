@@ -0,0 +1,165 @@
+package main
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// EventType is what a play-by-play entry records, read from its free text
+// title.
+type EventType string
+
+const (
+	EventTry EventType = "try"
+	EventConversion EventType = "conversion"
+	EventConversionMissed EventType = "conversion_missed"
+	EventPenaltyGoal EventType = "penalty_goal"
+	EventPenaltyGoalMissed EventType = "penalty_goal_missed"
+	EventFieldGoal EventType = "field_goal"
+	EventTwoPointFieldGoal EventType = "two_point_field_goal"
+	EventFieldGoalMissed EventType = "field_goal_missed"
+	EventPenalty EventType = "penalty"
+	EventSetRestart EventType = "set_restart"
+	EventError EventType = "error"
+	EventSinBin EventType = "sin_bin"
+	EventSendOff EventType = "send_off"
+	EventOnReport EventType = "on_report"
+	EventInterchange EventType = "interchange"
+	EventLineBreak EventType = "line_break"
+	EventFortyTwenty EventType = "forty_twenty"
+	EventTwentyForty EventType = "twenty_forty"
+	EventCaptainsChallenge EventType = "captains_challenge"
+	EventKickOff EventType = "kick_off"
+	EventHalfTime EventType = "half_time"
+	EventFullTime EventType = "full_time"
+	EventOther EventType = "other"
+)
+
+// Points is what the event adds to the score of the team it belongs to.
+func (e EventType) Points() int {
+	switch e {
+	case EventTry:
+		return 4
+	case EventConversion, EventPenaltyGoal, EventTwoPointFieldGoal:
+		return 2
+	case EventFieldGoal:
+		return 1
+	}
+
+	return 0
+}
+
+// eventPatterns are checked in order, so titles that contain another event's
+// name ("Penalty Goal", "Conversion - Missed") come before it.
+var eventPatterns = []struct {
+	event EventType
+	words []string
+}{
+	{EventConversionMissed, []string{"conversion missed", "missed conversion", "conversion miss"}},
+	{EventConversion, []string{"conversion"}},
+	{EventPenaltyGoalMissed, []string{"penalty goal missed", "missed penalty goal", "penalty shot missed", "missed penalty shot"}},
+	{EventPenaltyGoal, []string{"penalty goal", "penalty shot"}},
+	{EventFieldGoalMissed, []string{"field goal missed", "missed field goal", "drop goal missed"}},
+	{EventTwoPointFieldGoal, []string{"2 point field goal", "2pt field goal", "two point field goal"}},
+	{EventFieldGoal, []string{"field goal", "drop goal", "1 point field goal"}},
+	{EventSinBin, []string{"sin bin", "sin binned"}},
+	{EventSendOff, []string{"send off", "sent off", "sending off"}},
+	{EventOnReport, []string{"on report", "placed on report"}},
+	{EventCaptainsChallenge, []string{"captains challenge", "captain challenge"}},
+	{EventSetRestart, []string{"set restart", "six again", "6 again"}},
+	{EventPenalty, []string{"penalty"}},
+	{EventInterchange, []string{"interchange", "replacement", "substitution"}},
+	{EventFortyTwenty, []string{"40/20", "40 20"}},
+	{EventTwentyForty, []string{"20/40", "20 40"}},
+	{EventLineBreak, []string{"line break", "linebreak"}},
+	{EventError, []string{"error", "knock on", "forward pass"}},
+	{EventHalfTime, []string{"half time", "halftime"}},
+	{EventFullTime, []string{"full time", "fulltime"}},
+	{EventKickOff, []string{"kick off", "kickoff"}},
+	{EventTry, []string{"try"}},
+}
+
+// classifyEvent reads the type of an event from its title. A try that was
+// disallowed is not a try.
+func classifyEvent(title string) EventType {
+	t := " " + strings.Join(strings.Fields(strings.NewReplacer("-", " ", "–", " ", "'", "", "’", "").Replace(strings.ToLower(title))), " ") + " "
+
+	if strings.Contains(t, " penalty try ") {
+		return EventTry
+	}
+	if strings.Contains(t, " no try ") || strings.Contains(t, " try disallowed ") {
+		return EventOther
+	}
+
+	for _, p := range eventPatterns {
+		for _, w := range p.words {
+			if strings.Contains(t, " "+w+" ") {
+				return p.event
+			}
+		}
+	}
+
+	return EventOther
+}
+
+var eventClockPattern = regexp.MustCompile(`^(\d{1,3})(?:[:.](\d{2}))?\s*'?$`)
+
+// parseEventClock reads the match clock of an event, shown either as minutes
+// ("12'") or minutes and seconds ("12:04").
+func parseEventClock(text string) (int, int, bool) {
+	match := eventClockPattern.FindStringSubmatch(strings.TrimSpace(text))
+	if match == nil {
+		return 0, 0, false
+	}
+
+	minute, _ := strconv.Atoi(match[1])
+	second, _ := strconv.Atoi(match[2])
+	if second > 59 {
+		return 0, 0, false
+	}
+
+	return minute, second, true
+}
+
+// eventPlayers finds the players named in an event's notes, in the order they
+// are mentioned. A full name always counts, a surname only when no one else in
+// the list shares it.
+func eventPlayers(notes string, players []*Player) []*Player {
+	text := " " + normaliseName(strings.NewReplacer("(", " ", ")", " ", ",", " ", ":", " ").Replace(notes)) + " "
+
+	surnames := map[string]int{}
+	for _, p := range players {
+		surnames[normaliseName(p.nameLast)]++
+	}
+
+	type mention struct {
+		at int
+		player *Player
+	}
+	var mentions []mention
+	for _, p := range players {
+		last := normaliseName(p.nameLast)
+		if last == "" {
+			continue
+		}
+
+		at := strings.Index(text, " "+normaliseName(p.nameFirst+" "+p.nameLast)+" ")
+		if at < 0 && surnames[last] == 1 {
+			at = strings.Index(text, " "+last+" ")
+		}
+		if at >= 0 {
+			mentions = append(mentions, mention{at: at, player: p})
+		}
+	}
+
+	sort.SliceStable(mentions, func(i, j int) bool { return mentions[i].at < mentions[j].at })
+
+	found := make([]*Player, 0, len(mentions))
+	for _, m := range mentions {
+		found = append(found, m.player)
+	}
+
+	return found
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestClassifyEvent(t *testing.T) {
+	tests := []struct {
+		title string
+		want EventType
+	}{
+		{"Try", EventTry},
+		{"Penalty Try", EventTry},
+		{"Video Referee - Try Awarded", EventTry},
+		{"No Try", EventOther},
+		{"Try Disallowed", EventOther},
+		{"Conversion", EventConversion},
+		{"Conversion - Missed", EventConversionMissed},
+		{"Conversion Miss", EventConversionMissed},
+		{"Penalty Goal", EventPenaltyGoal},
+		{"Penalty Shot", EventPenaltyGoal},
+		{"Penalty Goal - Missed", EventPenaltyGoalMissed},
+		{"1 Point Field Goal", EventFieldGoal},
+		{"2 Point Field Goal", EventTwoPointFieldGoal},
+		{"Field Goal - Missed", EventFieldGoalMissed},
+		{"Penalty", EventPenalty},
+		{"Penalty - Ruck Infringement", EventPenalty},
+		{"Set Restart", EventSetRestart},
+		{"Six Again", EventSetRestart},
+		{"Error", EventError},
+		{"Knock On", EventError},
+		{"Sin Bin", EventSinBin},
+		{"Send Off", EventSendOff},
+		{"On Report", EventOnReport},
+		{"Interchange", EventInterchange},
+		{"HIA Replacement", EventInterchange},
+		{"Line Break", EventLineBreak},
+		{"40/20", EventFortyTwenty},
+		{"20/40", EventTwentyForty},
+		{"Captain's Challenge", EventCaptainsChallenge},
+		{"Captain’s Challenge - Successful", EventCaptainsChallenge},
+		{"Kick Off", EventKickOff},
+		{"Half Time", EventHalfTime},
+		{"Full Time", EventFullTime},
+		{"Goal Line Drop Out", EventOther},
+		{"", EventOther},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			if got := classifyEvent(tt.title); got != tt.want {
+				t.Errorf("classifyEvent(%q) = %s, want %s", tt.title, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseEventClock(t *testing.T) {
+	tests := []struct {
+		text string
+		minute int
+		second int
+		ok bool
+	}{
+		{"0'", 0, 0, true},
+		{"12'", 12, 0, true},
+		{" 7' ", 7, 0, true},
+		{"12:04", 12, 4, true},
+		{"39.59", 39, 59, true},
+		{"80'", 80, 0, true},
+		// golden point runs past 80
+		{"103'", 103, 0, true},
+		{"12:60", 0, 0, false},
+		{"HT", 0, 0, false},
+		{"", 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			minute, second, ok := parseEventClock(tt.text)
+			if minute != tt.minute || second != tt.second || ok != tt.ok {
+				t.Errorf("parseEventClock(%q) = %d, %d, %v, want %d, %d, %v", tt.text, minute, second, ok, tt.minute, tt.second, tt.ok)
+			}
+		})
+	}
+}
+
+func TestEventPlayers(t *testing.T) {
+	tom := &Player{nameFirst: "Tom", nameLast: "Trbojevic"}
+	jake := &Player{nameFirst: "Jake", nameLast: "Trbojevic"}
+	daly := &Player{nameFirst: "Daly", nameLast: "Cherry-Evans"}
+	jaydn := &Player{nameFirst: "Jaydn", nameLast: "Su'A"}
+	davvy := &Player{nameFirst: "Davvy", nameLast: "Moale"}
+	cameron := &Player{nameFirst: "Cameron", nameLast: "Murray"}
+	players := []*Player{tom, jake, daly, jaydn, davvy, cameron}
+
+	tests := []struct {
+		name string
+		notes string
+		want []*Player
+	}{
+		{"full name", "Tom Trbojevic", []*Player{tom}},
+		{"full name with a shared surname", "Jake Trbojevic (Tackle Break)", []*Player{jake}},
+		{"ambiguous surname", "Trbojevic", []*Player{}},
+		{"ambiguous initial", "J. Trbojevic", []*Player{}},
+		{"unique surname", "Cherry-Evans", []*Player{daly}},
+		{"curly apostrophe", "Jaydn Su’A", []*Player{jaydn}},
+		{"mention order", "Off: Cameron Murray, On: Davvy Moale", []*Player{cameron, davvy}},
+		{"nobody named", "Ball lost in a two man tackle", []*Player{}},
+		{"surname inside another word", "Murrays", []*Player{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := eventPlayers(tt.notes, players); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("eventPlayers(%q) = %v, want %v", tt.notes, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractPlays(t *testing.T) {
+	plays := extractPlays(fixtureDoc(t, matchFixture))
+
+	want := []struct {
+		team string
+		eventType EventType
+		points int
+		minute int
+		second int
+		notes string
+	}{
+		{"", EventKickOff, 0, 0, 0, ""},
+		{"Sea Eagles", EventTry, 4, 6, 0, "Tom Trbojevic"},
+		{"Sea Eagles", EventConversionMissed, 0, 7, 0, "Reuben Garrick"},
+		{"Rabbitohs", EventPenaltyGoal, 2, 12, 40, "Latrell Mitchell"},
+		{"Rabbitohs", EventInterchange, 0, 22, 0, "On: Davvy Moale Off: Cameron Murray"},
+		{"", EventHalfTime, 0, 40, 0, ""},
+		{"Sea Eagles", EventFieldGoal, 1, 78, 0, "Daly Cherry-Evans"},
+		{"", EventFullTime, 0, 80, 0, ""},
+	}
+
+	if len(plays) != len(want) {
+		t.Fatalf("got %d plays, want %d", len(plays), len(want))
+	}
+	for i, w := range want {
+		p := plays[i]
+		if p.index != i || !p.timed {
+			t.Errorf("play %d: index %d, timed %v", i, p.index, p.timed)
+		}
+		if p.team != w.team || p.eventType != w.eventType || p.points != w.points || p.notes != w.notes {
+			t.Errorf("play %d = %q %s %d %q, want %q %s %d %q", i, p.team, p.eventType, p.points, p.notes, w.team, w.eventType, w.points, w.notes)
+		}
+		if p.minute != w.minute || p.second != w.second {
+			t.Errorf("play %d clock = %d:%02d, want %d:%02d", i, p.minute, p.second, w.minute, w.second)
+		}
+	}
+}
@@ -0,0 +1,689 @@
+package main
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ExportSchemaVersion is bumped whenever a field in the export is renamed,
+// removed or changes meaning, so consumers can tell old files apart.
+const ExportSchemaVersion = 2
+
+type Export struct {
+	SchemaVersion int `json:"schemaVersion"`
+	Competitions []CompetitionExport `json:"competitions"`
+}
+
+type CompetitionExport struct {
+	ID int `json:"id"`
+	Name string `json:"name"`
+	Seasons []SeasonExport `json:"seasons"`
+}
+
+type SeasonExport struct {
+	Year string `json:"year"`
+	Rounds []RoundExport `json:"rounds"`
+}
+
+type RoundExport struct {
+	Round string `json:"round"`
+	RoundIndex int `json:"roundIndex"`
+	Start *time.Time `json:"start"`
+	End *time.Time `json:"end"`
+	Matches []MatchExport `json:"matches"`
+	Byes []string `json:"byes"`
+	Ladder []LadderExport `json:"ladder"`
+}
+
+type LadderExport struct {
+	Position int `json:"position"`
+	TeamID string `json:"teamId"`
+	Played int `json:"played"`
+	Won int `json:"won"`
+	Drawn int `json:"drawn"`
+	Lost int `json:"lost"`
+	Byes int `json:"byes"`
+	Points int `json:"points"`
+	For int `json:"for"`
+	Against int `json:"against"`
+	Differential int `json:"differential"`
+}
+
+type MatchExport struct {
+	HomeTeam string `json:"homeTeam"`
+	HomeTeamID string `json:"homeTeamId"`
+	HomeScore int `json:"homeScore"`
+	HomeTeamList []PlayerExport `json:"homeTeamList"`
+
+	AwayTeam string `json:"awayTeam"`
+	AwayTeamID string `json:"awayTeamId"`
+	AwayScore int `json:"awayScore"`
+	AwayTeamList []PlayerExport `json:"awayTeamList"`
+
+	MatchOfficials []MatchOfficialExport `json:"matchOfficials"`
+
+	Location string `json:"location"`
+	VenueID string `json:"venueId"`
+	Kickoff *time.Time `json:"kickoff"`
+	Status string `json:"status"`
+	Weather string `json:"weather"`
+
+	PlayByPlay []PlayExport `json:"playByPlay"`
+	ScoreTimeline *ScoreTimelineExport `json:"scoreTimeline"`
+
+	Stats *MatchStatsExport `json:"stats"`
+}
+
+type PlayerExport struct {
+	NrlID string `json:"nrlId"`
+	NameFirst string `json:"nameFirst"`
+	NameLast string `json:"nameLast"`
+	Position string `json:"position"`
+	Number int `json:"number"`
+	Role string `json:"role"`
+	Captain bool `json:"captain"`
+	ViceCaptain bool `json:"viceCaptain"`
+	LateChange bool `json:"lateChange"`
+	PlayerStats *PlayerStatsExport `json:"playerStats"`
+}
+
+type MatchOfficialExport struct {
+	NameFirst string `json:"nameFirst"`
+	NameLast string `json:"nameLast"`
+	Role string `json:"role"`
+}
+
+type PlayExport struct {
+	Play string `json:"play"`
+	Team string `json:"team"`
+	Notes string `json:"notes"`
+	Time string `json:"time"`
+	Type string `json:"type"`
+	Minute *int `json:"minute"`
+	Second *int `json:"second"`
+	TeamID string `json:"teamId"`
+	Points int `json:"points"`
+	Player *PlayPlayerExport `json:"player"`
+	OtherPlayer *PlayPlayerExport `json:"otherPlayer"`
+}
+
+// PlayPlayerExport names a player involved in a play, who is also in one of
+// the match's team lists.
+type PlayPlayerExport struct {
+	NrlID string `json:"nrlId"`
+	NameFirst string `json:"nameFirst"`
+	NameLast string `json:"nameLast"`
+}
+
+type ScoreTimelineExport struct {
+	HalfTime *ScoreExport `json:"halfTime"`
+	Final ScoreExport `json:"final"`
+	BiggestHomeLead int `json:"biggestHomeLead"`
+	BiggestAwayLead int `json:"biggestAwayLead"`
+	LeadChanges int `json:"leadChanges"`
+	Comeback bool `json:"comeback"`
+	// false when the plays don't add up to the final score
+	Reliable bool `json:"reliable"`
+	FirstTryTeamID string `json:"firstTryTeamId"`
+	FirstTryScorer *PlayPlayerExport `json:"firstTryScorer"`
+	Events []ScoreEventExport `json:"events"`
+}
+
+type ScoreExport struct {
+	Home int `json:"home"`
+	Away int `json:"away"`
+}
+
+type ScoreEventExport struct {
+	Minute *int `json:"minute"`
+	Second *int `json:"second"`
+	TeamID string `json:"teamId"`
+	Type string `json:"type"`
+	Points int `json:"points"`
+	Player *PlayPlayerExport `json:"player"`
+	HomeScore int `json:"homeScore"`
+	AwayScore int `json:"awayScore"`
+}
+
+type MatchStatsExport struct {
+	PosAndComp *PosAndCompExport `json:"posAndComp"`
+	Attack *AttackExport `json:"attack"`
+	Passing *PassingExport `json:"passing"`
+	Kicking *KickingExport `json:"kicking"`
+	Defence *DefenceExport `json:"defence"`
+	NegPlays *NegPlaysExport `json:"negPlays"`
+}
+
+type PosAndCompExport struct {
+	HomePosPer int `json:"homePosPer"`
+	AwayPosPer int `json:"awayPosPer"`
+	HomePosTime string `json:"homePosTime"`
+	AwayPosTime string `json:"awayPosTime"`
+	HomeSets int `json:"homeSets"`
+	HomeSetsCompleted int `json:"homeSetsCompleted"`
+	AwaySets int `json:"awaySets"`
+	AwaySetsCompleted int `json:"awaySetsCompleted"`
+}
+
+type AttackExport struct {
+	HomeRuns int `json:"homeRuns"`
+	AwayRuns int `json:"awayRuns"`
+	HomeRunMeters int `json:"homeRunMeters"`
+	AwayRunMeters int `json:"awayRunMeters"`
+	HomePostContactMeters int `json:"homePostContactMeters"`
+	AwayPostContactMeters int `json:"awayPostContactMeters"`
+	HomeLineBreaks int `json:"homeLineBreaks"`
+	AwayLineBreaks int `json:"awayLineBreaks"`
+	HomeTackleBreaks int `json:"homeTackleBreaks"`
+	AwayTackleBreaks int `json:"awayTackleBreaks"`
+	HomeAvgSetDistance float64 `json:"homeAvgSetDistance"`
+	AwayAvgSetDistance float64 `json:"awayAvgSetDistance"`
+	HomeKickReturnMeters int `json:"homeKickReturnMeters"`
+	AwayKickReturnMeters int `json:"awayKickReturnMeters"`
+	HomeAvgPlayTheBallSpeed float64 `json:"homeAvgPlayTheBallSpeed"`
+	AwayAvgPlayTheBallSpeed float64 `json:"awayAvgPlayTheBallSpeed"`
+}
+
+type PassingExport struct {
+	HomeOffloads int `json:"homeOffloads"`
+	AwayOffloads int `json:"awayOffloads"`
+	HomeReceipts int `json:"homeReceipts"`
+	AwayReceipts int `json:"awayReceipts"`
+	HomeTotalPasses int `json:"homeTotalPasses"`
+	AwayTotalPasses int `json:"awayTotalPasses"`
+	HomeDummyPasses int `json:"homeDummyPasses"`
+	AwayDummyPasses int `json:"awayDummyPasses"`
+}
+
+type KickingExport struct {
+	HomeKicks int `json:"homeKicks"`
+	AwayKicks int `json:"awayKicks"`
+	HomeKickingMeters int `json:"homeKickingMeters"`
+	AwayKickingMeters int `json:"awayKickingMeters"`
+	HomeForcedDropOuts int `json:"homeForcedDropOuts"`
+	AwayForcedDropOuts int `json:"awayForcedDropOuts"`
+	HomeKickDefusal int `json:"homeKickDefusal"`
+	AwayKickDefusal int `json:"awayKickDefusal"`
+	HomeBombs int `json:"homeBombs"`
+	AwayBombs int `json:"awayBombs"`
+	HomeGrubbers int `json:"homeGrubbers"`
+	AwayGrubbers int `json:"awayGrubbers"`
+}
+
+type DefenceExport struct {
+	HomeEffecTackle float64 `json:"homeEffecTackle"`
+	AwayEffecTackle float64 `json:"awayEffecTackle"`
+	HomeTacklesMade int `json:"homeTacklesMade"`
+	AwayTacklesMade int `json:"awayTacklesMade"`
+	HomeMissedTackles int `json:"homeMissedTackles"`
+	AwayMissedTackles int `json:"awayMissedTackles"`
+	HomeIntercepts int `json:"homeIntercepts"`
+	AwayIntercepts int `json:"awayIntercepts"`
+	HomeIneffecTackles int `json:"homeIneffecTackles"`
+	AwayIneffecTackles int `json:"awayIneffecTackles"`
+}
+
+type NegPlaysExport struct {
+	HomeErrors int `json:"homeErrors"`
+	AwayErrors int `json:"awayErrors"`
+	HomePenCon int `json:"homePenCon"`
+	AwayPenCon int `json:"awayPenCon"`
+	HomeRuckInf int `json:"homeRuckInf"`
+	AwayRuckInf int `json:"awayRuckInf"`
+	HomeInside10 int `json:"homeInside10"`
+	AwayInside10 int `json:"awayInside10"`
+	HomeOnReport int `json:"homeOnReport"`
+	AwayOnReport int `json:"awayOnReport"`
+}
+
+type PlayerStatsExport struct {
+	MinutesPlayed int `json:"minutesPlayed"`
+	Points int `json:"points"`
+	Tries int `json:"tries"`
+	Conversions int `json:"conversions"`
+	ConversionAttempts int `json:"conversionAttempts"`
+	PenaltyGoals int `json:"penaltyGoals"`
+	GoalConversionRate float64 `json:"goalConversionRate"`
+	FieldGoals1 int `json:"fieldGoals1"`
+	FieldGoals2 int `json:"fieldGoals2"`
+	Runs int `json:"runs"`
+	RunMeters int `json:"runMeters"`
+	KickReturnMeters int `json:"kickReturnMeters"`
+	PostContactMeters int `json:"postContactMeters"`
+	LineBreaks int `json:"lineBreaks"`
+	LineBreakAssists int `json:"lineBreakAssists"`
+	TryAssists int `json:"tryAssists"`
+	LineEngagedRuns int `json:"lineEngagedRuns"`
+	TackleBreaks int `json:"tackleBreaks"`
+	HitUps int `json:"hitUps"`
+	AvgPlayTheBallSpeed float64 `json:"avgPlayTheBallSpeed"`
+	DummyHalfRuns int `json:"dummyHalfRuns"`
+	DummyHalfRunMeters int `json:"dummyHalfRunMeters"`
+	OneOnOneSteals int `json:"oneOnOneSteals"`
+	Offloads int `json:"offloads"`
+	DummyPasses int `json:"dummyPasses"`
+	Passes int `json:"passes"`
+	Receipts int `json:"receipts"`
+	PassesToRunRatio float64 `json:"passesToRunRatio"`
+	TackleEfficiency float64 `json:"tackleEfficiency"`
+	TacklesMade int `json:"tacklesMade"`
+	MissedTackles int `json:"missedTackles"`
+	IneffectiveTackles int `json:"ineffectiveTackles"`
+	Intercepts int `json:"intercepts"`
+	KicksDefused int `json:"kicksDefused"`
+	Kicks int `json:"kicks"`
+	KickingMeters int `json:"kickingMeters"`
+	ForcedDropOuts int `json:"forcedDropOuts"`
+	BombKicks int `json:"bombKicks"`
+	Grubbers int `json:"grubbers"`
+	FortyTwenty int `json:"fortyTwenty"`
+	TwentyForty int `json:"twentyForty"`
+	CrossFieldKicks int `json:"crossFieldKicks"`
+	KickedDead int `json:"kickedDead"`
+	Errors int `json:"errors"`
+	HandlingErrors int `json:"handlingErrors"`
+	OneOnOneLost int `json:"oneOnOneLost"`
+	Penalties int `json:"penalties"`
+	RuckInfringements int `json:"ruckInfringements"`
+	Inside10 int `json:"inside10"`
+	OnReport int `json:"onReport"`
+	SinBins int `json:"sinBins"`
+	SendOffs int `json:"sendOffs"`
+}
+
+func (ps *PlayerStats) toExport() *PlayerStatsExport {
+	return &PlayerStatsExport{
+		MinutesPlayed: ps.minutesPlayed,
+		Points: ps.points,
+		Tries: ps.tries,
+		Conversions: ps.conversions,
+		ConversionAttempts: ps.conversionAttempted,
+		PenaltyGoals: ps.penGoals,
+		GoalConversionRate: ps.conversionRate,
+		FieldGoals1: ps.feildGoal1,
+		FieldGoals2: ps.feildGoal2,
+		Runs: ps.runs,
+		RunMeters: ps.runMeters,
+		KickReturnMeters: ps.kickReturnMeters,
+		PostContactMeters: ps.postContactMeters,
+		LineBreaks: ps.lineBreaks,
+		LineBreakAssists: ps.lineBreakAssists,
+		TryAssists: ps.tryAssists,
+		LineEngagedRuns: ps.lineEngagedRuns,
+		TackleBreaks: ps.tackleBreaks,
+		HitUps: ps.hitUps,
+		AvgPlayTheBallSpeed: ps.AvgPlayBallSpeed,
+		DummyHalfRuns: ps.dummyHalfRuns,
+		DummyHalfRunMeters: ps.dummyHalfRunMeters,
+		OneOnOneSteals: ps.oneOnOneSteal,
+		Offloads: ps.offloads,
+		DummyPasses: ps.dummyPasses,
+		Passes: ps.passes,
+		Receipts: ps.receipts,
+		PassesToRunRatio: ps.passesToRunRatio,
+		TackleEfficiency: ps.tackleEff,
+		TacklesMade: ps.tacklesMade,
+		MissedTackles: ps.tacklesMissed,
+		IneffectiveTackles: ps.ineffectiveTackles,
+		Intercepts: ps.intercepts,
+		KicksDefused: ps.kicksDefused,
+		Kicks: ps.kicks,
+		KickingMeters: ps.kickMeters,
+		ForcedDropOuts: ps.forcedDropOuts,
+		BombKicks: ps.bombKicks,
+		Grubbers: ps.grubbers,
+		FortyTwenty: ps.fourtyTwenty,
+		TwentyForty: ps.twentyFourty,
+		CrossFieldKicks: ps.crossFieldKicks,
+		KickedDead: ps.kicksDead,
+		Errors: ps.errors,
+		HandlingErrors: ps.handlingErr,
+		OneOnOneLost: ps.OneOnOneLost,
+		Penalties: ps.pen,
+		RuckInfringements: ps.ruckInf,
+		Inside10: ps.inside10,
+		OnReport: ps.onReport,
+		SinBins: ps.sinBins,
+		SendOffs: ps.sendOffs,
+	}
+}
+
+func NewExport(comps []Competition) Export {
+	e := Export{
+		SchemaVersion: ExportSchemaVersion,
+		Competitions: []CompetitionExport{},
+	}
+
+	for _, c := range comps {
+		e.Competitions = append(e.Competitions, c.toExport())
+	}
+
+	return e
+}
+
+func (e Export) Marshal() ([]byte, error) {
+	return json.MarshalIndent(e, "", "  ")
+}
+
+func (c Competition) toExport() CompetitionExport {
+	ce := CompetitionExport{
+		ID: c.id,
+		Name: c.name,
+		Seasons: []SeasonExport{},
+	}
+
+	for _, s := range c.seasons {
+		ce.Seasons = append(ce.Seasons, s.toExport())
+	}
+
+	return ce
+}
+
+func (s *Season) toExport() SeasonExport {
+	se := SeasonExport{
+		Year: s.year,
+		Rounds: []RoundExport{},
+	}
+
+	for _, r := range s.rounds {
+		se.Rounds = append(se.Rounds, r.toExport())
+	}
+
+	return se
+}
+
+// exportTime writes times with their Sydney offset, and unknown times as null.
+func exportTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+
+	t = t.In(sydney)
+	return &t
+}
+
+func (r *Round) toExport() RoundExport {
+	re := RoundExport{
+		Round: r.roundName,
+		RoundIndex: r.roundIndex,
+		Start: exportTime(r.start),
+		End: exportTime(r.end),
+		Matches: []MatchExport{},
+		Byes: append([]string{}, r.byes...),
+		Ladder: []LadderExport{},
+	}
+
+	for _, m := range r.matches {
+		re.Matches = append(re.Matches, m.toExport())
+	}
+
+	for _, s := range r.ladder {
+		re.Ladder = append(re.Ladder, LadderExport{
+			Position: s.Position,
+			TeamID: s.Team,
+			Played: s.Played,
+			Won: s.Won,
+			Drawn: s.Drawn,
+			Lost: s.Lost,
+			Byes: s.Byes,
+			Points: s.Points,
+			For: s.For,
+			Against: s.Against,
+			Differential: s.Differential(),
+		})
+	}
+
+	return re
+}
+
+func (m *Match) toExport() MatchExport {
+	me := MatchExport{
+		HomeTeam: m.homeTeam,
+		HomeTeamID: m.homeTeamID,
+		HomeScore: m.homeScore,
+		HomeTeamList: playersToExport(m.homeTeamList),
+		AwayTeam: m.awayTeam,
+		AwayTeamID: m.awayTeamID,
+		AwayScore: m.awayScore,
+		AwayTeamList: playersToExport(m.awayTeamList),
+		MatchOfficials: []MatchOfficialExport{},
+		Location: m.location,
+		VenueID: m.venueID,
+		Kickoff: exportTime(m.kickoff),
+		Status: string(m.status),
+		Weather: m.weather,
+		PlayByPlay: []PlayExport{},
+	}
+
+	for _, o := range m.matchOfficals {
+		me.MatchOfficials = append(me.MatchOfficials, o.toExport())
+	}
+
+	players := map[uuid.UUID]*Player{}
+	for _, p := range append(append([]*Player{}, m.homeTeamList...), m.awayTeamList...) {
+		if p.id != uuid.Nil {
+			players[p.id] = p
+		}
+	}
+	for _, p := range m.playByPlay {
+		me.PlayByPlay = append(me.PlayByPlay, p.toExport(players))
+	}
+
+	if m.scoreTimeline != nil {
+		me.ScoreTimeline = m.scoreTimeline.toExport(players)
+	}
+
+	if m.stats != nil {
+		me.Stats = m.stats.toExport()
+	}
+
+	return me
+}
+
+func playersToExport(players []*Player) []PlayerExport {
+	out := []PlayerExport{}
+	for _, p := range players {
+		out = append(out, p.toExport())
+	}
+
+	return out
+}
+
+func (p *Player) toExport() PlayerExport {
+	pe := PlayerExport{
+		NrlID: p.nrlID,
+		NameFirst: p.nameFirst,
+		NameLast: p.nameLast,
+		Position: p.position,
+		Number: p.number,
+		Role: string(p.role),
+		Captain: p.captain,
+		ViceCaptain: p.viceCaptain,
+		LateChange: p.lateChange,
+	}
+
+	if p.playerStats != nil {
+		pe.PlayerStats = p.playerStats.toExport()
+	}
+
+	return pe
+}
+
+func (o *MatchOffical) toExport() MatchOfficialExport {
+	return MatchOfficialExport{
+		NameFirst: o.nameFirst,
+		NameLast: o.nameLast,
+		Role: o.role,
+	}
+}
+
+func (p *Play) toExport(players map[uuid.UUID]*Player) PlayExport {
+	pe := PlayExport{
+		Play: p.play,
+		Team: p.team,
+		Notes: p.notes,
+		Time: p.time,
+		Type: string(p.eventType),
+		TeamID: p.teamID,
+		Points: p.points,
+		Player: playPlayerToExport(players[p.playerID]),
+		OtherPlayer: playPlayerToExport(players[p.otherPlayerID]),
+	}
+
+	if p.timed {
+		minute, second := p.minute, p.second
+		pe.Minute, pe.Second = &minute, &second
+	}
+
+	return pe
+}
+
+func (tl *ScoreTimeline) toExport(players map[uuid.UUID]*Player) *ScoreTimelineExport {
+	te := &ScoreTimelineExport{
+		Final: ScoreExport{Home: tl.homeScore, Away: tl.awayScore},
+		BiggestHomeLead: tl.biggestHomeLead,
+		BiggestAwayLead: tl.biggestAwayLead,
+		LeadChanges: tl.leadChanges,
+		Comeback: tl.comeback,
+		Reliable: tl.reliable,
+		FirstTryTeamID: tl.firstTryTeamID,
+		FirstTryScorer: playPlayerToExport(players[tl.firstTryPlayerID]),
+		Events: []ScoreEventExport{},
+	}
+
+	if tl.halfTimeKnown {
+		te.HalfTime = &ScoreExport{Home: tl.halfTimeHome, Away: tl.halfTimeAway}
+	}
+
+	for _, e := range tl.events {
+		ee := ScoreEventExport{
+			TeamID: e.teamID,
+			Type: string(e.eventType),
+			Points: e.points,
+			Player: playPlayerToExport(players[e.playerID]),
+			HomeScore: e.homeScore,
+			AwayScore: e.awayScore,
+		}
+		if e.timed {
+			minute, second := e.minute, e.second
+			ee.Minute, ee.Second = &minute, &second
+		}
+		te.Events = append(te.Events, ee)
+	}
+
+	return te
+}
+
+func playPlayerToExport(p *Player) *PlayPlayerExport {
+	if p == nil {
+		return nil
+	}
+
+	return &PlayPlayerExport{
+		NrlID: p.nrlID,
+		NameFirst: p.nameFirst,
+		NameLast: p.nameLast,
+	}
+}
+
+func (ms *MatchStats) toExport() *MatchStatsExport {
+	mse := &MatchStatsExport{}
+
+	if pc := ms.posAndComp; pc != nil {
+		mse.PosAndComp = &PosAndCompExport{
+			HomePosPer: pc.homePosPer,
+			AwayPosPer: pc.awayPosPer,
+			HomePosTime: pc.homePosTime,
+			AwayPosTime: pc.awayPosTime,
+			HomeSets: pc.homeSets,
+			HomeSetsCompleted: pc.homeSetsCompleated,
+			AwaySets: pc.awaySets,
+			AwaySetsCompleted: pc.awaySetsCompleated,
+		}
+	}
+
+	if a := ms.attack; a != nil {
+		mse.Attack = &AttackExport{
+			HomeRuns: a.homeRuns,
+			AwayRuns: a.awayRuns,
+			HomeRunMeters: a.homeRunMeters,
+			AwayRunMeters: a.awayRunMeters,
+			HomePostContactMeters: a.homePostContactMeters,
+			AwayPostContactMeters: a.awayPostContactMeters,
+			HomeLineBreaks: a.homeLineBreaks,
+			AwayLineBreaks: a.awayLineBreaks,
+			HomeTackleBreaks: a.homeTackleBreaks,
+			AwayTackleBreaks: a.awayTackleBreaks,
+			HomeAvgSetDistance: a.homeAvgSetDistance,
+			AwayAvgSetDistance: a.awayAvgSetDistance,
+			HomeKickReturnMeters: a.homeKickReturnMeters,
+			AwayKickReturnMeters: a.awayKickReturnMeters,
+			HomeAvgPlayTheBallSpeed: a.homeAvgPlayTheBallSpeed,
+			AwayAvgPlayTheBallSpeed: a.awayAvgPlayTheBallSpeed,
+		}
+	}
+
+	if p := ms.passing; p != nil {
+		mse.Passing = &PassingExport{
+			HomeOffloads: p.homeOffloads,
+			AwayOffloads: p.awayOffloads,
+			HomeReceipts: p.homeReceipts,
+			AwayReceipts: p.awayReceipts,
+			HomeTotalPasses: p.homeTotalPasses,
+			AwayTotalPasses: p.awayTotalPasses,
+			HomeDummyPasses: p.homeDummyPasses,
+			AwayDummyPasses: p.awayDummyPasses,
+		}
+	}
+
+	if k := ms.kicking; k != nil {
+		mse.Kicking = &KickingExport{
+			HomeKicks: k.homeKicks,
+			AwayKicks: k.awayKicks,
+			HomeKickingMeters: k.homeKickingMeters,
+			AwayKickingMeters: k.awayKickingMeters,
+			HomeForcedDropOuts: k.homeForcedDropOuts,
+			AwayForcedDropOuts: k.awayForcedDropOuts,
+			HomeKickDefusal: k.homeKickDefusal,
+			AwayKickDefusal: k.awayKickDefusal,
+			HomeBombs: k.homeBombs,
+			AwayBombs: k.awayBombs,
+			HomeGrubbers: k.homeGrubbers,
+			AwayGrubbers: k.awayGrubbers,
+		}
+	}
+
+	if d := ms.defence; d != nil {
+		mse.Defence = &DefenceExport{
+			HomeEffecTackle: d.homeEffecTackle,
+			AwayEffecTackle: d.awayEffecTackle,
+			HomeTacklesMade: d.homeTacklesMade,
+			AwayTacklesMade: d.awayTacklesMade,
+			HomeMissedTackles: d.homeMissedTackles,
+			AwayMissedTackles: d.awayMissedTackles,
+			HomeIntercepts: d.homeIntercepts,
+			AwayIntercepts: d.awayIntercepts,
+			HomeIneffecTackles: d.homeIneffecTackles,
+			AwayIneffecTackles: d.awayIneffecTackles,
+		}
+	}
+
+	if np := ms.negPlays; np != nil {
+		mse.NegPlays = &NegPlaysExport{
+			HomeErrors: np.homeErrors,
+			AwayErrors: np.awayErrors,
+			HomePenCon: np.homePenCon,
+			AwayPenCon: np.awayPenCon,
+			HomeRuckInf: np.homeRuckInf,
+			AwayRuckInf: np.awayRuckInf,
+			HomeInside10: np.homeInside10,
+			AwayInside10: np.awayInside10,
+			HomeOnReport: np.homeOnReport,
+			AwayOnReport: np.awayOnReport,
+		}
+	}
+
+	return mse
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+)
+
+// output/results.json is the sample export, kept in step with the schema, so
+// every field of the schema has to survive a decode and encode
+func TestExportRoundTrip(t *testing.T) {
+	content, err := os.ReadFile("../output/results.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var e Export
+	dec := json.NewDecoder(bytes.NewReader(content))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&e); err != nil {
+		t.Fatalf("results.json does not match the export schema: %v", err)
+	}
+
+	if e.SchemaVersion != ExportSchemaVersion {
+		t.Fatalf("schema version %d, want %d", e.SchemaVersion, ExportSchemaVersion)
+	}
+
+	encoded, err := e.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var want, got any
+	if err := json.Unmarshal(content, &want); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(encoded, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(want, got) {
+		t.Fatal("results.json changed after a decode/encode round trip")
+	}
+}
+
+func TestExportEscapesText(t *testing.T) {
+	m := &Match{
+		homeTeam: "Sea Eagles",
+		awayTeam: "Roosters",
+		location: `4 Pines Park "Brookvale Oval"`,
+		playByPlay: []*Play{
+			{play: "Try", team: "Sea Eagles", notes: "Tom Trbojevic \"TT\" scores\nunder the posts", time: "12:04"},
+		},
+	}
+
+	comp := Competition{
+		id: 111,
+		name: "Mens NRL Premiership",
+		seasons: []*Season{{year: "2024", rounds: []*Round{{roundName: "Round 1", roundIndex: 1, matches: []*Match{m}}}}},
+	}
+
+	encoded, err := NewExport([]Competition{comp}).Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var e Export
+	if err := json.Unmarshal(encoded, &e); err != nil {
+		t.Fatalf("export is not valid json: %v", err)
+	}
+
+	got := e.Competitions[0].Seasons[0].Rounds[0].Matches[0]
+	if got.Location != m.location {
+		t.Errorf("location = %q, want %q", got.Location, m.location)
+	}
+	if got.PlayByPlay[0].Notes != m.playByPlay[0].notes {
+		t.Errorf("notes = %q, want %q", got.PlayByPlay[0].Notes, m.playByPlay[0].notes)
+	}
+	if got.Stats != nil {
+		t.Errorf("stats = %+v, want null for a match without stats", got.Stats)
+	}
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/0xlfl/nrl-predictor/scraper/features"
+)
+
+// featureStats are the per-team columns of the match stats tables, each
+// stored as home_<column> and away_<column>. The possession time is left out
+// as it only restates the possession percentage.
+var featureStats = []struct {
+	table string
+	columns []string
+}{
+	{"pos_and_comp", []string{"pos_per", "sets", "sets_completed"}},
+	{"attack", []string{"runs", "run_meters", "post_contact_meters", "line_breaks", "tackle_breaks", "avg_set_distance", "kick_return_meters", "avg_play_the_ball_speed"}},
+	{"passing", []string{"offloads", "receipts", "total_passes", "dummy_passes"}},
+	{"kicking", []string{"kicks", "kicking_meters", "forced_drop_outs", "kick_defusal", "bombs", "grubbers"}},
+	{"defence", []string{"effec_tackle", "tackles_made", "missed_tackles", "intercepts", "ineffec_tackles"}},
+	{"neg_plays", []string{"errors", "pen_con", "ruck_inf", "inside10", "on_report"}},
+}
+
+// UpdateFeatures works out every team's form going into each match of the
+// competition, played or not, and stores it.
+func UpdateFeatures(ctx context.Context, db *DB, compID int) ([]features.Row, error) {
+	games, err := db.GetFeatureGames(ctx, compID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get match stats: %w", err)
+	}
+
+	rows := features.Compute(games)
+	if err := db.SetMatchFeatures(ctx, rows); err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
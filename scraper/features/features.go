@@ -0,0 +1,166 @@
+// Package features turns the stats teams recorded in past matches into model
+// inputs for the matches that follow.
+package features
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Windows are the numbers of recent games stats are averaged over, on top of
+// the season to date.
+var Windows = []int{3, 5, 10}
+
+// Rate is a stat over another, worked out as the ratio of the totals over a
+// window rather than the mean of each game's ratio.
+type Rate struct {
+	Name string
+	Of string
+	Per string
+}
+
+var Rates = []Rate{
+	{Name: "completion_rate", Of: "sets_completed", Per: "sets"},
+	{Name: "error_rate", Of: "errors", Per: "sets"},
+}
+
+// Game is one team's side of a match. Stats holds only the stats that were
+// recorded, and is ignored for games that haven't been played.
+type Game struct {
+	MatchID uuid.UUID
+	Season string
+	Round int
+	Kickoff time.Time
+	Team string
+	Opponent string
+	Played bool
+	Stats map[string]float64
+}
+
+// Row holds the features of a team going into a match. Games counts the
+// played games they were taken from.
+type Row struct {
+	MatchID uuid.UUID
+	Team string
+	Opponent string
+	Games int
+	Features map[string]float64
+}
+
+// before reports whether a was over before b kicked off. Without both
+// kickoffs only an earlier round counts, so nothing from the same round leaks
+// into a match's features.
+func before(a, b Game) bool {
+	if !a.Kickoff.IsZero() && !b.Kickoff.IsZero() {
+		return a.Kickoff.Before(b.Kickoff)
+	}
+	if a.Season != b.Season {
+		return a.Season < b.Season
+	}
+
+	return a.Round < b.Round
+}
+
+// Compute works out the features of every game from the played games that
+// finished strictly before it.
+func Compute(games []Game) []Row {
+	ordered := append([]Game{}, games...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		a, b := ordered[i], ordered[j]
+		if a.Season != b.Season {
+			return a.Season < b.Season
+		}
+		if a.Round != b.Round {
+			return a.Round < b.Round
+		}
+		return a.Kickoff.Before(b.Kickoff)
+	})
+
+	byTeam := map[string][]Game{}
+	for _, g := range ordered {
+		byTeam[g.Team] = append(byTeam[g.Team], g)
+	}
+
+	type key struct {
+		match uuid.UUID
+		team string
+	}
+	own := map[key]map[string]float64{}
+	rows := make([]Row, 0, len(ordered))
+	for _, g := range ordered {
+		var history []Game
+		for _, h := range byTeam[g.Team] {
+			if h.Played && before(h, g) {
+				history = append(history, h)
+			}
+		}
+
+		f := form(g.Season, history)
+		own[key{g.MatchID, g.Team}] = f
+		rows = append(rows, Row{MatchID: g.MatchID, Team: g.Team, Opponent: g.Opponent, Games: len(history), Features: map[string]float64{}})
+	}
+
+	// differentials need both sides, so they go in once every form is known
+	for _, r := range rows {
+		f, opp := own[key{r.MatchID, r.Team}], own[key{r.MatchID, r.Opponent}]
+		for name, v := range f {
+			r.Features[name] = v
+			if o, ok := opp[name]; ok {
+				r.Features[name+"_diff"] = v - o
+			}
+		}
+	}
+
+	return rows
+}
+
+// form averages the history over each window and the season to date.
+func form(season string, history []Game) map[string]float64 {
+	f := map[string]float64{}
+	for _, n := range Windows {
+		recent := history[max(0, len(history)-n):]
+		average(f, "_avg_"+strconv.Itoa(n), recent)
+	}
+
+	var current []Game
+	for _, g := range history {
+		if g.Season == season {
+			current = append(current, g)
+		}
+	}
+	average(f, "_avg_season", current)
+
+	return f
+}
+
+func average(f map[string]float64, suffix string, games []Game) {
+	sums, counts := map[string]float64{}, map[string]int{}
+	for _, g := range games {
+		for name, v := range g.Stats {
+			sums[name] += v
+			counts[name]++
+		}
+	}
+	for name, sum := range sums {
+		f[name+suffix] = sum / float64(counts[name])
+	}
+
+	for _, r := range Rates {
+		var of, per float64
+		for _, g := range games {
+			o, ok := g.Stats[r.Of]
+			p, ok2 := g.Stats[r.Per]
+			// a game missing either side would skew the ratio
+			if ok && ok2 {
+				of += o
+				per += p
+			}
+		}
+		if per > 0 {
+			f[r.Name+suffix] = of / per
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package features
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// match returns both sides of a match.
+func match(season string, round int, kickoff time.Time, home, away string, played bool, homeStats, awayStats map[string]float64) []Game {
+	id := uuid.New()
+	return []Game{
+		{MatchID: id, Season: season, Round: round, Kickoff: kickoff, Team: home, Opponent: away, Played: played, Stats: homeStats},
+		{MatchID: id, Season: season, Round: round, Kickoff: kickoff, Team: away, Opponent: home, Played: played, Stats: awayStats},
+	}
+}
+
+func rowsByMatch(rows []Row) map[uuid.UUID]map[string]Row {
+	m := map[uuid.UUID]map[string]Row{}
+	for _, r := range rows {
+		if m[r.MatchID] == nil {
+			m[r.MatchID] = map[string]Row{}
+		}
+		m[r.MatchID][r.Team] = r
+	}
+	return m
+}
+
+func TestComputeHistory(t *testing.T) {
+	kickoff := time.Date(2024, time.March, 7, 9, 0, 0, 0, time.UTC)
+	stats := func(runs float64) map[string]float64 { return map[string]float64{"runs": runs} }
+
+	round1 := match("2024", 1, time.Time{}, "Storm", "Panthers", true, stats(150), stats(170))
+	// round 2 was drawn without a kickoff, and its postponed fixture was
+	// replayed with one
+	round2 := match("2024", 2, time.Time{}, "Storm", "Titans", true, stats(160), stats(140))
+	replayed := match("2024", 2, kickoff.AddDate(0, 0, 20), "Panthers", "Storm", true, stats(180), stats(120))
+	unplayed := match("2024", 3, kickoff.AddDate(0, 0, 21), "Storm", "Titans", false, stats(999), stats(999))
+	round4 := match("2024", 4, kickoff.AddDate(0, 0, 28), "Titans", "Storm", false, nil, nil)
+
+	var games []Game
+	for _, m := range [][]Game{round4, unplayed, replayed, round2, round1} {
+		games = append(games, m...)
+	}
+	rows := rowsByMatch(Compute(games))
+
+	tests := []struct {
+		name string
+		row Row
+		games int
+		runs float64
+	}{
+		{"first game", rows[round1[0].MatchID]["Storm"], 0, 0},
+		// the two round 2 matches never feed each other, whichever has the
+		// kickoff
+		{"same round without a kickoff", rows[round2[0].MatchID]["Storm"], 1, 150},
+		{"same round with a kickoff", rows[replayed[0].MatchID]["Storm"], 1, 150},
+		{"after the replayed fixture", rows[unplayed[0].MatchID]["Storm"], 3, (150 + 160 + 120) / 3.0},
+		// the unplayed round 3 game is left out
+		{"after an unplayed game", rows[round4[0].MatchID]["Storm"], 3, (150 + 160 + 120) / 3.0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.row.Games != tt.games {
+				t.Errorf("Games = %d, want %d", tt.row.Games, tt.games)
+			}
+			if got := tt.row.Features["runs_avg_10"]; got != tt.runs {
+				t.Errorf("runs_avg_10 = %v, want %v", got, tt.runs)
+			}
+		})
+	}
+}
+
+func TestComputeSeasonAverage(t *testing.T) {
+	stats := func(runs float64) map[string]float64 { return map[string]float64{"runs": runs} }
+	kickoff := time.Date(2024, time.September, 1, 9, 0, 0, 0, time.UTC)
+
+	last2024 := match("2024", 26, kickoff, "Storm", "Panthers", true, stats(100), stats(100))
+	first2025 := match("2025", 1, kickoff.AddDate(0, 6, 0), "Storm", "Panthers", true, stats(200), stats(200))
+	second2025 := match("2025", 2, kickoff.AddDate(0, 6, 7), "Storm", "Panthers", false, nil, nil)
+
+	rows := rowsByMatch(Compute(append(append(last2024, first2025...), second2025...)))
+
+	first := rows[first2025[0].MatchID]["Storm"].Features
+	if _, ok := first["runs_avg_season"]; ok {
+		t.Errorf("runs_avg_season = %v going into a new season, want none", first["runs_avg_season"])
+	}
+	if first["runs_avg_3"] != 100 {
+		t.Errorf("runs_avg_3 = %v, want last season's 100", first["runs_avg_3"])
+	}
+
+	second := rows[second2025[0].MatchID]["Storm"].Features
+	if second["runs_avg_season"] != 200 || second["runs_avg_3"] != 150 {
+		t.Errorf("runs_avg_season, runs_avg_3 = %v, %v, want 200, 150", second["runs_avg_season"], second["runs_avg_3"])
+	}
+}
+
+func TestComputeRates(t *testing.T) {
+	kickoff := time.Date(2024, time.March, 7, 9, 0, 0, 0, time.UTC)
+	var games []Game
+	for i, s := range []map[string]float64{
+		{"sets": 40, "sets_completed": 30, "errors": 10},
+		{"sets": 20, "sets_completed": 18, "errors": 2},
+		// no set count, so neither rate can use this game
+		{"sets_completed": 5, "errors": 20},
+	} {
+		games = append(games, match("2024", i+1, kickoff.AddDate(0, 0, 7*i), "Storm", "Panthers", true, s, nil)...)
+	}
+	next := match("2024", 4, kickoff.AddDate(0, 0, 21), "Storm", "Panthers", false, nil, nil)
+	games = append(games, next...)
+
+	f := rowsByMatch(Compute(games))[next[0].MatchID]["Storm"].Features
+	want := map[string]float64{
+		"completion_rate_avg_season": 48.0 / 60,
+		"error_rate_avg_season": 12.0 / 60,
+		"errors_avg_season": 32.0 / 3,
+		"sets_avg_season": 30,
+	}
+	for name, v := range want {
+		if f[name] != v {
+			t.Errorf("%s = %v, want %v", name, f[name], v)
+		}
+	}
+}
+
+func TestComputeDifferentials(t *testing.T) {
+	kickoff := time.Date(2024, time.March, 7, 9, 0, 0, 0, time.UTC)
+	first := match("2024", 1, kickoff, "Storm", "Panthers", true,
+		map[string]float64{"runs": 150, "kicks": 20},
+		map[string]float64{"runs": 170})
+	next := match("2024", 2, kickoff.AddDate(0, 0, 7), "Storm", "Panthers", false, nil, nil)
+
+	rows := rowsByMatch(Compute(append(first, next...)))
+
+	storm, panthers := rows[next[0].MatchID]["Storm"].Features, rows[next[0].MatchID]["Panthers"].Features
+	if storm["runs_avg_3_diff"] != -20 || panthers["runs_avg_3_diff"] != 20 {
+		t.Errorf("runs_avg_3_diff = %v, %v, want -20, 20", storm["runs_avg_3_diff"], panthers["runs_avg_3_diff"])
+	}
+	if _, ok := storm["kicks_avg_3_diff"]; ok {
+		t.Error("kicks_avg_3_diff present without the Panthers' kicks")
+	}
+	if storm["kicks_avg_3"] != 20 {
+		t.Errorf("kicks_avg_3 = %v, want 20", storm["kicks_avg_3"])
+	}
+
+	// nothing to go on for the first match, so no features either way
+	if f := rows[first[0].MatchID]["Storm"].Features; !reflect.DeepEqual(f, map[string]float64{}) {
+		t.Errorf("features of the first match = %v, want none", f)
+	}
+}
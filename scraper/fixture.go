@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/chromedp/chromedp"
+)
+
+// FixtureFetcher serves pages from a directory of saved HTML snapshots
+// instead of a live browser, so parsing can be re-run offline.
+type FixtureFetcher struct {
+	dir string
+}
+
+var fixtureNameReplacer = regexp.MustCompile(`[^A-Za-z0-9.-]+`)
+
+func NewFixtureFetcher(dir string) (*FixtureFetcher, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open fixture dir: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("fixture path %s is not a directory", dir)
+	}
+
+	return &FixtureFetcher{dir: dir}, nil
+}
+
+// fixturePath names the snapshot of url after the url itself, so fixtures can
+// be found by eye, followed by a short hash of the full url as the readable
+// part folds every run of punctuation into one "_".
+func fixturePath(dir string, url string) string {
+	name := strings.TrimPrefix(strings.TrimPrefix(url, "https://"), "http://")
+	name = strings.Trim(fixtureNameReplacer.ReplaceAllString(name, "_"), "_")
+	sum := sha256.Sum256([]byte(url))
+
+	return filepath.Join(dir, name+"_"+hex.EncodeToString(sum[:4])+".html")
+}
+
+// writeFixture saves a fetched page into dir. Pages fetched with extra
+// instructions (e.g. an opened dropdown) are a superset of the plain page for
+// the same url, so a plain fetch never replaces an existing snapshot.
+func writeFixture(dir string, url string, html string, overwrite bool) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	path := fixturePath(dir, url)
+	if !overwrite {
+		if _, err := os.Stat(path); err == nil {
+			return nil
+		}
+	}
+
+	return writeFileAtomic(path, []byte(html))
+}
+
+// Fetch serves the snapshot saved for url. Browser instructions can't be
+// replayed against a snapshot, so they are ignored and the page is returned
+// as it was recorded, which is the page with the instructions already run
+// whenever any were used (see writeFixture).
+func (ff *FixtureFetcher) Fetch(
+	ctx context.Context,
+	url string,
+	instructions chromedp.Tasks,
+	require bool,
+) (string, error) {
+	content, err := os.ReadFile(fixturePath(ff.dir, url))
+	if err != nil {
+		return "", fmt.Errorf("no fixture for %s: %w", url, err)
+	}
+
+	return string(content), nil
+}
+
+func (ff *FixtureFetcher) FetchSeasons(ctx context.Context, compID int, wg *sync.WaitGroup) ([]string, error) {
+	return fetchSeasons(ctx, ff, compID)
+}
+
+func (FixtureFetcher) ParseList(html string, selector string) ([]string, error) {
+	return parseList(html, selector)
+}
+
+func (ff *FixtureFetcher) IsCached(url string) int {
+	info, err := os.Stat(fixturePath(ff.dir, url))
+	if err != nil {
+		return 0
+	}
+
+	return int(info.ModTime().Unix())
+}
+
+// Close is a no-op, fixtures are read fresh on every fetch.
+func (ff *FixtureFetcher) Close() error {
+	return nil
+}
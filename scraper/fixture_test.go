@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// the pages under testdata are nrl.com pages cut down to the markup the
+// parsers read, named the way FixtureFetcher looks them up
+const (
+	roundFixture = "https://www.nrl.com/draw/?competition=111&round=1&season=2024"
+	matchFixture = "https://www.nrl.com//draw/nrl-premiership/2024/round-1/sea-eagles-v-rabbitohs/"
+)
+
+func fixturePage(t *testing.T, url string) string {
+	t.Helper()
+
+	f, err := NewFixtureFetcher("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := f.Fetch(context.Background(), url, nil, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return content
+}
+
+func fixtureDoc(t *testing.T, url string) *goquery.Document {
+	t.Helper()
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(fixturePage(t, url)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return doc
+}
+
+func TestFixtureFetchSeasons(t *testing.T) {
+	f, err := NewFixtureFetcher("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	seasons, err := f.FetchSeasons(context.Background(), 111, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"2025", "2024", "2023"}; !reflect.DeepEqual(seasons, want) {
+		t.Errorf("seasons = %v, want %v", seasons, want)
+	}
+
+	// the competition picks the draw the seasons are read from
+	if _, err := f.FetchSeasons(context.Background(), 113, nil); err == nil {
+		t.Error("no error for a competition without a fixture")
+	}
+}
+
+// urls that only differ in their punctuation read the same once it is folded
+// into "_", so each keeps a snapshot of its own
+func TestFixturePathCollisions(t *testing.T) {
+	dir := t.TempDir()
+	urls := []string{
+		"https://www.nrl.com/draw/?competition=111&round=1",
+		"https://www.nrl.com/draw/competition=111/round=1",
+		"https://www.nrl.com/draw/competition/111/round/1",
+		"https://www.nrl.com/draw/?competition=111/round&1",
+	}
+
+	paths := map[string]string{}
+	for _, url := range urls {
+		path := fixturePath(dir, url)
+		if other, ok := paths[path]; ok {
+			t.Errorf("%s and %s share the fixture %s", url, other, path)
+		}
+		paths[path] = url
+
+		if err := writeFixture(dir, url, "<p>"+url+"</p>", false); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	f, err := NewFixtureFetcher(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, url := range urls {
+		content, err := f.Fetch(context.Background(), url, nil, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if content != "<p>"+url+"</p>" {
+			t.Errorf("fetched %q for %s", content, url)
+		}
+	}
+}
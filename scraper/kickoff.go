@@ -0,0 +1,159 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// nrl.com shows kickoffs in Sydney time, which moves between AEST and AEDT
+// during the season. The tz database is embedded so the container doesn't
+// need one.
+var sydney = mustLoadLocation("Australia/Sydney")
+
+func mustLoadLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err)
+	}
+
+	return loc
+}
+
+var months = map[string]time.Month{}
+
+var weekdays = map[string]bool{"tues": true, "thur": true, "thurs": true}
+
+func init() {
+	for m := time.January; m <= time.December; m++ {
+		name := strings.ToLower(m.String())
+		months[name] = m
+		months[name[:3]] = m
+	}
+	months["sept"] = time.September
+
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		name := strings.ToLower(d.String())
+		weekdays[name] = true
+		weekdays[name[:3]] = true
+	}
+}
+
+var (
+	dayPattern = regexp.MustCompile(`^(\d{1,2})(st|nd|rd|th)?$`)
+	yearPattern = regexp.MustCompile(`^\d{4}$`)
+	clockPattern = regexp.MustCompile(`^(\d{1,2})(?:[:.](\d{2}))?\s*(am|pm)?$`)
+)
+
+// parseKickoff finds when a match kicks off. A machine readable datetime is
+// used when the page has one, otherwise the header text is parsed with the
+// season supplying the year it leaves out.
+func parseKickoff(s *goquery.Selection, season string) (time.Time, error) {
+	if header := s.Find(".match-header"); header.Length() > 0 {
+		s = header
+	}
+
+	if dt, ok := s.Find("time[datetime]").First().Attr("datetime"); ok {
+		if t, err := time.Parse(time.RFC3339, strings.TrimSpace(dt)); err == nil {
+			return t.In(sydney), nil
+		}
+	}
+
+	date := strings.TrimSpace(s.Find("p.match-header__title").First().Text())
+	clock := strings.TrimSpace(s.Find(".match-header__kick-off, .match-clock__kick-off, time").First().Text())
+
+	return parseKickoffText(strings.TrimSpace(date+" "+clock), season)
+}
+
+// parseKickoffText reads dates such as "Saturday 2 March", "Sat, 2nd Mar
+// 2024" or "Thursday 7 March 7:50 PM" in Sydney time. The day is the number
+// between the weekday and the month, or next to the month when there is no
+// weekday, so other numbers in a header ("Round 5") are never taken for it.
+// Without a clock time the kickoff is taken as midnight of that day.
+func parseKickoffText(text string, season string) (time.Time, error) {
+	fields := strings.Fields(strings.ToLower(strings.NewReplacer(",", " ", "kick off", " ", "kick-off", " ").Replace(text)))
+
+	day, year := 0, 0
+	var month time.Month
+	hour, minute := 0, 0
+
+	monthAt, dayAt, weekdayAt := -1, -1, -1
+	for i, f := range fields {
+		if weekdays[f] && weekdayAt < 0 {
+			weekdayAt = i
+		}
+		if m, ok := months[f]; ok && monthAt < 0 {
+			month, monthAt = m, i
+		}
+	}
+	// "2 March" is preferred over "March 2", and with a weekday the day has
+	// to follow it ("Saturday 2 March", "Saturday March 2")
+	for _, i := range []int{monthAt - 1, monthAt + 1} {
+		if monthAt < 0 || i < 0 || i >= len(fields) {
+			continue
+		}
+		if weekdayAt >= 0 && i-1 != weekdayAt && !(i == monthAt+1 && monthAt-1 == weekdayAt) {
+			continue
+		}
+		if match := dayPattern.FindStringSubmatch(fields[i]); match != nil {
+			day, _ = strconv.Atoi(match[1])
+			dayAt = i
+			break
+		}
+	}
+
+	for i := 0; i < len(fields); i++ {
+		f := fields[i]
+		if i == monthAt || i == dayAt {
+			continue
+		}
+
+		if yearPattern.MatchString(f) {
+			year, _ = strconv.Atoi(f)
+			continue
+		}
+
+		// the am/pm marker may be split from the time
+		clock := f
+		if i+1 < len(fields) && (fields[i+1] == "am" || fields[i+1] == "pm") {
+			clock += fields[i+1]
+		}
+		if strings.ContainsAny(clock, ":.") || strings.HasSuffix(clock, "am") || strings.HasSuffix(clock, "pm") {
+			if match := clockPattern.FindStringSubmatch(clock); match != nil {
+				hour, _ = strconv.Atoi(match[1])
+				minute, _ = strconv.Atoi(match[2])
+				switch {
+				case match[3] == "pm" && hour < 12:
+					hour += 12
+				case match[3] == "am" && hour == 12:
+					hour = 0
+				}
+				if clock != f {
+					i++
+				}
+			}
+		}
+	}
+
+	if year == 0 {
+		y, err := strconv.Atoi(season)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("no year in %q and invalid season %q", text, season)
+		}
+		year = y
+	}
+
+	if day == 0 || month == 0 {
+		return time.Time{}, fmt.Errorf("unable to parse kickoff %q", text)
+	}
+	if hour > 23 || minute > 59 {
+		return time.Time{}, fmt.Errorf("invalid kickoff time in %q", text)
+	}
+
+	return time.Date(year, month, day, hour, minute, 0, 0, sydney), nil
+}
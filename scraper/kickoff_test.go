@@ -0,0 +1,95 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func TestParseKickoffText(t *testing.T) {
+	tests := []struct {
+		text string
+		season string
+		// in UTC, so the Sydney offset in effect is checked too
+		want time.Time
+	}{
+		{"Thursday 7 March 7:50 PM", "2024", time.Date(2024, time.March, 7, 8, 50, 0, 0, time.UTC)},
+		{"Sat, 2nd Mar 2024", "2023", time.Date(2024, time.March, 1, 13, 0, 0, 0, time.UTC)},
+		{"Saturday 2 March", "2024", time.Date(2024, time.March, 1, 13, 0, 0, 0, time.UTC)},
+		{"March 21 7:00 pm", "2025", time.Date(2025, time.March, 21, 8, 0, 0, 0, time.UTC)},
+		{"Friday 15 August 6.00 PM", "2025", time.Date(2025, time.August, 15, 8, 0, 0, 0, time.UTC)},
+		{"Round 5 - Saturday 6 April 3:00 PM", "2024", time.Date(2024, time.April, 6, 4, 0, 0, 0, time.UTC)},
+		{"Round 27 Sunday 1st September 4:05pm", "2024", time.Date(2024, time.September, 1, 6, 5, 0, 0, time.UTC)},
+		{"Kick Off 12:00 PM Sunday 28 July", "2024", time.Date(2024, time.July, 28, 2, 0, 0, 0, time.UTC)},
+		// daylight saving ends at 3am on the first Sunday of April
+		{"Saturday 6 April 7:35 PM", "2024", time.Date(2024, time.April, 6, 8, 35, 0, 0, time.UTC)},
+		{"Sunday 7 April 2:00 PM", "2024", time.Date(2024, time.April, 7, 4, 0, 0, 0, time.UTC)},
+		// and starts at 2am on the first Sunday of October, grand final day
+		// in 2024
+		{"Saturday 5 October 2024 7:40 PM", "2024", time.Date(2024, time.October, 5, 9, 40, 0, 0, time.UTC)},
+		{"Sunday 6 October 2024 7:30 PM", "2024", time.Date(2024, time.October, 6, 8, 30, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got, err := parseKickoffText(tt.text, tt.season)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("kickoff = %v, want %v", got, tt.want.In(sydney))
+			}
+		})
+	}
+}
+
+func TestParseKickoffTextErrors(t *testing.T) {
+	for _, text := range []string{
+		"",
+		"7:50 PM",
+		// a round number is not a day
+		"Round 5 Saturday March",
+		"Round 5 March Saturday 2:00 PM",
+		"Thursday 7 March 25:00",
+		"Saturday 2 March",
+	} {
+		season := "2024"
+		if text == "Saturday 2 March" {
+			season = "all"
+		}
+		if got, err := parseKickoffText(text, season); err == nil {
+			t.Errorf("%q = %v, want an error", text, got)
+		}
+	}
+}
+
+func TestParseKickoff(t *testing.T) {
+	round := fixtureDoc(t, roundFixture)
+
+	tests := []struct {
+		name string
+		s *goquery.Selection
+		want time.Time
+	}{
+		{"datetime attribute", round.Find(".match").First(), time.Date(2024, time.March, 3, 8, 30, 0, 0, sydney)},
+		{"match header text", fixtureDoc(t, matchFixture).Selection, time.Date(2024, time.March, 3, 8, 30, 0, 0, sydney)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseKickoff(tt.s, "2024")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("kickoff = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// a card that only carries the clock needs the day header above it
+	if _, err := parseKickoff(round.Find(".match").Eq(1), "2024"); err == nil {
+		t.Error("no error for a card without its day")
+	}
+}
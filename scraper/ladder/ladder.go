@@ -0,0 +1,185 @@
+// Package ladder works out competition standings from match results.
+package ladder
+
+import (
+	"sort"
+)
+
+// Competition points, as awarded in the NRL.
+const (
+	WinPoints = 2
+	DrawPoints = 1
+	ByePoints = 2
+)
+
+// Result is a regular season match. Matches that haven't been played still
+// count towards working out which teams had the bye.
+type Result struct {
+	Round int
+	Home string
+	Away string
+	HomeScore int
+	AwayScore int
+	Played bool
+}
+
+type Standing struct {
+	Position int
+	Team string
+	Played int
+	Won int
+	Drawn int
+	Lost int
+	Byes int
+	Points int
+	For int
+	Against int
+}
+
+func (s Standing) Differential() int {
+	return s.For - s.Against
+}
+
+// Snapshot is the ladder once a round has been played.
+type Snapshot struct {
+	Round int
+	Standings []Standing
+}
+
+// Missing is a team that neither played nor had the bye in a round whose
+// byes are known.
+type Missing struct {
+	Round int
+	Team string
+}
+
+// Compute returns the ladder after every round with at least one played
+// match. byes lists the teams given the bye in each round. In a round without
+// that list, every team in teams or results that has no match in it had the
+// bye. In a round with it, the teams that have neither are returned as
+// missing and are not credited.
+func Compute(teams []string, results []Result, byes map[int][]string) ([]Snapshot, []Missing) {
+	table := map[string]*Standing{}
+	addTeam := func(t string) {
+		if _, ok := table[t]; !ok {
+			table[t] = &Standing{Team: t}
+		}
+	}
+	for _, t := range teams {
+		addTeam(t)
+	}
+
+	byRound := map[int][]Result{}
+	for _, r := range results {
+		byRound[r.Round] = append(byRound[r.Round], r)
+		addTeam(r.Home)
+		addTeam(r.Away)
+	}
+	for _, teams := range byes {
+		for _, t := range teams {
+			addTeam(t)
+		}
+	}
+
+	rounds := make([]int, 0, len(byRound))
+	for round := range byRound {
+		rounds = append(rounds, round)
+	}
+	sort.Ints(rounds)
+
+	var snapshots []Snapshot
+	var missing []Missing
+	for _, round := range rounds {
+		played := false
+		scheduled := map[string]bool{}
+		for _, r := range byRound[round] {
+			scheduled[r.Home], scheduled[r.Away] = true, true
+			played = played || r.Played
+		}
+		if !played {
+			continue
+		}
+
+		listed, known := byes[round]
+		bye := map[string]bool{}
+		for _, t := range listed {
+			bye[t] = true
+		}
+
+		names := make([]string, 0, len(table))
+		for team := range table {
+			names = append(names, team)
+		}
+		sort.Strings(names)
+		for _, team := range names {
+			switch {
+			case scheduled[team]:
+			case !known || bye[team]:
+				table[team].Byes++
+				table[team].Points += ByePoints
+			default:
+				missing = append(missing, Missing{Round: round, Team: team})
+			}
+		}
+
+		for _, r := range byRound[round] {
+			if r.Played {
+				apply(table[r.Home], r.HomeScore, r.AwayScore)
+				apply(table[r.Away], r.AwayScore, r.HomeScore)
+			}
+		}
+
+		snapshots = append(snapshots, Snapshot{Round: round, Standings: rank(table)})
+	}
+
+	return snapshots, missing
+}
+
+func apply(s *Standing, scored, conceded int) {
+	s.Played++
+	s.For += scored
+	s.Against += conceded
+
+	switch {
+	case scored > conceded:
+		s.Won++
+		s.Points += WinPoints
+	case scored == conceded:
+		s.Drawn++
+		s.Points += DrawPoints
+	default:
+		s.Lost++
+	}
+}
+
+// rank orders the table by the NRL tie-breaks: competition points, then
+// points differential, then the percentage of points for to points against.
+// Teams still level are listed by name so the ladder is stable.
+func rank(table map[string]*Standing) []Standing {
+	standings := make([]Standing, 0, len(table))
+	for _, s := range table {
+		standings = append(standings, *s)
+	}
+
+	sort.Slice(standings, func(i, j int) bool {
+		a, b := standings[i], standings[j]
+		if a.Points != b.Points {
+			return a.Points > b.Points
+		}
+		if a.Differential() != b.Differential() {
+			return a.Differential() > b.Differential()
+		}
+		// compares For/Against without dividing by a team that conceded nothing
+		if pa, pb := a.For*b.Against, b.For*a.Against; pa != pb {
+			return pa > pb
+		}
+
+		return a.Team < b.Team
+	})
+
+	for i := range standings {
+		standings[i].Position = i + 1
+	}
+
+	return standings
+}
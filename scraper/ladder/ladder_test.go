@@ -0,0 +1,207 @@
+package ladder
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRank(t *testing.T) {
+	tests := []struct {
+		name string
+		table []*Standing
+		want []string
+	}{
+		{
+			name: "points",
+			table: []*Standing{
+				{Team: "Broncos", Points: 2, For: 40, Against: 0},
+				{Team: "Storm", Points: 4, For: 10, Against: 30},
+			},
+			want: []string{"Storm", "Broncos"},
+		},
+		{
+			name: "differential",
+			table: []*Standing{
+				{Team: "Broncos", Points: 4, For: 30, Against: 20},
+				{Team: "Storm", Points: 4, For: 20, Against: 0},
+			},
+			want: []string{"Storm", "Broncos"},
+		},
+		{
+			name: "percentage",
+			table: []*Standing{
+				{Team: "Broncos", Points: 4, For: 20, Against: 10},
+				{Team: "Storm", Points: 4, For: 40, Against: 30},
+			},
+			want: []string{"Broncos", "Storm"},
+		},
+		{
+			// 10/0 is an infinite percentage and beats 20/10
+			name: "percentage with nothing against",
+			table: []*Standing{
+				{Team: "Broncos", Points: 4, For: 20, Against: 10},
+				{Team: "Storm", Points: 4, For: 10, Against: 0},
+			},
+			want: []string{"Storm", "Broncos"},
+		},
+		{
+			name: "name",
+			table: []*Standing{
+				{Team: "Storm", Points: 2, Byes: 1},
+				{Team: "Broncos", Points: 2, Byes: 1},
+			},
+			want: []string{"Broncos", "Storm"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table := map[string]*Standing{}
+			for _, s := range tt.table {
+				table[s.Team] = s
+			}
+
+			var got []string
+			for i, s := range rank(table) {
+				if s.Position != i+1 {
+					t.Errorf("%s at position %d, want %d", s.Team, s.Position, i+1)
+				}
+				got = append(got, s.Team)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("order = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComputeByes(t *testing.T) {
+	teams := []string{"Broncos", "Dolphins", "Storm", "Titans"}
+	results := []Result{
+		{Round: 1, Home: "Broncos", Away: "Storm", HomeScore: 12, AwayScore: 20, Played: true},
+		// the Dolphins and Titans have the bye
+		{Round: 2, Home: "Dolphins", Away: "Titans", HomeScore: 18, AwayScore: 18, Played: true},
+		// round 3 is partly played
+		{Round: 3, Home: "Storm", Away: "Dolphins", HomeScore: 30, AwayScore: 6, Played: true},
+		{Round: 3, Home: "Titans", Away: "Broncos", Played: false},
+		// nothing of round 4 has been played
+		{Round: 4, Home: "Storm", Away: "Titans", Played: false},
+	}
+
+	snapshots, missing := Compute(teams, results, nil)
+	if len(missing) > 0 {
+		t.Errorf("missing = %v, want none", missing)
+	}
+
+	var rounds []int
+	for _, s := range snapshots {
+		rounds = append(rounds, s.Round)
+	}
+	if !reflect.DeepEqual(rounds, []int{1, 2, 3}) {
+		t.Fatalf("rounds = %v, want [1 2 3]", rounds)
+	}
+
+	byTeam := func(s Snapshot) map[string]Standing {
+		m := map[string]Standing{}
+		for _, st := range s.Standings {
+			st.Position = 0
+			m[st.Team] = st
+		}
+		return m
+	}
+
+	round1 := byTeam(snapshots[0])
+	if want := (Standing{Team: "Dolphins", Byes: 1, Points: 2}); round1["Dolphins"] != want {
+		t.Errorf("round 1 Dolphins = %+v, want %+v", round1["Dolphins"], want)
+	}
+
+	round3 := byTeam(snapshots[2])
+	want := map[string]Standing{
+		"Storm": {Team: "Storm", Played: 2, Won: 2, Byes: 1, Points: 6, For: 50, Against: 18},
+		"Dolphins": {Team: "Dolphins", Played: 2, Drawn: 1, Lost: 1, Byes: 1, Points: 3, For: 24, Against: 48},
+		"Titans": {Team: "Titans", Played: 1, Drawn: 1, Byes: 1, Points: 3, For: 18, Against: 18},
+		"Broncos": {Team: "Broncos", Played: 1, Lost: 1, Byes: 1, Points: 2, For: 12, Against: 20},
+	}
+	if !reflect.DeepEqual(round3, want) {
+		t.Errorf("round 3 =\n%+v\nwant\n%+v", round3, want)
+	}
+}
+
+func TestComputeSeason(t *testing.T) {
+	results := []Result{
+		{Round: 1, Home: "Sea Eagles", Away: "Rabbitohs", HomeScore: 36, AwayScore: 24, Played: true},
+		{Round: 1, Home: "Storm", Away: "Panthers", HomeScore: 8, AwayScore: 0, Played: true},
+		{Round: 2, Home: "Panthers", Away: "Sea Eagles", HomeScore: 22, AwayScore: 22, Played: true},
+		{Round: 2, Home: "Rabbitohs", Away: "Storm", HomeScore: 10, AwayScore: 30, Played: true},
+		{Round: 3, Home: "Sea Eagles", Away: "Storm", HomeScore: 16, AwayScore: 14, Played: true},
+		{Round: 3, Home: "Rabbitohs", Away: "Panthers", HomeScore: 20, AwayScore: 18, Played: true},
+	}
+
+	snapshots, _ := Compute(nil, results, nil)
+	if len(snapshots) != 3 {
+		t.Fatalf("got %d snapshots, want 3", len(snapshots))
+	}
+
+	want := []Standing{
+		{Position: 1, Team: "Sea Eagles", Played: 3, Won: 2, Drawn: 1, Points: 5, For: 74, Against: 60},
+		{Position: 2, Team: "Storm", Played: 3, Won: 2, Lost: 1, Points: 4, For: 52, Against: 26},
+		{Position: 3, Team: "Rabbitohs", Played: 3, Won: 1, Lost: 2, Points: 2, For: 54, Against: 84},
+		{Position: 4, Team: "Panthers", Played: 3, Drawn: 1, Lost: 2, Points: 1, For: 40, Against: 50},
+	}
+	if got := snapshots[2].Standings; !reflect.DeepEqual(got, want) {
+		t.Errorf("ladder =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestComputeListedByes(t *testing.T) {
+	teams := []string{"Broncos", "Dolphins", "Storm", "Titans", "Warriors"}
+	results := []Result{
+		{Round: 1, Home: "Broncos", Away: "Storm", HomeScore: 12, AwayScore: 20, Played: true},
+		{Round: 2, Home: "Broncos", Away: "Titans", HomeScore: 10, AwayScore: 4, Played: true},
+		{Round: 2, Home: "Storm", Away: "Warriors", HomeScore: 20, AwayScore: 16, Played: true},
+	}
+	// round 1 lists only the Dolphins, round 2 isn't listed
+	byes := map[int][]string{1: {"Dolphins"}}
+
+	snapshots, missing := Compute(teams, results, byes)
+
+	want := []Missing{{Round: 1, Team: "Titans"}, {Round: 1, Team: "Warriors"}}
+	if !reflect.DeepEqual(missing, want) {
+		t.Errorf("missing = %v, want %v", missing, want)
+	}
+
+	byes1 := map[string]int{}
+	for _, s := range snapshots[0].Standings {
+		byes1[s.Team] = s.Byes
+	}
+	if !reflect.DeepEqual(byes1, map[string]int{"Broncos": 0, "Dolphins": 1, "Storm": 0, "Titans": 0, "Warriors": 0}) {
+		t.Errorf("round 1 byes = %v", byes1)
+	}
+
+	// without a list the bye is worked out from the draw
+	for _, s := range snapshots[1].Standings {
+		if s.Team == "Dolphins" && (s.Byes != 2 || s.Points != 4) {
+			t.Errorf("round 2 Dolphins = %+v, want 2 byes and 4 points", s)
+		}
+	}
+}
+
+func TestComputeByeTeamsOnLadder(t *testing.T) {
+	// a team only known from the bye list still makes the ladder
+	results := []Result{
+		{Round: 1, Home: "Broncos", Away: "Storm", HomeScore: 12, AwayScore: 20, Played: true},
+	}
+	snapshots, missing := Compute(nil, results, map[int][]string{1: {"Dolphins"}})
+	if len(missing) > 0 {
+		t.Errorf("missing = %v, want none", missing)
+	}
+
+	want := []Standing{
+		{Position: 1, Team: "Storm", Played: 1, Won: 1, Points: 2, For: 20, Against: 12},
+		{Position: 2, Team: "Dolphins", Byes: 1, Points: 2},
+		{Position: 3, Team: "Broncos", Played: 1, Lost: 1, For: 12, Against: 20},
+	}
+	if got := snapshots[0].Standings; !reflect.DeepEqual(got, want) {
+		t.Errorf("ladder =\n%+v\nwant\n%+v", got, want)
+	}
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TaskKind string
+
+const (
+	TaskSeason TaskKind = "season"
+	TaskRound TaskKind = "round"
+	TaskMatch TaskKind = "match"
+)
+
+type TaskStatus string
+
+const (
+	TaskPending TaskStatus = "pending"
+	TaskDone TaskStatus = "done"
+	TaskFailed TaskStatus = "failed"
+)
+
+const (
+	RunRunning = "running"
+	RunFinished = "finished"
+	RunFailed = "failed"
+	RunInterrupted = "interrupted"
+)
+
+// Task is one unit of a scrape run: listing the rounds of a season, listing
+// the matches of a round, or scraping a match.
+type Task struct {
+	kind TaskKind
+	season string
+	roundIndex int
+	roundID uuid.UUID
+	matchID uuid.UUID
+	url string
+}
+
+func seasonTask(season string) Task {
+	return Task{kind: TaskSeason, season: season}
+}
+
+func roundTask(season string, roundIndex int, roundID uuid.UUID) Task {
+	return Task{kind: TaskRound, season: season, roundIndex: roundIndex, roundID: roundID}
+}
+
+func (t Task) matchTask(matchID uuid.UUID, url string) Task {
+	return Task{kind: TaskMatch, season: t.season, roundIndex: t.roundIndex, roundID: t.roundID, matchID: matchID, url: url}
+}
+
+func (t Task) key() string {
+	switch t.kind {
+	case TaskSeason:
+		return t.season
+	case TaskRound:
+		return fmt.Sprintf("%s/%d", t.season, t.roundIndex)
+	}
+
+	return fmt.Sprintf("%s/%d/%s", t.season, t.roundIndex, t.matchID)
+}
+
+// matchStages groups the stages errors are reported under by the part of a
+// match that raises them. Each key is a stage of a match task in the ledger.
+var matchStages = map[Stage][]Stage{
+	StageMatchFetch: {StageMatchFetch},
+	StageMatchStats: {StageMatchStats, StagePosAndComp, StageAttack, StagePassing, StageKicking, StageDefence, StageNegPlays},
+	StagePlayByPlay: {StagePlayByPlay, StageScoreTimeline},
+	StageTeamList: {StageTeamList, StagePlayerStats, StageMatchDetails, StageOfficials},
+}
+
+func taskStages(t Task) []Stage {
+	switch t.kind {
+	case TaskSeason:
+		return []Stage{StageRounds}
+	case TaskRound:
+		return []Stage{StageRound}
+	}
+
+	return []Stage{StageMatchFetch, StageMatchStats, StagePlayByPlay, StageTeamList}
+}
+
+// Ledger records the status of every task of a scrape run so an interrupted
+// or failed run can be resumed. A task is only marked done once everything it
+// spawned has been recorded, so a pending task always covers the work lost
+// below it. A nil Ledger records nothing.
+type Ledger struct {
+	db *DB
+	runID uuid.UUID
+}
+
+func (l *Ledger) RunID() uuid.UUID {
+	if l == nil {
+		return uuid.Nil
+	}
+
+	return l.runID
+}
+
+// ledger writes are detached from the caller so a task's outcome is still
+// recorded while shutting down
+func (l *Ledger) write(ctx context.Context, t Task, stage Stage, status TaskStatus, message string) {
+	if l == nil {
+		return
+	}
+
+	dbCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+
+	if err := l.db.SetScrapeTask(dbCtx, l.runID, t, stage, status, message); err != nil {
+		fmt.Println("unable to record task", t.key(), stage, err)
+	}
+}
+
+func (l *Ledger) start(ctx context.Context, t Task, stages ...Stage) {
+	for _, stage := range stages {
+		l.write(ctx, t, stage, TaskPending, "")
+	}
+}
+
+func (l *Ledger) finish(ctx context.Context, t Task, stage Stage, err error) {
+	if err != nil {
+		l.write(ctx, t, stage, TaskFailed, err.Error())
+		return
+	}
+
+	l.write(ctx, t, stage, TaskDone, "")
+}
+
+// close marks the run as finished, failed or interrupted.
+func (l *Ledger) close(ctx context.Context, failed bool) {
+	if l == nil {
+		return
+	}
+
+	status := RunFinished
+	switch {
+	case ctx.Err() != nil:
+		status = RunInterrupted
+	case failed:
+		status = RunFailed
+	}
+
+	dbCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+
+	if err := l.db.FinishScrapeRun(dbCtx, l.runID, status); err != nil {
+		fmt.Println("unable to finish scrape run", err)
+	}
+}
+
+// NewLedger starts a run for the competition. When the run cannot be recorded
+// the scrape still goes ahead, it just cannot be resumed.
+func NewLedger(ctx context.Context, db *DB, compID int, cfg *ScrapeConfig, resumedFrom uuid.UUID) *Ledger {
+	dbCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	runID, err := db.CreateScrapeRun(dbCtx, compID, cfg.seasons.String(), cfg.rounds.String(), resumedFrom)
+	if err != nil {
+		fmt.Println("unable to start scrape run, it will not be resumable", err)
+		return nil
+	}
+
+	fmt.Println("Scrape run", runID)
+	return &Ledger{db: db, runID: runID}
+}
+
+// uncoveredTasks drops the rounds and matches whose season or round is being
+// requeued too, as scraping the parent again already covers them.
+func uncoveredTasks(tasks []Task) []Task {
+	seasons := map[string]bool{}
+	rounds := map[string]bool{}
+	for _, t := range tasks {
+		switch t.kind {
+		case TaskSeason:
+			seasons[t.season] = true
+		case TaskRound:
+			rounds[t.key()] = true
+		}
+	}
+
+	var uncovered []Task
+	for _, t := range tasks {
+		switch t.kind {
+		case TaskRound:
+			if seasons[t.season] {
+				continue
+			}
+		case TaskMatch:
+			if seasons[t.season] || rounds[roundTask(t.season, t.roundIndex, uuid.Nil).key()] {
+				continue
+			}
+		}
+		uncovered = append(uncovered, t)
+	}
+
+	return uncovered
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestTaskKey(t *testing.T) {
+	roundID := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	matchID := uuid.MustParse("22222222-2222-2222-2222-222222222222")
+
+	tests := []struct {
+		name string
+		task Task
+		want string
+	}{
+		{"season", seasonTask("2024"), "2024"},
+		{"round", roundTask("2024", 3, roundID), "2024/3"},
+		// a round listed again under a new id is still the same task
+		{"round without id", roundTask("2024", 3, uuid.Nil), "2024/3"},
+		{"match", roundTask("2024", 3, roundID).matchTask(matchID, "https://www.nrl.com/draw/"), "2024/3/22222222-2222-2222-2222-222222222222"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.task.key(); got != tt.want {
+				t.Errorf("key() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTaskStages(t *testing.T) {
+	matchID := uuid.New()
+
+	tests := []struct {
+		name string
+		task Task
+		want []Stage
+	}{
+		{"season", seasonTask("2024"), []Stage{StageRounds}},
+		{"round", roundTask("2024", 1, uuid.New()), []Stage{StageRound}},
+		{"match", roundTask("2024", 1, uuid.New()).matchTask(matchID, ""), []Stage{StageMatchFetch, StageMatchStats, StagePlayByPlay, StageTeamList}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := taskStages(tt.task); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("taskStages() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// every ledger stage of a match gathers its errors from matchStages, and
+	// an error stage counted under two of them would fail both
+	seen := map[Stage]Stage{}
+	for _, stage := range taskStages(Task{kind: TaskMatch}) {
+		reported, ok := matchStages[stage]
+		if !ok {
+			t.Errorf("match stage %s has no error stages", stage)
+		}
+		for _, r := range reported {
+			if other, ok := seen[r]; ok {
+				t.Errorf("error stage %s is under both %s and %s", r, other, stage)
+			}
+			seen[r] = stage
+		}
+	}
+	if len(matchStages) != len(taskStages(Task{kind: TaskMatch})) {
+		t.Errorf("matchStages has %d stages, match tasks have %d", len(matchStages), len(taskStages(Task{kind: TaskMatch})))
+	}
+}
+
+func TestUncoveredTasks(t *testing.T) {
+	round := func(season string, index int) Task {
+		return roundTask(season, index, uuid.New())
+	}
+	match := func(season string, index int) Task {
+		return round(season, index).matchTask(uuid.New(), "")
+	}
+
+	season2023 := seasonTask("2023")
+	round2024r1 := round("2024", 1)
+	round2024r2 := round("2024", 2)
+	match2023 := match("2023", 5)
+	match2024r1 := match("2024", 1)
+	match2024r3 := match("2024", 3)
+
+	tests := []struct {
+		name string
+		tasks []Task
+		want []Task
+	}{
+		{"nothing", nil, nil},
+		{"season covers its rounds and matches", []Task{season2023, round("2023", 5), match2023}, []Task{season2023}},
+		// the round was listed under another id in the run being resumed
+		{"round covers its matches", []Task{round2024r1, match2024r1}, []Task{round2024r1}},
+		{"other rounds and seasons are kept", []Task{season2023, round2024r2, match2024r1, match2024r3}, []Task{season2023, round2024r2, match2024r1, match2024r3}},
+		{"matches alone are kept", []Task{match2023, match2024r3}, []Task{match2023, match2024r3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := uncoveredTasks(tt.tasks); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("uncoveredTasks() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -10,6 +10,8 @@ import (
 	"github.com/PuerkitoBio/goquery"
 	"strings"
   "github.com/google/uuid"
+
+	"github.com/0xlfl/nrl-predictor/scraper/elo"
 )
 
 type Season struct {
@@ -25,49 +27,21 @@ type Competition struct {
 }
 
 type Fetcher interface {
-	Fetch(url string, instructions chromedp.Tasks, require bool) (body string, err error)
-	FetchSeasons(wg *sync.WaitGroup) ([]string)
+	Fetch(ctx context.Context, url string, instructions chromedp.Tasks, require bool) (body string, err error)
+	FetchSeasons(ctx context.Context, compID int, wg *sync.WaitGroup) ([]string, error)
 	ParseList(html string, selector string) ([]string, error)
 	IsCached(url string) (cachedAt int)
+	Close() error
 }
 
 type PageFetcher struct {
 	allocCtx context.Context
+	allocCancel context.CancelFunc
 	browserCtx context.Context
+	browserCancel context.CancelFunc
 	semaphore chan struct{}
-}
-
-func createListStr[T fmt.Stringer](list []T) string {
-	var sb strings.Builder
-	sb.WriteString("[")
-
-	for i, v := range list {
-		sb.WriteString(v.String())
-		if i < len(list)-1 {
-			sb.WriteString(", ")
-		}
-	}
-
-	sb.WriteString("]")
-	return sb.String()
-}
-
-func (c Competition) String() string {
-	result := "{"
-	i := 0
-	for _, round := range c.seasons {
-		result += round.String()
-		if i < len(c.seasons)-1 {
-			result += ","
-		}
-		i++
-	}
-
-	return result + "}"
-}
-
-func (s *Season) String() string {
-	return fmt.Sprintf("\"%s\": %s", s.year, createListStr(s.rounds))
+	recordDir string
+	cache *PageCache
 }
 
 func NewPageFetcher(maxConcurrent int) (*PageFetcher, error) {
@@ -96,23 +70,43 @@ func NewPageFetcher(maxConcurrent int) (*PageFetcher, error) {
 
 	return &PageFetcher{
 		allocCtx: allocCtx,
+		allocCancel: allocCancel,
 		browserCtx: browserCtx,
+		browserCancel: cancel,
 		semaphore: make(chan struct{}, maxConcurrent),
 	}, nil
 }
 
 func (p *PageFetcher) Fetch(
+	ctx context.Context,
 	url string,
 	instructions chromedp.Tasks,
 	require bool,
 ) (string, error) {
-	p.semaphore <- struct{}{}
+	key := cacheKey(url, instructions)
+	if p.cache != nil {
+		if html, ok := p.cache.Get(key); ok {
+			// a cached page is still fetched as far as a recording goes
+			p.record(url, html, instructions)
+			return html, nil
+		}
+	}
+
+	select {
+	case p.semaphore <- struct{}{}:
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
 	defer func() { <-p.semaphore }() 
 
-	ctx, browserCancel := chromedp.NewContext(p.browserCtx)
+	// tabs have to hang off the browser context, so tie them to the caller's
+	// context by closing the tab when it is cancelled
+	tabCtx, browserCancel := chromedp.NewContext(p.browserCtx)
 	defer browserCancel()
+	stop := context.AfterFunc(ctx, browserCancel)
+	defer stop()
 
-	ctx, timeoutCancel:= context.WithTimeout(ctx, 60*time.Second)
+	tabCtx, timeoutCancel:= context.WithTimeout(tabCtx, 60*time.Second)
 	defer timeoutCancel()
 
 	var html string
@@ -131,43 +125,123 @@ func (p *PageFetcher) Fetch(
 	// Always get the HTML at the end
 	tasks = append(tasks, chromedp.OuterHTML("html", &html))
 
-	chromedp.ListenTarget(ctx, func(ev interface{}) {})
+	chromedp.ListenTarget(tabCtx, func(ev interface{}) {})
 
-	err := chromedp.Run(ctx, tasks)
+	err := chromedp.Run(tabCtx, tasks)
 	for i := 0; i < 4; i++ {
 		if err == nil {
+			p.record(url, html, instructions)
+			if p.cache != nil {
+				if err := p.cache.Put(key, html); err != nil {
+					fmt.Println("unable to cache page", url, err)
+				}
+			}
 			return html, nil
-		} else if !require {
+		} else if !require || ctx.Err() != nil {
 			return "", err
 		}
 
-		err = chromedp.Run(ctx, tasks);
+		err = chromedp.Run(tabCtx, tasks);
 	}
 
 	return "", err
 }
 
-func (pf PageFetcher) FetchSeasons(wg *sync.WaitGroup) (years []string) {
-	content := fmt.Sprintf(
-		pf.Fetch(
-			fmt.Sprintf("https://www.nrl.com/draw/?competition=111&round=1&season=2025"),
-			chromedp.Tasks{
-					chromedp.WaitVisible(`[aria-controls="season-dropdown"]`, chromedp.ByQuery),
-					chromedp.Click(`[aria-controls="season-dropdown"]`, chromedp.ByQuery),
-					chromedp.Sleep(2 * time.Second),
-			},
-			true,
-	))
-
-	years, err := pf.ParseList(content, "#season-dropdown li button div")
+// record saves a fetched page into the record dir, if there is one.
+func (p *PageFetcher) record(url string, html string, instructions chromedp.Tasks) {
+	if p.recordDir == "" {
+		return
+	}
+
+	if err := writeFixture(p.recordDir, url, html, len(instructions) > 0); err != nil {
+		fmt.Println("unable to record fixture", url, err)
+	}
+}
+
+// Record saves every page fetched from now on into dir, in the layout
+// FixtureFetcher reads back, whether it came from the browser or the cache.
+func (p *PageFetcher) Record(dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create record dir: %w", err)
+	}
+
+	p.recordDir = dir
+	return nil
+}
+
+// UseCache serves pages from an on-disk cache in dir while they are fresh,
+// only falling back to the browser once they expire.
+func (p *PageFetcher) UseCache(dir string, config CacheConfig) error {
+	cache, err := NewPageCache(dir, config)
 	if err != nil {
-		panic(err)
+		return err
 	}
 
-	return
+	p.cache = cache
+	return nil
+}
+
+// Close shuts the browser down and closes the page cache, if one is in use.
+func (p *PageFetcher) Close() error {
+	if p.browserCancel != nil {
+		p.browserCancel()
+	}
+	if p.allocCancel != nil {
+		p.allocCancel()
+	}
+	if p.cache == nil {
+		return nil
+	}
+
+	return p.cache.Close()
+}
+
+const interactiveKey = "#interactive"
+
+// cacheKey separates pages fetched with extra browser instructions from the
+// plain page at the same url, as the two can render different content.
+func cacheKey(url string, instructions chromedp.Tasks) string {
+	if len(instructions) > 0 {
+		return url + interactiveKey
+	}
+
+	return url
+}
+
+func (pf PageFetcher) FetchSeasons(ctx context.Context, compID int, wg *sync.WaitGroup) ([]string, error) {
+	return fetchSeasons(ctx, &pf, compID)
 }
 
 func (PageFetcher) ParseList(html string, selector string) ([]string, error) {
+	return parseList(html, selector)
+}
+
+// fetchSeasons lists the seasons of a competition from the season dropdown
+// of its draw, which is the same whatever season the draw is opened on.
+func fetchSeasons(ctx context.Context, f Fetcher, compID int) ([]string, error) {
+	content, err := f.Fetch(
+		ctx,
+		fmt.Sprintf("https://www.nrl.com/draw/?competition=%d&round=1&season=2025", compID),
+		chromedp.Tasks{
+				chromedp.WaitVisible(`[aria-controls="season-dropdown"]`, chromedp.ByQuery),
+				chromedp.Click(`[aria-controls="season-dropdown"]`, chromedp.ByQuery),
+				chromedp.Sleep(2 * time.Second),
+		},
+		true,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch seasons: %w", err)
+	}
+
+	years, err := f.ParseList(content, "#season-dropdown li button div")
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse seasons: %w", err)
+	}
+
+	return years, nil
+}
+
+func parseList(html string, selector string) ([]string, error) {
 	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
 	if err != nil {
 		return nil, err
@@ -184,46 +258,178 @@ func (PageFetcher) ParseList(html string, selector string) ([]string, error) {
 	return results, nil
 }
 
-func (PageFetcher) IsCached(url string) (int) {
-	return 0
+func (p *PageFetcher) IsCached(url string) (int) {
+	if p.cache == nil {
+		return 0
+	}
+
+	// the page may only have been fetched with instructions, which caches it
+	// under its own key
+	cachedAt := p.cache.CachedAt(url)
+	if interactive := p.cache.CachedAt(url + interactiveKey); interactive.After(cachedAt) {
+		cachedAt = interactive
+	}
+	if cachedAt.IsZero() {
+		return 0
+	}
+
+	return int(cachedAt.Unix())
 }
 
 func main() {
-	/*var wg sync.WaitGroup
-	fetcher, err := NewPageFetcher(10)
-	
+	os.Exit(run(os.Args[1:]))
+}
+
+// Scrape walks every selected season of a competition. Cancelling ctx stops
+// new seasons, rounds and matches from being started while letting matches
+// already in flight finish, so the DB is never left with a partial match.
+// Failures along the way are collected, stored and summarised at the end
+// rather than stopping the scrape, and every task is recorded in a ledger so
+// the run can be resumed. The returned error is non-nil if anything failed.
+func Scrape(ctx context.Context, db *DB, compID int, f Fetcher, wg *sync.WaitGroup, cfg *ScrapeConfig) (err error) {
+	dbCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	err = db.CreateCompIfNotExist(dbCtx, "Mens NRL Premiership", compID)
+	cancel()
 	if err != nil {
-		fmt.Println("unable to creater page fatcher", err)
-		return
+		return fmt.Errorf("unable to create competition: %w", err)
 	}
 
-	Scrape(111, fetcher, &wg)*/
+	seedVenues(ctx, db)
+
+	errs := NewErrorCollector()
+	stats := &StatsTracker{}
+	ledger := NewLedger(ctx, db, compID, cfg, uuid.Nil)
+	stopProgress := reportProgress(stats)
+	defer func() {
+		err = finishScrape(ctx, db, compID, wg, stopProgress, errs, ledger)
+	}()
 
-	db, err := NewDB()
+	available, err := f.FetchSeasons(ctx, compID, wg)
 	if err != nil {
-		panic(err)
+		errs.Add(ScrapeError{stage: StageSeasons, err: err})
+		return
+	}
+
+	seasons := cfg.selectSeasons(available)
+	fmt.Printf("Scraping %d seasons\n", len(seasons))
+
+	// every season is recorded before any starts, so an interrupted run still
+	// knows about the ones it never reached
+	for _, s := range seasons {
+		ledger.start(ctx, seasonTask(s), taskStages(seasonTask(s))...)
+	}
+
+	for _, s := range seasons {
+		if ctx.Err() != nil {
+			break
+		}
+
+		launchSeason(ctx, db, compID, s, f, wg, stats, errs, ledger, cfg)
 	}
 
-	defer db.Conn.Close()
-	comp, _ := db.GetCompetition(111)
-	writeToFile(fmt.Sprint(comp), "/app/output/results.json")
+	return nil
 }
 
-func Scrape(compID int, f Fetcher, wg *sync.WaitGroup) (comp Competition) {
-	db, err := NewDB()
+// Resume re-queues the unfinished and failed tasks of a scrape run under a new
+// run. With runID set to uuid.Nil the latest run of the competition is used.
+func Resume(ctx context.Context, db *DB, compID int, runID uuid.UUID, f Fetcher, wg *sync.WaitGroup) (err error) {
+	dbCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	prev, err := db.GetScrapeRun(dbCtx, compID, runID)
 	if err != nil {
-		panic(err)
+		return err
+	}
+	if prev.status == RunFinished {
+		return fmt.Errorf("scrape run %s finished cleanly, there is nothing to resume", prev.id)
 	}
-	defer db.Conn.Close() 
 
-  ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-	err = db.CreateCompIfNotExist(ctx, "Mens NRL Premiership", compID)
-	
-	seasons := f.FetchSeasons(wg)
+	seasonSel, err := ParseSelection(prev.seasons)
+	if err != nil {
+		return fmt.Errorf("scrape run %s has invalid seasons: %w", prev.id, err)
+	}
+	roundSel, err := ParseRoundSelection(prev.rounds)
+	if err != nil {
+		return fmt.Errorf("scrape run %s has invalid rounds: %w", prev.id, err)
+	}
+	cfg := &ScrapeConfig{seasons: seasonSel, rounds: roundSel}
 
+	tasks, err := db.GetUnfinishedTasks(dbCtx, prev.id)
+	if err != nil {
+		return err
+	}
+	tasks = uncoveredTasks(tasks)
+	if len(tasks) == 0 {
+		fmt.Printf("Scrape run %s has no unfinished tasks\n", prev.id)
+		return nil
+	}
+
+	fmt.Printf("Resuming scrape run %s (%s) with %d tasks\n", prev.id, prev.status, len(tasks))
+
+	seedVenues(ctx, db)
+
+	errs := NewErrorCollector()
 	stats := &StatsTracker{}
+	ledger := NewLedger(ctx, db, prev.compID, cfg, prev.id)
+	stopProgress := reportProgress(stats)
+	defer func() {
+		err = finishScrape(ctx, db, prev.compID, wg, stopProgress, errs, ledger)
+	}()
+
+	for _, t := range tasks {
+		ledger.start(ctx, t, taskStages(t)...)
+	}
+
+	for _, t := range tasks {
+		if ctx.Err() != nil {
+			break
+		}
+
+		switch t.kind {
+		case TaskSeason:
+			launchSeason(ctx, db, prev.compID, t.season, f, wg, stats, errs, ledger, cfg)
+		case TaskRound:
+			wg.Add(1)
+			go scrapeRound(ctx, db, t, prev.compID, f, wg, stats, errs, ledger)
+		case TaskMatch:
+			errs.registerMatch(t.matchID, t.url, t.season, t.roundIndex)
+			wg.Add(1)
+			go scrapeMatch(ctx, db, t, f, wg, stats, errs, ledger)
+		}
+	}
+
+	return nil
+}
+
+// seedVenues refreshes the venue registry before scraping so locations
+// resolve against the bundled data. Without it locations are still stored,
+// just not linked to a venue.
+func seedVenues(ctx context.Context, db *DB) {
+	dbCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	if err := db.SeedVenues(dbCtx); err != nil {
+		fmt.Println("unable to seed venues", err)
+	}
+}
+
+func launchSeason(ctx context.Context, db *DB, compID int, season string, f Fetcher, wg *sync.WaitGroup, stats *StatsTracker, errs *ErrorCollector, ledger *Ledger, cfg *ScrapeConfig) {
+	dbCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	seasonID, err := db.CreateSeasonIfNotExist(dbCtx, compID, season)
+	cancel()
+	if err != nil {
+		errs.Add(ScrapeError{stage: StageSeason, season: season, err: err})
+		ledger.finish(ctx, seasonTask(season), StageRounds, err)
+		return
+	}
+
+	wg.Add(1)
+	go ScrapeSeason(ctx, db, compID, season, seasonID, f, wg, stats, errs, ledger, cfg)
+}
 
+// reportProgress prints the tracker every few seconds until the returned
+// func is called.
+func reportProgress(stats *StatsTracker) func() {
 	done := make(chan struct{})
 	go func() {
 		ticker := time.NewTicker(5 * time.Second)
@@ -239,74 +445,135 @@ func Scrape(compID int, f Fetcher, wg *sync.WaitGroup) (comp Competition) {
 		}
 	}()
 
-	for _, s := range seasons {
-		wg.Add(1)
+	return func() { close(done) }
+}
 
-    ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
-		seasonID, err := db.CreateSeasonIfNotExist(ctx, compID, s)
+// finishScrape waits for every task of a run to end, then reconciles players,
+// rebuilds the ladders and ratings, closes the run in the ledger and reports
+// its errors. An interrupted scrape leaves the ladders and ratings to the
+// ladder and elo commands. Features go over the stats of every match, so they
+// are always left to the features command.
+func finishScrape(ctx context.Context, db *DB, compID int, wg *sync.WaitGroup, stopProgress func(), errs *ErrorCollector, ledger *Ledger) error {
+	wg.Wait()
+	stopProgress()
+
+	// the reconciliation runs in one transaction, so it is safe to finish
+	// even while shutting down
+	dbCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 60*time.Second)
+	merged, err := db.ReconcilePlayers(dbCtx)
+	cancel()
+	if err != nil {
+		errs.Add(ScrapeError{stage: StageReconcile, err: err})
+	} else {
+		fmt.Printf("Merged %d duplicate players\n", merged)
+	}
+
+	if ctx.Err() == nil {
+		updateDerived(ctx, db, compID, errs)
+	}
+
+	ledger.close(ctx, len(errs.Errors()) > 0)
+	reportErrors(ctx, db, compID, ledger.RunID(), errs)
+
+	if ctx.Err() != nil {
+		fmt.Println("Scrape interrupted, in-flight matches finished.")
+		return errs.Err()
+	}
+
+	fmt.Println("All jobs complete.")
+	return errs.Err()
+}
+
+// updateDerived rebuilds the tables worked out from the stored results, each
+// step with its own time limit so a slow one doesn't starve the rest.
+func updateDerived(ctx context.Context, db *DB, compID int, errs *ErrorCollector) {
+	steps := []struct {
+		stage Stage
+		timeout time.Duration
+		update func(context.Context) error
+	}{
+		{StageLadder, 60 * time.Second, func(ctx context.Context) error {
+			return UpdateLadders(ctx, db, compID, errs)
+		}},
+		{StageRatings, 60 * time.Second, func(ctx context.Context) error {
+			_, err := UpdateRatings(ctx, db, compID, elo.DefaultConfig)
+			return err
+		}},
+	}
+
+	for _, step := range steps {
+		stepCtx, cancel := context.WithTimeout(ctx, step.timeout)
+		err := step.update(stepCtx)
+		cancel()
 		if err != nil {
-			wg.Done() 
-			continue
+			errs.Add(ScrapeError{stage: step.stage, err: err})
 		}
+	}
+}
 
-		go ScrapeSeason(compID, s, seasonID, f, wg, stats)
+// reportErrors stores the collected errors and warnings and prints their
+// summary. It runs detached from ctx so an interrupted scrape still records
+// what went wrong.
+func reportErrors(ctx context.Context, db *DB, compID int, runID uuid.UUID, errs *ErrorCollector) {
+	collected := append(errs.Errors(), errs.Warnings()...)
+	if len(collected) > 0 {
+		dbCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+		defer cancel()
+
+		if err := db.SaveScrapeErrors(dbCtx, compID, runID, collected); err != nil {
+			fmt.Println("unable to save scrape errors", err)
+		}
 	}
 
-	wg.Wait()
-	close(done)
-	fmt.Println("All jobs complete.")
-	return
+	fmt.Print(errs.Summary())
 }
 
-func ScrapeSeason(compID int, season string, seasonID uuid.UUID, f Fetcher, wg *sync.WaitGroup, stats *StatsTracker) {
+func ScrapeSeason(ctx context.Context, db *DB, compID int, season string, seasonID uuid.UUID, f Fetcher, wg *sync.WaitGroup, stats *StatsTracker, errs *ErrorCollector, ledger *Ledger, cfg *ScrapeConfig) {
 	defer wg.Done()
 	stats.Start()
 	defer stats.Finish()
 
-	if season != "2024" {
+	if ctx.Err() != nil {
 		return
 	}
 
-	content := fmt.Sprintf(
-		f.Fetch(
-			fmt.Sprintf("https://www.nrl.com/draw/?competition=%d&round=1&season=%s", compID, season),
-			chromedp.Tasks{
-				chromedp.WaitVisible(`[aria-controls="round-dropdown"]`, chromedp.ByQuery),
-				chromedp.Click(`[aria-controls="round-dropdown"]`, chromedp.ByQuery),
-				chromedp.Sleep(2 * time.Second),
-			},
-			true,
-	))
+	url := fmt.Sprintf("https://www.nrl.com/draw/?competition=%d&round=1&season=%s", compID, season)
+	content, err := f.Fetch(
+		ctx,
+		url,
+		chromedp.Tasks{
+			chromedp.WaitVisible(`[aria-controls="round-dropdown"]`, chromedp.ByQuery),
+			chromedp.Click(`[aria-controls="round-dropdown"]`, chromedp.ByQuery),
+			chromedp.Sleep(2 * time.Second),
+		},
+		true,
+	)
+	if err != nil {
+		errs.Add(ScrapeError{stage: StageRounds, url: url, season: season, err: err})
+		ledger.finish(ctx, seasonTask(season), StageRounds, err)
+		return
+	}
 
 	rounds, err := f.ParseList(content, "#round-dropdown li button div")
 	if err != nil {
-		panic(err)
+		errs.Add(ScrapeError{stage: StageRounds, url: url, season: season, err: err})
+		ledger.finish(ctx, seasonTask(season), StageRounds, err)
+		return
 	}
 
 	Reverse(rounds)
 	wg.Add(1)
-	go scrapeRounds(rounds, season, seasonID, compID, f, wg, stats)
+	go scrapeRounds(ctx, db, rounds, season, seasonID, compID, f, wg, stats, errs, ledger, cfg)
 	return
 }
 
-func writeToFile (content string, fileName string) {
-	// Create or truncate the file
-	file, err := os.Create(fileName)
-	if err != nil {
-			fmt.Println("Error creating file:", err)
-			return
-	}
-	defer file.Close()
-
-	// Write to the file
-	_, err = file.WriteString(content)
-	if err != nil {
-			fmt.Println("Error writing to file:", err)
-			return
+func writeToFile(content string, fileName string) error {
+	if err := os.WriteFile(fileName, []byte(content), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", fileName, err)
 	}
 
 	fmt.Println("File written successfully.")
+	return nil
 }
 
 func Reverse[T any](arr []T) {
@@ -7,6 +7,7 @@ import (
 	"strconv"
 	"context"
 	"time"
+	"errors"
 
 	"github.com/chromedp/chromedp"
 	"github.com/PuerkitoBio/goquery"
@@ -14,18 +15,36 @@ import (
 )
 
 type Play struct {
+	index int
 	time string
 	play string
 	team string
 	notes string
+
+	eventType EventType
+	minute int
+	second int
+	timed bool
+	teamID string
+	playerID uuid.UUID
+	otherPlayerID uuid.UUID
+	points int
 }
 
 type Player struct {
+	id uuid.UUID
+	nrlID string
+	profileURL string
 	nameFirst string
 	nameLast string
 	position string
 	number int
-	playerStats PlayerStats
+	role PlayerRole
+	captain bool
+	viceCaptain bool
+	// named after the team list was first announced
+	lateChange bool
+	playerStats *PlayerStats
 }
 
 type MatchOffical struct {
@@ -38,115 +57,150 @@ type Match struct {
 	id uuid.UUID
 
 	homeTeam string
+	homeTeamID string
 	homeScore int
 	homeTeamList []*Player
 
 	awayTeam string
+	awayTeamID string
 	awayScore int
 	awayTeamList []*Player
 
 	matchOfficals []*MatchOffical
 
 	location string
-	kickoffTime string
-	datePlayed string
+	venueID string
+	kickoff time.Time
+	status MatchStatus
 	weather	string
 
 	playByPlay []*Play
+	scoreTimeline *ScoreTimeline
 
 	stats *MatchStats
 }
 
-func (p *Play) String() string {
-	return fmt.Sprintf(`
-	{ 
-		"play": "%s",
-		"team": "%s",
-		"notes": "%s",
-		"time": "%s"
-	}`, p.play, p.team, p.notes, p.time)
-}
-
-func (p *Player) String() string {
-	return fmt.Sprintf(`
-	{ 
-		"nameFirst": "%s",
-		"nameLast": "%s",
-		"position": "%s",
-		"number": %d,
-		"playerStats": ""
-	}`, p.nameFirst, p.nameLast, p.position, p.number)
-}
-
-func (m *Match) String() string {
-	homeTeamList := createListStr(m.homeTeamList)
-	awayTeamList := createListStr(m.awayTeamList)
-	playByPlay := createListStr(m.playByPlay)
-
-	return fmt.Sprintf(`
-		{ "homeTeam": "%s",
-		"homeScore": %d,
-		"homeTeamList": %s,
-		"awayTeam": "%s", 
-		"awayScore": %d,
-		"awayTeamList": %s,
-		"matchOfficals": "",
-		"location": "%s",
-		"datePlayed": "%s",
-		"weather":	"%s",
-		"playByPlay": %s,
-		"stats": %s}`,
-		m.homeTeam,
-		m.homeScore,
-		homeTeamList,
-		m.awayTeam,
-		m.awayScore,
-		awayTeamList,
-		m.location,
-		m.datePlayed,
-		m.weather,
-		playByPlay,
-		m.stats,
-	)
-}
-
-func scrapeMatch(m uuid.UUID, url string, f Fetcher, wg *sync.WaitGroup, stats *StatsTracker) {
+// scrapeMatch fetches and stores a single match. Cancelling ctx abandons the
+// fetch, leaving the match pending in the ledger, but once the page is in hand
+// the match is stored in full, so a shutdown never leaves one half written.
+func scrapeMatch(ctx context.Context, db *DB, task Task, f Fetcher, wg *sync.WaitGroup, stats *StatsTracker, errs *ErrorCollector, ledger *Ledger) {
 	defer wg.Done()
 	stats.Start()
 	defer stats.Finish()
 
+	m := task.matchID
+
 	content, err := f.Fetch(
-		fmt.Sprintf("https://www.nrl.com/%s", url),
+		ctx,
+		fmt.Sprintf("https://www.nrl.com/%s", task.url),
 		chromedp.Tasks{},
 		true,
 	)
+	if err != nil && ctx.Err() != nil {
+		return
+	}
+
+	ctx = context.WithoutCancel(ctx)
 
 	if err != nil {
+		errs.AddMatch(m, StageMatchFetch, err)
+		ledger.finish(ctx, task, StageMatchFetch, err)
 		return;
 	}
-
-	wg.Add(3)
-	go parseMatchStats(m, content, wg)
-	go parsePlaybyPlay(m, content, wg)
-	go parseTeamList(m, content, wg)
+	ledger.finish(ctx, task, StageMatchFetch, nil)
+
+	var parsers sync.WaitGroup
+	parsers.Add(4)
+	go parseMatchStats(ctx, db, m, content, &parsers, errs)
+	// plays name players, so they are parsed once the team lists are stored
+	go func() {
+		parseTeamList(ctx, db, m, task.season, content, &parsers, errs)
+		parsePlaybyPlay(ctx, db, m, task.season, content, &parsers, errs)
+		parseScoreTimeline(ctx, db, m, &parsers, errs)
+	}()
+	parsers.Wait()
+
+	for _, stage := range []Stage{StageMatchStats, StagePlayByPlay, StageTeamList} {
+		ledger.finish(ctx, task, stage, errs.matchError(m, matchStages[stage]...))
+	}
 }
 
-func parsePlaybyPlay(matchID uuid.UUID, content string, wg *sync.WaitGroup) {
+func parsePlaybyPlay(ctx context.Context, db *DB, matchID uuid.UUID, season string, content string, wg *sync.WaitGroup, errs *ErrorCollector) {
 	defer wg.Done()
 
 	doc, err := goquery.NewDocumentFromReader(strings.NewReader(content))
 	if err != nil {
+		errs.AddMatch(matchID, StagePlayByPlay, err)
 		return;
 	}
 
-	db, err := NewDB()
+	listCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	// plays are still stored, without their team or players, when the teams
+	// can't be read
+	homeTeamID, awayTeamID, err := db.GetMatchTeams(listCtx, matchID)
+	if err != nil {
+		errs.AddMatch(matchID, StagePlayByPlay, fmt.Errorf("failed to get match teams: %w", err))
+	}
+	homeTeamList, awayTeamList, err := db.GetTeamLists(listCtx, matchID)
 	if err != nil {
-		panic(err)
+		errs.AddMatch(matchID, StagePlayByPlay, fmt.Errorf("failed to get team lists: %w", err))
 	}
-	defer db.Conn.Close() 
+	bothTeamLists := append(append([]*Player{}, homeTeamList...), awayTeamList...)
+
+	// the same name is shown on every play of a team
+	teamIDs := map[string]string{}
+
+	for i, play := range extractPlays(doc) {
+		dbCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+		if play.team != "" {
+			teamID, ok := teamIDs[play.team]
+			if !ok {
+				if team, err := db.ResolveTeam(dbCtx, play.team, season); err == nil {
+					teamID = team.id
+				} else {
+					errs.AddMatch(matchID, StagePlayByPlay, fmt.Errorf("play %d: %w", i, err))
+				}
+				teamIDs[play.team] = teamID
+			}
+			play.teamID = teamID
+		}
+
+		// players are looked for in their own side when the team is known
+		players := bothTeamLists
+		switch {
+		case play.teamID == "":
+		case play.teamID == homeTeamID:
+			players = homeTeamList
+		case play.teamID == awayTeamID:
+			players = awayTeamList
+		}
+		named := eventPlayers(play.notes, players)
+		if len(named) > 0 {
+			play.playerID = named[0].id
+		}
+		if len(named) > 1 {
+			play.otherPlayerID = named[1].id
+		}
+
+		_, err := db.CreatePlay(dbCtx, matchID, i, play)
+		cancel()
+		if err != nil {
+			errs.AddMatch(matchID, StagePlayByPlay, fmt.Errorf("play %d: %w", i, err))
+			return
+		}
+	}
+}
+
+// extractPlays reads the play-by-play off a match centre page, typed and
+// timed but not yet put to a team or its players.
+func extractPlays(doc *goquery.Document) []*Play {
+	var plays []*Play
 
 	doc.Find("div.match-centre-event").Each(func(i int, b *goquery.Selection) {
-		play := &Play{}
+		play := &Play{index: i}
 		b.Find(".match-centre-event__team-name").Each(func(_ int, s *goquery.Selection) {
 			play.team = strings.TrimSpace(s.Text())
 		})
@@ -163,70 +217,142 @@ func parsePlaybyPlay(matchID uuid.UUID, content string, wg *sync.WaitGroup) {
 			play.time = strings.TrimSpace(s.Text())
 		})
 
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
+		play.eventType = classifyEvent(play.play)
+		play.points = play.eventType.Points()
+		play.minute, play.second, play.timed = parseEventClock(play.time)
 
-		wg.Add(1)
-		go func () {
-			defer wg.Done()
-			db.CreatePlay(ctx, matchID, i, play.time, play.play, play.team, play.notes)
-		}()
+		plays = append(plays, play)
 	})
+
+	return plays
 }
 
-func parseTeamList(matchID uuid.UUID, content string, wg *sync.WaitGroup) {
+func parseTeamList(ctx context.Context, db *DB, matchID uuid.UUID, season string, content string, wg *sync.WaitGroup, errs *ErrorCollector) {
 	defer wg.Done()
 
 	var doc *goquery.Document
 	doc, err := goquery.NewDocumentFromReader(strings.NewReader(content))
-	homeTeamList, awayTeamList, err := ExtractTeamPlayers(doc)
+	if err != nil {
+		errs.AddMatch(matchID, StageTeamList, err)
+		return
+	}
 
-	db, err := NewDB()
+	homeTeamList, awayTeamList, err := ExtractTeamPlayers(doc)
 	if err != nil {
+		errs.AddMatch(matchID, StageTeamList, err)
 		return
 	}
-	defer db.Conn.Close() 
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	homeStats, awayStats, err := ExtractPlayerStats(doc)
+	if err != nil {
+		errs.AddMatch(matchID, StagePlayerStats, err)
+	} else {
+		assignPlayerStats(homeTeamList, homeStats)
+		assignPlayerStats(awayTeamList, awayStats)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
+	if err := db.SetTeamLists(ctx, matchID, homeTeamList, awayTeamList); err != nil {
+		errs.AddMatch(matchID, StageTeamList, err)
+	}
+
+	sel := doc.Find(".match-team__score.match-team__score--home").First()
+	text := sel.Clone().Children().Remove().End().Text()
+	trimmed := strings.TrimSpace(text)
+	score, err := strconv.Atoi(trimmed)
+	if err == nil {
+		if err := db.SetHomeScore(ctx, matchID, score); err != nil {
+			errs.AddMatch(matchID, StageMatchDetails, err)
+		}
+	}
+
+	sel = doc.Find(".match-team__score.match-team__score--away").First()
+	text = sel.Clone().Children().Remove().End().Text()
+	trimmed = strings.TrimSpace(text)
+	score, err = strconv.Atoi(trimmed)
+	if err == nil {
+		if err := db.SetAwayScore(ctx, matchID, score); err != nil {
+			errs.AddMatch(matchID, StageMatchDetails, err)
+		}
+	}
+
+	sel = doc.Find(".match-venue.o-text").First()
+	text = sel.Clone().Children().Remove().End().Text()
+	location := strings.TrimSpace(text)
+	if location != "" {
+		// the location of an unknown venue is still stored, so it is only
+		// a warning
+		err := db.SetLocation(ctx, matchID, location)
+		if errors.Is(err, ErrUnknownVenue) {
+			errs.WarnMatch(matchID, StageVenue, err)
+		} else if err != nil {
+			errs.AddMatch(matchID, StageMatchDetails, err)
+		}
+	}
+
+	kickoff, err := parseKickoff(doc.Selection, season)
+	if err == nil {
+		err = db.SetKickoff(ctx, matchID, kickoff)
+	}
 	if err != nil {
-		db.SetTeamLists(ctx, matchID, homeTeamList, awayTeamList)
-		sel := doc.Find(".match-team__score.match-team__score--home").First()
-		text := sel.Clone().Children().Remove().End().Text()
-		trimmed := strings.TrimSpace(text)
-		score, err := strconv.Atoi(trimmed)
-		if err == nil {
-			db.SetHomeScore(ctx, matchID, score)
+		errs.AddMatch(matchID, StageMatchDetails, err)
+	}
+
+	doc.Find("p.match-weather__text").Each(func(i int, s *goquery.Selection) {
+		if strings.Contains(s.Text(), "Weather:") {
+			if err := db.SetWeather(ctx, matchID, strings.TrimSpace(s.Find("span").Text())); err != nil {
+				errs.AddMatch(matchID, StageMatchDetails, err)
+			}
 		}
+	})
 
-		sel = doc.Find(".match-team__score.match-team__score--away").First()
-		text = sel.Clone().Children().Remove().End().Text()
-		trimmed = strings.TrimSpace(text)
-		score, err = strconv.Atoi(trimmed)
-		if err == nil {
-			db.SetAwayScore(ctx, matchID, score)
+	officials := ExtractMatchOfficials(doc)
+	if len(officials) > 0 {
+		if err := db.SetMatchOfficials(ctx, matchID, officials); err != nil {
+			errs.AddMatch(matchID, StageOfficials, err)
 		}
+	}
+}
 
-		sel = doc.Find(".match-venue.o-text").First()
-		text = sel.Clone().Children().Remove().End().Text()
-		location := strings.TrimSpace(text)
-		if err == nil {
-			db.SetLocation(ctx, matchID, location)
+func ExtractMatchOfficials(doc *goquery.Document) []*MatchOffical {
+	var officials []*MatchOffical
+
+	doc.Find(".match-officials__official, .team-list-officials__item").Each(func(_ int, s *goquery.Selection) {
+		role := strings.Join(strings.Fields(s.Find("[class*='__role'], [class*='__position']").First().Text()), " ")
+		name := strings.Fields(s.Find("[class*='__name']").First().Text())
+
+		if len(name) == 0 {
+			return
 		}
 
-		sel = doc.Find("p.match-header__title").First()
-		dateStr := strings.TrimSpace(sel.Text())
-		if dateStr != "" {
-			db.SetDatePlayed(ctx, matchID, dateStr)
+		o := &MatchOffical{
+			nameFirst: name[0],
+			nameLast: strings.Join(name[1:], " "),
+			role: normaliseOfficialRole(role),
 		}
+		officials = append(officials, o)
+	})
 
-		doc.Find("p.match-weather__text").Each(func(i int, s *goquery.Selection) {
-			if strings.Contains(s.Text(), "Weather:") {
-				db.SetWeather(ctx, matchID, strings.TrimSpace(s.Find("span").Text()))
-			}
-		})
+	return officials
+}
+
+func normaliseOfficialRole(role string) string {
+	r := strings.ToLower(role)
+
+	switch {
+	case strings.Contains(r, "bunker") || strings.Contains(r, "video"):
+		return "Bunker Official"
+	case strings.Contains(r, "senior review"):
+		return "Senior Review Official"
+	case strings.Contains(r, "touch"):
+		return "Touch Judge"
+	case strings.Contains(r, "referee"):
+		return "Referee"
 	}
+
+	return role
 }
 
 func ExtractTeamPlayers(doc *goquery.Document) ([]*Player, []*Player, error) {
@@ -264,29 +390,41 @@ func ExtractTeamPlayers(doc *goquery.Document) ([]*Player, []*Player, error) {
 			number: awayNumber,
 		}
 
-		b.Find(".team-list-profile:not(.team-list-profile--away) > div.team-list-profile-content > div.team-list-profile__name").Each(func(_ int, s *goquery.Selection) {
-			str := strings.TrimSpace(s.Text())
-			name := strings.Fields(str)
-			
-			if len(name) >= 2 {
-				pHome.nameFirst = name[0]
-				pHome.nameLast = name[1]
-			}
+		b.Find(".team-list-profile:not(.team-list-profile--away)").First().Each(func(_ int, s *goquery.Selection) {
+			readTeamListProfile(pHome, s)
 		})
 
-		b.Find(".team-list-profile:not(.team-list-profile--home) > div.team-list-profile-content > div.team-list-profile__name").Each(func(_ int, s *goquery.Selection) {
-			str := strings.TrimSpace(s.Text())
-			name := strings.Fields(str)
-
-			if len(name) >= 2 {
-				pAway.nameFirst = name[0]
-				pAway.nameLast = name[1]
-			}
+		b.Find(".team-list-profile:not(.team-list-profile--home)").First().Each(func(_ int, s *goquery.Selection) {
+			readTeamListProfile(pAway, s)
 		})
 
-		hPlayers = append(hPlayers, pHome)
-		aPlayers = append(aPlayers, pAway)
+		pHome.role = playerRole(position, pHome.number)
+		pAway.role = playerRole(position, pAway.number)
+
+		// a side with nobody named in this slot has no player to add
+		if pHome.nameFirst != "" || pHome.nameLast != "" {
+			hPlayers = append(hPlayers, pHome)
+		}
+		if pAway.nameFirst != "" || pAway.nameLast != "" {
+			aPlayers = append(aPlayers, pAway)
+		}
 	})
 
 	return hPlayers, aPlayers, nil
+}
+
+// profileLink finds the player's nrl.com profile url in a team list entry.
+// Profile urls are /players/<competition>/<club>/<slug>/ and the slug is the
+// only part that survives a player changing clubs, so it is used as their id.
+func profileLink(s *goquery.Selection) (string, string) {
+	href, ok := s.Attr("href")
+	if !ok || !strings.Contains(href, "/players/") {
+		href, ok = s.Find(`a[href*="/players/"]`).First().Attr("href")
+	}
+	if !ok {
+		return "", ""
+	}
+
+	parts := strings.Split(strings.Trim(href, "/"), "/")
+	return href, parts[len(parts)-1]
 }
\ No newline at end of file
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractMatchOfficials(t *testing.T) {
+	tests := []struct {
+		name string
+		url string
+		want []MatchOffical
+	}{
+		{
+			"match officials",
+			matchFixture,
+			[]MatchOffical{
+				{"Ashley", "Klein", "Referee"},
+				{"Drew", "Oultram", "Touch Judge"},
+				{"Grant", "Atkins", "Bunker Official"},
+			},
+		},
+		{
+			"team list officials",
+			"https://www.nrl.com//draw/nrl-premiership/2024/round-1/storm-v-panthers/",
+			[]MatchOffical{
+				{"Grant", "Atkins", "Referee"},
+				{"Chris", "Butler", "Touch Judge"},
+				{"Ben", "Cummins", "Bunker Official"},
+				{"Jared", "Maxwell", "Senior Review Official"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []MatchOffical
+			for _, o := range ExtractMatchOfficials(fixtureDoc(t, tt.url)) {
+				got = append(got, *o)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("officials = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
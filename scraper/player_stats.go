@@ -1,12 +1,22 @@
 package main
 
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
 type PlayerStats struct {
+	minutesPlayed int
+
 	points int
 	tries int
 	conversions int
 	conversionAttempted int
 	penGoals int
-	conversionRate int
+	conversionRate float64
 	feildGoal1 int
 	feildGoal2 int
 
@@ -21,7 +31,7 @@ type PlayerStats struct {
 	lineEngagedRuns int
 	tackleBreaks int
 	hitUps int
-	AvgPlayBallSpeed int
+	AvgPlayBallSpeed float64
 	dummyHalfRuns int
 	dummyHalfRunMeters int
 	oneOnOneSteal int
@@ -32,7 +42,7 @@ type PlayerStats struct {
 	receipts int
 	passesToRunRatio float64
 
-	tackleEff int
+	tackleEff float64
 	tacklesMade int
 	tacklesMissed int
 	ineffectiveTackles int
@@ -59,3 +69,261 @@ type PlayerStats struct {
 	sinBins int
 	sendOffs int
 }
+
+// newPlayerStats returns stats with every field unknown, so a column the
+// table doesn't have is stored as missing rather than as 0.
+func newPlayerStats() *PlayerStats {
+	return &PlayerStats{
+		minutesPlayed: -1,
+		points: -1,
+		tries: -1,
+		conversions: -1,
+		conversionAttempted: -1,
+		penGoals: -1,
+		conversionRate: -1,
+		feildGoal1: -1,
+		feildGoal2: -1,
+		runs: -1,
+		runMeters: -1,
+		kickReturnMeters: -1,
+		postContactMeters: -1,
+		lineBreaks: -1,
+		lineBreakAssists: -1,
+		tryAssists: -1,
+		lineEngagedRuns: -1,
+		tackleBreaks: -1,
+		hitUps: -1,
+		AvgPlayBallSpeed: -1,
+		dummyHalfRuns: -1,
+		dummyHalfRunMeters: -1,
+		oneOnOneSteal: -1,
+		offloads: -1,
+		dummyPasses: -1,
+		passes: -1,
+		receipts: -1,
+		passesToRunRatio: -1,
+		tackleEff: -1,
+		tacklesMade: -1,
+		tacklesMissed: -1,
+		ineffectiveTackles: -1,
+		intercepts: -1,
+		kicksDefused: -1,
+		kicks: -1,
+		kickMeters: -1,
+		forcedDropOuts: -1,
+		bombKicks: -1,
+		grubbers: -1,
+		fourtyTwenty: -1,
+		twentyFourty: -1,
+		crossFieldKicks: -1,
+		kicksDead: -1,
+		errors: -1,
+		handlingErr: -1,
+		OneOnOneLost: -1,
+		pen: -1,
+		ruckInf: -1,
+		inside10: -1,
+		onReport: -1,
+		sinBins: -1,
+		sendOffs: -1,
+	}
+}
+
+type PlayerStatsRow struct {
+	name string
+	number int
+	stats *PlayerStats
+}
+
+// playerStatHandlers maps a lower-cased column header of the match centre
+// player stats table onto the PlayerStats field it fills.
+var playerStatHandlers = map[string]func(ps *PlayerStats, v string){
+	"mins played": func(ps *PlayerStats, v string) { ps.minutesPlayed = parseStatInt(v) },
+	"minutes played": func(ps *PlayerStats, v string) { ps.minutesPlayed = parseStatInt(v) },
+	"points": func(ps *PlayerStats, v string) { ps.points = parseStatInt(v) },
+	"total points": func(ps *PlayerStats, v string) { ps.points = parseStatInt(v) },
+	"tries": func(ps *PlayerStats, v string) { ps.tries = parseStatInt(v) },
+	"conversions": func(ps *PlayerStats, v string) { ps.conversions = parseStatInt(v) },
+	"conversion attempts": func(ps *PlayerStats, v string) { ps.conversionAttempted = parseStatInt(v) },
+	"penalty goals": func(ps *PlayerStats, v string) { ps.penGoals = parseStatInt(v) },
+	"goal conversion rate": func(ps *PlayerStats, v string) { ps.conversionRate = parseStatFloat(v) },
+	"1 point field goals": func(ps *PlayerStats, v string) { ps.feildGoal1 = parseStatInt(v) },
+	"2 point field goals": func(ps *PlayerStats, v string) { ps.feildGoal2 = parseStatInt(v) },
+	"all runs": func(ps *PlayerStats, v string) { ps.runs = parseStatInt(v) },
+	"all run metres": func(ps *PlayerStats, v string) { ps.runMeters = parseStatInt(v) },
+	"kick return metres": func(ps *PlayerStats, v string) { ps.kickReturnMeters = parseStatInt(v) },
+	"post contact metres": func(ps *PlayerStats, v string) { ps.postContactMeters = parseStatInt(v) },
+	"line breaks": func(ps *PlayerStats, v string) { ps.lineBreaks = parseStatInt(v) },
+	"line break assists": func(ps *PlayerStats, v string) { ps.lineBreakAssists = parseStatInt(v) },
+	"try assists": func(ps *PlayerStats, v string) { ps.tryAssists = parseStatInt(v) },
+	"line engaged runs": func(ps *PlayerStats, v string) { ps.lineEngagedRuns = parseStatInt(v) },
+	"tackle breaks": func(ps *PlayerStats, v string) { ps.tackleBreaks = parseStatInt(v) },
+	"hit ups": func(ps *PlayerStats, v string) { ps.hitUps = parseStatInt(v) },
+	"average play the ball speed": func(ps *PlayerStats, v string) { ps.AvgPlayBallSpeed = parseStatFloat(v) },
+	"dummy half runs": func(ps *PlayerStats, v string) { ps.dummyHalfRuns = parseStatInt(v) },
+	"dummy half run metres": func(ps *PlayerStats, v string) { ps.dummyHalfRunMeters = parseStatInt(v) },
+	"one on one steal": func(ps *PlayerStats, v string) { ps.oneOnOneSteal = parseStatInt(v) },
+	"offloads": func(ps *PlayerStats, v string) { ps.offloads = parseStatInt(v) },
+	"dummy passes": func(ps *PlayerStats, v string) { ps.dummyPasses = parseStatInt(v) },
+	"passes": func(ps *PlayerStats, v string) { ps.passes = parseStatInt(v) },
+	"receipts": func(ps *PlayerStats, v string) { ps.receipts = parseStatInt(v) },
+	"passes to run ratio": func(ps *PlayerStats, v string) { ps.passesToRunRatio = parseStatFloat(v) },
+	"tackle efficiency": func(ps *PlayerStats, v string) { ps.tackleEff = parseStatFloat(v) },
+	"tackles made": func(ps *PlayerStats, v string) { ps.tacklesMade = parseStatInt(v) },
+	"missed tackles": func(ps *PlayerStats, v string) { ps.tacklesMissed = parseStatInt(v) },
+	"ineffective tackles": func(ps *PlayerStats, v string) { ps.ineffectiveTackles = parseStatInt(v) },
+	"intercepts": func(ps *PlayerStats, v string) { ps.intercepts = parseStatInt(v) },
+	"kicks defused": func(ps *PlayerStats, v string) { ps.kicksDefused = parseStatInt(v) },
+	"kicks": func(ps *PlayerStats, v string) { ps.kicks = parseStatInt(v) },
+	"kicking metres": func(ps *PlayerStats, v string) { ps.kickMeters = parseStatInt(v) },
+	"forced drop outs": func(ps *PlayerStats, v string) { ps.forcedDropOuts = parseStatInt(v) },
+	"bomb kicks": func(ps *PlayerStats, v string) { ps.bombKicks = parseStatInt(v) },
+	"grubbers": func(ps *PlayerStats, v string) { ps.grubbers = parseStatInt(v) },
+	"40/20": func(ps *PlayerStats, v string) { ps.fourtyTwenty = parseStatInt(v) },
+	"20/40": func(ps *PlayerStats, v string) { ps.twentyFourty = parseStatInt(v) },
+	"cross field kicks": func(ps *PlayerStats, v string) { ps.crossFieldKicks = parseStatInt(v) },
+	"kicked dead": func(ps *PlayerStats, v string) { ps.kicksDead = parseStatInt(v) },
+	"errors": func(ps *PlayerStats, v string) { ps.errors = parseStatInt(v) },
+	"handling errors": func(ps *PlayerStats, v string) { ps.handlingErr = parseStatInt(v) },
+	"one on one lost": func(ps *PlayerStats, v string) { ps.OneOnOneLost = parseStatInt(v) },
+	"penalties": func(ps *PlayerStats, v string) { ps.pen = parseStatInt(v) },
+	"ruck infringements": func(ps *PlayerStats, v string) { ps.ruckInf = parseStatInt(v) },
+	"inside 10 metres": func(ps *PlayerStats, v string) { ps.inside10 = parseStatInt(v) },
+	"on report": func(ps *PlayerStats, v string) { ps.onReport = parseStatInt(v) },
+	"sin bins": func(ps *PlayerStats, v string) { ps.sinBins = parseStatInt(v) },
+	"send offs": func(ps *PlayerStats, v string) { ps.sendOffs = parseStatInt(v) },
+}
+
+// parseStatInt reads a stat cell, returning -1 for one that isn't a number
+// (such as "-") so it is stored as missing like the table defaults, not as 0.
+func parseStatInt(v string) int {
+	v = cleanStat(v)
+
+	// minutes are shown as mm:ss
+	if mins, _, ok := strings.Cut(v, ":"); ok {
+		v = mins
+	}
+
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return -1
+		}
+		return int(f)
+	}
+
+	return n
+}
+
+func parseStatFloat(v string) float64 {
+	f, err := strconv.ParseFloat(cleanStat(v), 64)
+	if err != nil {
+		return -1
+	}
+	return f
+}
+
+func cleanStat(v string) string {
+	v = strings.TrimSpace(v)
+	v = strings.ReplaceAll(v, ",", "")
+	v = strings.TrimSuffix(v, "%")
+	v = strings.TrimSuffix(v, "s")
+	return strings.TrimSpace(v)
+}
+
+// ExtractPlayerStats reads the match centre Player Stats tables, the first of
+// which is the home team and the second the away team.
+func ExtractPlayerStats(doc *goquery.Document) ([]PlayerStatsRow, []PlayerStatsRow, error) {
+	var tables []*goquery.Selection
+
+	doc.Find("table").Each(func(_ int, t *goquery.Selection) {
+		for _, h := range tableHeaders(t) {
+			if h == "tries" {
+				tables = append(tables, t)
+				return
+			}
+		}
+	})
+
+	if len(tables) < 2 {
+		return nil, nil, fmt.Errorf("player stats tables not found")
+	}
+
+	return parsePlayerStatsTable(tables[0]), parsePlayerStatsTable(tables[1]), nil
+}
+
+func tableHeaders(t *goquery.Selection) []string {
+	var headers []string
+
+	t.Find("thead tr").Last().Find("th").Each(func(_ int, s *goquery.Selection) {
+		headers = append(headers, strings.ToLower(strings.Join(strings.Fields(s.Text()), " ")))
+	})
+
+	return headers
+}
+
+func parsePlayerStatsTable(t *goquery.Selection) []PlayerStatsRow {
+	headers := tableHeaders(t)
+
+	var rows []PlayerStatsRow
+	t.Find("tbody tr").Each(func(_ int, tr *goquery.Selection) {
+		row := PlayerStatsRow{stats: newPlayerStats()}
+
+		tr.Find("td, th").Each(func(i int, td *goquery.Selection) {
+			if i >= len(headers) {
+				return
+			}
+
+			value := strings.Join(strings.Fields(td.Text()), " ")
+
+			switch headers[i] {
+			case "player", "players":
+				row.name = value
+			case "number", "#", "no.":
+				row.number, _ = strconv.Atoi(value)
+			default:
+				if handler, ok := playerStatHandlers[headers[i]]; ok {
+					handler(row.stats, value)
+				}
+			}
+		})
+
+		if row.name != "" {
+			rows = append(rows, row)
+		}
+	})
+
+	return rows
+}
+
+// assignPlayerStats attaches parsed stats rows to the team list, matching on
+// jersey number first and falling back to the player's name.
+func assignPlayerStats(players []*Player, rows []PlayerStatsRow) {
+	for _, row := range rows {
+		var match *Player
+		for _, p := range players {
+			if row.number != 0 && p.number == row.number {
+				match = p
+				break
+			}
+		}
+
+		if match == nil {
+			for _, p := range players {
+				if samePlayerName(row.name, p.nameFirst+" "+p.nameLast) {
+					match = p
+					break
+				}
+			}
+		}
+
+		if match != nil {
+			match.playerStats = row.stats
+		}
+	}
+}
+
+func samePlayerName(a, b string) bool {
+	return strings.EqualFold(strings.Join(strings.Fields(a), " "), strings.Join(strings.Fields(b), " "))
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func TestExtractPlayerStats(t *testing.T) {
+	home, away, err := ExtractPlayerStats(fixtureDoc(t, matchFixture))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(home) != 3 || len(away) != 2 {
+		t.Fatalf("got %d home and %d away rows, want 3 and 2", len(home), len(away))
+	}
+
+	tests := []struct {
+		row PlayerStatsRow
+		name string
+		number int
+		minutes int
+		tries int
+		runMeters int
+		tackleEff float64
+		conversionRate float64
+	}{
+		{home[0], "Tom Trbojevic", 1, 80, 2, 1034, 87.5, -1},
+		{home[1], "Daly Cherry-Evans", 7, 80, 0, 41, 90, 66.7},
+		// "-" is a stat nrl.com doesn't have, stored as missing
+		{home[2], "Josh Aloiai", 14, 27, -1, 88, 100, -1},
+		{away[0], "Latrell Mitchell", 1, 80, 1, 162, 75, 100},
+		{away[1], "Davvy Moale", 15, 31, 0, 76, 95.2, -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ps := tt.row.stats
+			if tt.row.name != tt.name || tt.row.number != tt.number {
+				t.Errorf("row = %s #%d, want %s #%d", tt.row.name, tt.row.number, tt.name, tt.number)
+			}
+			if ps.minutesPlayed != tt.minutes || ps.tries != tt.tries || ps.runMeters != tt.runMeters {
+				t.Errorf("minutes, tries, run metres = %d, %d, %d, want %d, %d, %d", ps.minutesPlayed, ps.tries, ps.runMeters, tt.minutes, tt.tries, tt.runMeters)
+			}
+			if ps.tackleEff != tt.tackleEff || ps.conversionRate != tt.conversionRate {
+				t.Errorf("tackle efficiency, conversion rate = %v, %v, want %v, %v", ps.tackleEff, ps.conversionRate, tt.tackleEff, tt.conversionRate)
+			}
+		})
+	}
+}
+
+func TestParsePlayerStatsTableMissingColumns(t *testing.T) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(`<table>
+		<thead><tr><th>Player</th><th>Number</th><th>Tries</th></tr></thead>
+		<tbody><tr><td>Tom Trbojevic</td><td>1</td><td>2</td></tr></tbody>
+	</table>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows := parsePlayerStatsTable(doc.Find("table"))
+	if len(rows) != 1 {
+		t.Fatalf("got %d rows, want 1", len(rows))
+	}
+
+	// columns the table doesn't have are unknown, not 0
+	ps := rows[0].stats
+	if ps.tries != 2 {
+		t.Errorf("tries = %d, want 2", ps.tries)
+	}
+	if ps.minutesPlayed != -1 || ps.lineBreaks != -1 || ps.kicks != -1 || ps.tackleEff != -1 || ps.sendOffs != -1 {
+		t.Errorf("minutes, line breaks, kicks, tackle efficiency, send offs = %d, %d, %d, %v, %d, want all -1", ps.minutesPlayed, ps.lineBreaks, ps.kicks, ps.tackleEff, ps.sendOffs)
+	}
+}
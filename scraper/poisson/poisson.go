@@ -0,0 +1,327 @@
+// Package poisson predicts match scores from team attack and defence
+// strengths fitted to past results.
+//
+// Rugby league points come in tries, conversions and penalty goals worth 4, 2
+// and 2, not one at a time, so they are spread far wider than a Poisson
+// with the same mean. The model gets the means right but its distributions
+// are too narrow: margin bands, draws and totals far from the mean come out
+// overconfident.
+package poisson
+
+import (
+	"math"
+	"time"
+)
+
+type Config struct {
+	// age at which a result counts half as much as one played today
+	HalfLife time.Duration
+	// fitting passes over the results
+	Iterations int
+	// points shared by both scores, the bivariate covariance. Zero treats
+	// the scores as independent, and a negative value estimates it from the
+	// results
+	Covariance float64
+	// scores above this are left out of the distribution
+	MaxScore int
+}
+
+var DefaultConfig = Config{
+	HalfLife: 365 * 24 * time.Hour,
+	Iterations: 50,
+	Covariance: -1,
+	MaxScore: 100,
+}
+
+// strengths are kept above this so a team that hasn't scored, or hasn't
+// conceded, doesn't end up with a zero the normalising can't take the log of
+const minStrength = 0.01
+
+type Match struct {
+	Kickoff time.Time
+	Home string
+	Away string
+	HomeScore int
+	AwayScore int
+	Neutral bool
+}
+
+// Model gives each team's expected score as
+// base * home advantage * attack of the team * defence of the opponent.
+type Model struct {
+	cfg Config
+	base float64
+	homeAdvantage float64
+	covariance float64
+	attack map[string]float64
+	defence map[string]float64
+}
+
+// weighted is a match with the weight its age gives it.
+type weighted struct {
+	Match
+	w float64
+}
+
+// Fit estimates strengths from the matches played before at, weighting each
+// by its age so form counts for more. Later matches, and ones without a
+// kickoff to age them by, are ignored, so a model fitted at a kickoff never
+// sees the result it predicts.
+func Fit(cfg Config, matches []Match, at time.Time) *Model {
+	var ms []weighted
+	for _, m := range matches {
+		if m.Kickoff.IsZero() || !m.Kickoff.Before(at) {
+			continue
+		}
+		w := 1.0
+		if cfg.HalfLife > 0 {
+			w = math.Exp(-math.Ln2 * float64(at.Sub(m.Kickoff)) / float64(cfg.HalfLife))
+		}
+		ms = append(ms, weighted{Match: m, w: w})
+	}
+
+	model := &Model{
+		cfg: cfg,
+		base: 1,
+		homeAdvantage: 1,
+		covariance: math.Max(0, cfg.Covariance),
+		attack: map[string]float64{},
+		defence: map[string]float64{},
+	}
+	if len(ms) == 0 {
+		return model
+	}
+
+	var points, weights float64
+	for _, m := range ms {
+		model.attack[m.Home], model.attack[m.Away] = 1, 1
+		model.defence[m.Home], model.defence[m.Away] = 1, 1
+		points += m.w * float64(m.HomeScore+m.AwayScore)
+		weights += 2 * m.w
+	}
+	model.base = points / weights
+
+	home := func(m weighted) float64 {
+		if m.Neutral {
+			return 1
+		}
+		return model.homeAdvantage
+	}
+
+	for i := 0; i < cfg.Iterations; i++ {
+		scored, conceded := map[string]float64{}, map[string]float64{}
+		attackExp, defenceExp := map[string]float64{}, map[string]float64{}
+		for _, m := range ms {
+			h := home(m)
+			scored[m.Home] += m.w * float64(m.HomeScore)
+			scored[m.Away] += m.w * float64(m.AwayScore)
+			conceded[m.Home] += m.w * float64(m.AwayScore)
+			conceded[m.Away] += m.w * float64(m.HomeScore)
+			attackExp[m.Home] += m.w * model.base * h * model.defence[m.Away]
+			attackExp[m.Away] += m.w * model.base * model.defence[m.Home]
+			defenceExp[m.Home] += m.w * model.base * model.attack[m.Away]
+			defenceExp[m.Away] += m.w * model.base * h * model.attack[m.Home]
+		}
+		for team := range model.attack {
+			if attackExp[team] > 0 {
+				model.attack[team] = math.Max(minStrength, scored[team]/attackExp[team])
+			}
+			if defenceExp[team] > 0 {
+				model.defence[team] = math.Max(minStrength, conceded[team]/defenceExp[team])
+			}
+		}
+
+		var homeScored, homeExp float64
+		for _, m := range ms {
+			if m.Neutral {
+				continue
+			}
+			homeScored += m.w * float64(m.HomeScore)
+			homeExp += m.w * model.base * model.attack[m.Home] * model.defence[m.Away]
+		}
+		if homeExp > 0 {
+			model.homeAdvantage = homeScored / homeExp
+		}
+
+		model.normalise()
+	}
+
+	if cfg.Covariance < 0 {
+		model.covariance = estimateCovariance(model, ms)
+	}
+
+	return model
+}
+
+// estimateCovariance is the weighted sample covariance of the two scores
+// around their fitted means. Scores that move apart give a negative
+// covariance, which the bivariate Poisson can't hold, so it is floored at 0.
+func estimateCovariance(model *Model, ms []weighted) float64 {
+	var sum, weights float64
+	for _, m := range ms {
+		homeMean, awayMean := model.Expected(m.Home, m.Away, m.Neutral)
+		sum += m.w * (float64(m.HomeScore) - homeMean) * (float64(m.AwayScore) - awayMean)
+		weights += m.w
+	}
+	if weights == 0 {
+		return 0
+	}
+
+	return math.Max(0, sum/weights)
+}
+
+// normalise keeps the geometric mean of attack and defence at one, moving
+// the scale into base so the strengths read as relative to an average team.
+func (m *Model) normalise() {
+	for _, strengths := range []map[string]float64{m.attack, m.defence} {
+		var logSum float64
+		for _, s := range strengths {
+			logSum += math.Log(s)
+		}
+		mean := math.Exp(logSum / float64(len(strengths)))
+		for team := range strengths {
+			strengths[team] /= mean
+		}
+		m.base *= mean
+	}
+}
+
+func strength(strengths map[string]float64, team string) float64 {
+	if s, ok := strengths[team]; ok {
+		return s
+	}
+
+	return 1
+}
+
+// Expected returns the mean score of each team in a fixture. Teams without
+// results are treated as average.
+func (m *Model) Expected(home, away string, neutral bool) (float64, float64) {
+	h := m.homeAdvantage
+	if neutral {
+		h = 1
+	}
+
+	return m.base * h * strength(m.attack, home) * strength(m.defence, away),
+		m.base * strength(m.attack, away) * strength(m.defence, home)
+}
+
+// Predict returns the score distribution of a fixture.
+func (m *Model) Predict(home, away string, neutral bool) Distribution {
+	homeMean, awayMean := m.Expected(home, away, neutral)
+	return NewDistribution(homeMean, awayMean, m.covariance, m.cfg.MaxScore)
+}
+
+// Distribution holds the chance of every score up to a maximum.
+type Distribution struct {
+	p [][]float64
+}
+
+func pmf(mean float64, max int) []float64 {
+	p := make([]float64, max+1)
+	p[0] = math.Exp(-mean)
+	for x := 1; x <= max; x++ {
+		p[x] = p[x-1] * mean / float64(x)
+	}
+
+	return p
+}
+
+// NewDistribution builds a bivariate Poisson distribution with the given
+// means. The covariance is capped below the smaller mean.
+func NewDistribution(homeMean, awayMean, covariance float64, max int) Distribution {
+	cov := math.Max(0, math.Min(covariance, 0.99*math.Min(homeMean, awayMean)))
+	h, a, c := pmf(homeMean-cov, max), pmf(awayMean-cov, max), pmf(cov, max)
+
+	d := Distribution{p: make([][]float64, max+1)}
+	var total float64
+	for x := 0; x <= max; x++ {
+		d.p[x] = make([]float64, max+1)
+		for y := 0; y <= max; y++ {
+			// the shared k points are added to both scores
+			var p float64
+			for k := 0; k <= min(x, y); k++ {
+				p += c[k] * h[x-k] * a[y-k]
+			}
+			d.p[x][y] = p
+			total += p
+		}
+	}
+
+	// what lies past the maximum is spread back over the kept scores
+	for x := range d.p {
+		for y := range d.p[x] {
+			d.p[x][y] /= total
+		}
+	}
+
+	return d
+}
+
+func (d Distribution) Prob(home, away int) float64 {
+	if home < 0 || away < 0 || home >= len(d.p) || away >= len(d.p) {
+		return 0
+	}
+
+	return d.p[home][away]
+}
+
+func (d Distribution) sum(keep func(home, away int) bool) float64 {
+	var p float64
+	for x := range d.p {
+		for y := range d.p[x] {
+			if keep(x, y) {
+				p += d.p[x][y]
+			}
+		}
+	}
+
+	return p
+}
+
+// Outcome returns the chances of a home win, a draw and an away win.
+func (d Distribution) Outcome() (float64, float64, float64) {
+	return d.sum(func(h, a int) bool { return h > a }),
+		d.sum(func(h, a int) bool { return h == a }),
+		d.sum(func(h, a int) bool { return h < a })
+}
+
+// Margin is the chance the home margin falls between from and to inclusive.
+// Away wins are negative margins.
+func (d Distribution) Margin(from, to int) float64 {
+	return d.sum(func(h, a int) bool { return h-a >= from && h-a <= to })
+}
+
+// Over is the chance the total points go over the line.
+func (d Distribution) Over(line float64) float64 {
+	return d.sum(func(h, a int) bool { return float64(h+a) > line })
+}
+
+func (d Distribution) Under(line float64) float64 {
+	return d.sum(func(h, a int) bool { return float64(h+a) < line })
+}
+
+// Band is a range of winning margins, with To zero for open ended.
+type Band struct {
+	From int
+	To int
+}
+
+// MarginBands are the bands margin markets are usually offered in.
+var MarginBands = []Band{{From: 1, To: 12}, {From: 13, To: 0}}
+
+// BandProbabilities returns the chance of each band for the home and then the
+// away team.
+func (d Distribution) BandProbabilities(bands []Band) ([]float64, []float64) {
+	home, away := make([]float64, len(bands)), make([]float64, len(bands))
+	for i, b := range bands {
+		to := b.To
+		if to == 0 {
+			to = len(d.p)
+		}
+		home[i] = d.Margin(b.From, to)
+		away[i] = d.Margin(-to, -b.From)
+	}
+
+	return home, away
+}
@@ -0,0 +1,216 @@
+package poisson
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const tolerance = 1e-9
+
+func poissonProb(mean float64, k int) float64 {
+	return math.Exp(-mean) * math.Pow(mean, float64(k)) / math.Gamma(float64(k)+1)
+}
+
+func TestNewDistributionSumsToOne(t *testing.T) {
+	tests := []struct {
+		name string
+		home, away, cov float64
+		max int
+	}{
+		{"independent", 24, 18, 0, 100},
+		{"covariance", 24, 18, 5, 100},
+		{"truncated", 24, 18, 0, 30},
+		{"no points", 0, 0, 0, 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewDistribution(tt.home, tt.away, tt.cov, tt.max)
+			if sum := d.sum(func(int, int) bool { return true }); math.Abs(sum-1) > tolerance {
+				t.Errorf("probabilities sum to %v, want 1", sum)
+			}
+		})
+	}
+}
+
+func TestDistributionMarkets(t *testing.T) {
+	const homeMean, awayMean, max = 3.0, 2.0, 40
+	d := NewDistribution(homeMean, awayMean, 0, max)
+
+	var homeWin, draw, awayWin, homeBy1to2, over4 float64
+	for x := 0; x <= max; x++ {
+		for y := 0; y <= max; y++ {
+			p := poissonProb(homeMean, x) * poissonProb(awayMean, y)
+			switch {
+			case x > y:
+				homeWin += p
+			case x == y:
+				draw += p
+			default:
+				awayWin += p
+			}
+			if x-y >= 1 && x-y <= 2 {
+				homeBy1to2 += p
+			}
+			if x+y > 4 {
+				over4 += p
+			}
+		}
+	}
+
+	near := func(name string, got, want float64) {
+		t.Helper()
+		if math.Abs(got-want) > 1e-6 {
+			t.Errorf("%s = %v, want %v", name, got, want)
+		}
+	}
+
+	h, dr, a := d.Outcome()
+	near("home win", h, homeWin)
+	near("draw", dr, draw)
+	near("away win", a, awayWin)
+	near("outcomes", h+dr+a, 1)
+
+	near("margin 1 to 2", d.Margin(1, 2), homeBy1to2)
+	near("margin 0", d.Margin(0, 0), draw)
+	near("over 4.5", d.Over(4.5), over4)
+	near("over 4", d.Over(4), over4)
+	near("over and under a half line", d.Over(4.5)+d.Under(4.5), 1)
+	// the total of two independent Poissons is Poisson
+	near("under a whole line", d.Under(4), 1-over4-poissonProb(homeMean+awayMean, 4))
+
+	home, away := d.BandProbabilities([]Band{{From: 1, To: 2}, {From: 3, To: 0}})
+	near("home 1-2", home[0], homeBy1to2)
+	near("home bands", home[0]+home[1], homeWin)
+	near("away bands", away[0]+away[1], awayWin)
+
+	if p := d.Prob(-1, 0) + d.Prob(max+1, 0); p != 0 {
+		t.Errorf("probability of an impossible score = %v, want 0", p)
+	}
+}
+
+func TestNewDistributionCovariance(t *testing.T) {
+	independent := NewDistribution(20, 20, 0, 100)
+	shared := NewDistribution(20, 20, 8, 100)
+
+	// shared points pull the scores together
+	_, drawIndependent, _ := independent.Outcome()
+	_, drawShared, _ := shared.Outcome()
+	if drawShared <= drawIndependent {
+		t.Errorf("draw with covariance %v isn't above %v without", drawShared, drawIndependent)
+	}
+
+	// a covariance past the smaller mean is capped just below it
+	capped := NewDistribution(20, 10, 50, 100)
+	if want := NewDistribution(20, 10, 9.9, 100); !reflect.DeepEqual(capped, want) {
+		t.Error("covariance above the smaller mean isn't capped at 0.99 of it")
+	}
+	for x := range capped.p {
+		for y, p := range capped.p[x] {
+			if math.IsNaN(p) || p < 0 {
+				t.Fatalf("P(%d, %d) = %v", x, y, p)
+			}
+		}
+	}
+
+	if negative, zero := NewDistribution(20, 10, -3, 100), NewDistribution(20, 10, 0, 100); !reflect.DeepEqual(negative, zero) {
+		t.Error("negative covariance isn't treated as zero")
+	}
+}
+
+func season(start time.Time) []Match {
+	day := 24 * time.Hour
+	return []Match{
+		{Kickoff: start, Home: "Storm", Away: "Titans", HomeScore: 30, AwayScore: 12},
+		{Kickoff: start.Add(7 * day), Home: "Titans", Away: "Panthers", HomeScore: 16, AwayScore: 20},
+		{Kickoff: start.Add(14 * day), Home: "Panthers", Away: "Storm", HomeScore: 18, AwayScore: 22},
+		{Kickoff: start.Add(21 * day), Home: "Storm", Away: "Panthers", HomeScore: 26, AwayScore: 14, Neutral: true},
+		{Kickoff: start.Add(28 * day), Home: "Titans", Away: "Storm", HomeScore: 10, AwayScore: 24},
+	}
+}
+
+func TestFitIgnoresLaterMatches(t *testing.T) {
+	start := time.Date(2024, time.March, 7, 9, 0, 0, 0, time.UTC)
+	matches := season(start)
+	at := start.Add(35 * 24 * time.Hour)
+
+	later := append(append([]Match{}, matches...),
+		Match{Kickoff: at, Home: "Storm", Away: "Titans", HomeScore: 0, AwayScore: 60},
+		Match{Kickoff: at.Add(time.Hour), Home: "Panthers", Away: "Titans", HomeScore: 0, AwayScore: 60},
+		// without a kickoff a match can't be aged
+		Match{Home: "Panthers", Away: "Storm", HomeScore: 0, AwayScore: 60},
+	)
+
+	want := Fit(DefaultConfig, matches, at)
+	if got := Fit(DefaultConfig, later, at); !reflect.DeepEqual(got, want) {
+		t.Errorf("fit with later matches =\n%+v\nwant\n%+v", got, want)
+	}
+
+	if empty := Fit(DefaultConfig, matches, start); len(empty.attack) != 0 {
+		t.Errorf("fit before the first match rated %d teams", len(empty.attack))
+	}
+}
+
+func TestFitTeamWithoutPoints(t *testing.T) {
+	start := time.Date(2024, time.March, 7, 9, 0, 0, 0, time.UTC)
+	matches := append(season(start),
+		Match{Kickoff: start.Add(time.Hour), Home: "Dolphins", Away: "Storm", HomeScore: 0, AwayScore: 40},
+		Match{Kickoff: start.Add(8 * 24 * time.Hour), Home: "Panthers", Away: "Dolphins", HomeScore: 34, AwayScore: 0},
+	)
+	model := Fit(DefaultConfig, matches, start.Add(60*24*time.Hour))
+
+	if a := model.attack["Dolphins"]; math.IsNaN(a) || a <= 0 {
+		t.Errorf("attack of a team that never scored = %v, want a small positive strength", a)
+	}
+	for team := range model.attack {
+		for _, s := range []float64{model.attack[team], model.defence[team]} {
+			if math.IsNaN(s) || math.IsInf(s, 0) {
+				t.Errorf("%s has strength %v", team, s)
+			}
+		}
+	}
+
+	home, away := model.Expected("Dolphins", "Storm", false)
+	if math.IsNaN(home) || math.IsNaN(away) || home <= 0 || away <= 0 {
+		t.Errorf("expected scores = %v, %v", home, away)
+	}
+	if math.IsNaN(model.Predict("Dolphins", "Storm", false).Over(40.5)) {
+		t.Error("predicted total is NaN")
+	}
+}
+
+func TestFitCovariance(t *testing.T) {
+	start := time.Date(2024, time.March, 7, 9, 0, 0, 0, time.UTC)
+	// high scoring and low scoring matches, so both scores move together
+	var matches []Match
+	for i := 0; i < 20; i++ {
+		score := 8
+		if i%2 == 0 {
+			score = 36
+		}
+		home, away := "Storm", "Panthers"
+		if i%4 >= 2 {
+			home, away = away, home
+		}
+		matches = append(matches, Match{Kickoff: start.Add(time.Duration(i) * 24 * time.Hour), Home: home, Away: away, HomeScore: score + 2, AwayScore: score})
+	}
+	at := start.Add(30 * 24 * time.Hour)
+
+	estimated := Fit(DefaultConfig, matches, at)
+	if estimated.covariance <= 0 {
+		t.Errorf("estimated covariance = %v, want positive", estimated.covariance)
+	}
+
+	cfg := DefaultConfig
+	cfg.Covariance = 2
+	if fixed := Fit(cfg, matches, at); fixed.covariance != 2 {
+		t.Errorf("covariance = %v, want the configured 2", fixed.covariance)
+	}
+
+	cfg.Covariance = 0
+	if independent := Fit(cfg, matches, at); independent.covariance != 0 {
+		t.Errorf("covariance = %v, want 0", independent.covariance)
+	}
+}
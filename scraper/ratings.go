@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/0xlfl/nrl-predictor/scraper/elo"
+	"github.com/0xlfl/nrl-predictor/scraper/poisson"
+)
+
+// UpdateRatings replays every played match of the competition through the elo
+// model and stores the ratings after each round.
+func UpdateRatings(ctx context.Context, db *DB, compID int, cfg elo.Config) (*elo.Model, error) {
+	matches, roundIDs, err := db.GetRatingMatches(ctx, compID, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get results: %w", err)
+	}
+
+	model, snapshots := elo.Replay(cfg, matches)
+	if err := db.SetTeamRatings(ctx, roundIDs, snapshots); err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+// scoreMatches hands the results loaded for the ratings to the score model.
+func scoreMatches(results []elo.Match) []poisson.Match {
+	matches := make([]poisson.Match, 0, len(results))
+	for _, r := range results {
+		matches = append(matches, poisson.Match{
+			Kickoff: r.Kickoff,
+			Home: r.Home,
+			Away: r.Away,
+			HomeScore: r.HomeScore,
+			AwayScore: r.AwayScore,
+			Neutral: r.Neutral,
+		})
+	}
+
+	return matches
+}
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"errors"
+	"slices"
 	"sync"
 	"fmt"
 	"strings"
@@ -10,43 +12,59 @@ import (
 	"github.com/PuerkitoBio/goquery"
 	"github.com/chromedp/chromedp"
 	"github.com/google/uuid"
+
+	"github.com/0xlfl/nrl-predictor/scraper/ladder"
 )
 
 type Round struct {
 	id uuid.UUID
 	matches []*Match
-	startDay string
-	endDay string
+	start time.Time
+	end time.Time
 	roundName string
 	roundIndex int
+	// teams without a match this round
+	byes []string
+	// the ladder once the round was played
+	ladder []ladder.Standing
 }
 
 type RoundMatch struct {
 	homeTeam string
 	awayTeam string
+	// empty for fixtures nrl.com has no match centre for yet
 	url string
+	kickoff time.Time
+	status MatchStatus
 }
 
-func (r *Round) String() string {
-	matches := createListStr(r.matches)
-	return fmt.Sprintf(`{
-		"round": "%s",
-		"weekIndex": "%d",
-		"startDay": "%s",
-		"endDay": "%s",
-		"matches": %s
-	}`, r.roundName, r.roundIndex, r.startDay, r.endDay, matches)
-}
-
-func ExtractAllMatches(html string) ([]RoundMatch, error) {
+// ExtractAllMatches reads the fixtures of a round off the draw, played or
+// not, along with the teams that have the bye.
+func ExtractAllMatches(html string, season string) ([]RoundMatch, []string, error) {
 	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
 	if err != nil {
-		return nil, fmt.Errorf("parse error: %w", err)
+		return nil, nil, fmt.Errorf("parse error: %w", err)
 	}
 
 	var matches []RoundMatch
+	var byes []string
+	now := time.Now()
+
+	// the draw groups matches under a header per day, so remember the last
+	// one seen for matches that don't carry their own datetime
+	day := ""
+	doc.Find("p.match-header__title, .match").Each(func(i int, s *goquery.Selection) {
+		if s.Is("p.match-header__title") {
+			day = strings.TrimSpace(s.Text())
+			return
+		}
+
+		kickoff, err := parseKickoff(s, season)
+		if err != nil && day != "" {
+			clock := strings.TrimSpace(s.Find(".match-header__kick-off, .match-clock__kick-off, time").First().Text())
+			kickoff, _ = parseKickoffText(day+" "+clock, season)
+		}
 
-	doc.Find(".match").Each(func(i int, s *goquery.Selection) {
 		home := strings.TrimSpace(s.Find(".match-team__name--home").Text())
 		away := strings.TrimSpace(s.Find(".match-team__name--away").Text())
 
@@ -57,120 +75,175 @@ func ExtractAllMatches(html string) ([]RoundMatch, error) {
 			}
 		})
 
-		if home != "" && away != "" && url != "" {
+		// a bye is drawn as a card with a single team
+		if (home == "") != (away == "") && hasWord(strings.ToLower(s.Text()), "bye") {
+			byes = append(byes, home+away)
+			return
+		}
+
+		if home != "" && away != "" {
 			matches = append(matches, RoundMatch{
 				homeTeam: home,
 				awayTeam: away,
 				url: url,
+				kickoff: kickoff,
+				status: parseMatchStatus(s, kickoff, now),
 			})
 		}
 	})
 
-	return matches, nil
+	doc.Find(".bye-list__item, .match-bye").Each(func(_ int, s *goquery.Selection) {
+		name := strings.TrimSpace(s.Find(".bye-list__team-name, .match-bye__team-name, .match-team__name").First().Text())
+		if name == "" {
+			name, _ = s.Find("img[alt]").First().Attr("alt")
+		}
+		if name = strings.TrimSpace(name); name != "" && !slices.Contains(byes, name) {
+			byes = append(byes, name)
+		}
+	})
+
+	return matches, byes, nil
 }
 
-func scrapeRound(roundIndex int, season string, roundID uuid.UUID, compID int, datesSet bool, f Fetcher, wg *sync.WaitGroup, stats *StatsTracker) {
+func scrapeRound(ctx context.Context, db *DB, task Task, compID int, f Fetcher, wg *sync.WaitGroup, stats *StatsTracker, errs *ErrorCollector, ledger *Ledger) {
 	defer wg.Done()
 	stats.Start()
 	defer stats.Finish()
 
+	if ctx.Err() != nil {
+		return
+	}
+
+	season, roundIndex := task.season, task.roundIndex
+	url := fmt.Sprintf("https://www.nrl.com/draw/?competition=%d&round=%d&season=%s", compID, roundIndex, season)
 	content, err := f.Fetch(
-		fmt.Sprintf("https://www.nrl.com/draw/?competition=%d&round=%d&season=%s", compID, roundIndex, season),
+		ctx,
+		url,
 		chromedp.Tasks{},
 		true,
 	)
 
 	if err != nil {
+		errs.Add(ScrapeError{stage: StageRound, url: url, season: season, roundIndex: roundIndex, err: err})
+		ledger.finish(ctx, task, StageRound, err)
 		return
 	}
 
-	matches, err := ExtractAllMatches(content)
+	matches, byes, err := ExtractAllMatches(content, season)
 	if err != nil {
+		errs.Add(ScrapeError{stage: StageRound, url: url, season: season, roundIndex: roundIndex, err: err})
+		ledger.finish(ctx, task, StageRound, err)
 		return
 	}
 
-	if !datesSet {
-		start, end, err := parseRoundDates(content)
-		if err != nil {
+	var failed error
+	for _, v := range matches {
+		// stop handing out new matches once a shutdown has been requested,
+		// leaving the round pending in the ledger
+		if ctx.Err() != nil {
 			return
 		}
 
-		db, err := NewDB()
+		dbCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		matchID, err := db.CreateMatch(dbCtx, task.roundID, season, v.homeTeam, v.awayTeam)
+		// a team missing from team_alias is reported, but its match is still
+		// stored and scraped
+		if errors.Is(err, ErrUnknownTeam) && matchID != uuid.Nil {
+			errs.Add(ScrapeError{stage: StageMatch, url: v.url, season: season, roundIndex: roundIndex, err: err})
+			err = nil
+		}
+		if err == nil && !v.kickoff.IsZero() {
+			err = db.SetKickoff(dbCtx, matchID, v.kickoff)
+		}
+		if err == nil {
+			err = db.SetMatchStatus(dbCtx, matchID, v.status)
+		}
+		cancel()
+
 		if err != nil {
-			return
+			err = fmt.Errorf("unable to create %s v %s: %w", v.homeTeam, v.awayTeam, err)
+			errs.Add(ScrapeError{stage: StageMatch, url: v.url, season: season, roundIndex: roundIndex, err: err})
+			failed = errors.Join(failed, err)
+			continue
 		}
-		defer db.Conn.Close() 
 
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-		defer cancel()
+		// fixtures are stored now and their match centre scraped once they
+		// have been played
+		if !v.status.scraped() || v.url == "" {
+			continue
+		}
 
-		db.SetRoundDates(ctx, roundID, start, end)
-	}
+		mt := task.matchTask(matchID, v.url)
+		errs.registerMatch(matchID, v.url, season, roundIndex)
+		ledger.start(ctx, mt, taskStages(mt)...)
 
-  db, err := NewDB()
-	if err != nil {
-		return
+		wg.Add(1)
+		go scrapeMatch(ctx, db, mt, f, wg, stats, errs, ledger)
 	}
-	defer db.Conn.Close() 
 
-	for _, v := range matches {
-    ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
-		matchID, err := db.CreateMatch(ctx, roundID, v.homeTeam,v.awayTeam)
-	
-		if err == nil {
-			wg.Add(1)
-			go scrapeMatch(matchID, v.url, f, wg, stats)
+	dbCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	err = db.SetByes(dbCtx, task.roundID, season, byes)
+	cancel()
+	if err != nil {
+		errs.Add(ScrapeError{stage: StageRound, url: url, season: season, roundIndex: roundIndex, err: err})
+		// the byes that resolved are stored, like a match with an unknown team
+		if !errors.Is(err, ErrUnknownTeam) {
+			failed = errors.Join(failed, err)
 		}
 	}
+
+	ledger.finish(ctx, task, StageRound, failed)
 }
 
-func scrapeRounds(rounds []string, season string, seasonID uuid.UUID, compID int, f Fetcher, wg *sync.WaitGroup, stats *StatsTracker) {
+func scrapeRounds(ctx context.Context, db *DB, rounds []string, season string, seasonID uuid.UUID, compID int, f Fetcher, wg *sync.WaitGroup, stats *StatsTracker, errs *ErrorCollector, ledger *Ledger, cfg *ScrapeConfig) {
 	defer wg.Done()
 	stats.Start()
 	defer stats.Finish()
 
-	db, err := NewDB()
-	if err != nil {
-		return
-	}
-	defer db.Conn.Close() 
-
+	var failed error
 	for i, v := range rounds {
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
-		roundID, datesSet, err := db.CreateRound(ctx, i + 1, v, seasonID)
-
-		if err == nil && i == 0 {
-			wg.Add(1)
-			go scrapeRound(i + 1, season, roundID, compID, datesSet, f, wg, stats)
+		// the season stays pending in the ledger until all its rounds are queued
+		if ctx.Err() != nil {
+			return
 		}
-	}
-}
 
-func parseRoundDates(html string) (string, string, error) {
-	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
-	if err != nil {
-		return "", "", err
-	}
+		dbCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		roundID, err := db.CreateRound(dbCtx, i + 1, v, seasonID)
+		cancel()
 
-	var dates []string
-
-	doc.Find("p.match-header__title").Each(func(i int, s *goquery.Selection) {
-		dateStr := strings.TrimSpace(s.Text())
-		if dateStr == "" {
-			return
+		if err != nil {
+			errs.Add(ScrapeError{stage: StageRounds, season: season, roundIndex: i + 1, err: err})
+			failed = errors.Join(failed, err)
+			continue
 		}
 
-		dates = append(dates, dateStr)
-	})
+		if cfg.includesRound(i + 1) {
+			rt := roundTask(season, i + 1, roundID)
+			ledger.start(ctx, rt, taskStages(rt)...)
 
-	if len(dates) == 0 {
-		return "", "", fmt.Errorf("no dates found")
+			wg.Add(1)
+			go scrapeRound(ctx, db, rt, compID, f, wg, stats, errs, ledger)
+		}
 	}
 
-	minDate := dates[0]
-	maxDate := dates[len(dates) - 1]
+	ledger.finish(ctx, seasonTask(season), StageRounds, failed)
+}
+
+// resolveByes resolves the names of the teams with the bye. A name resolve
+// doesn't know is collected into unknown rather than stopping the rest, any
+// other failure is returned as err.
+func resolveByes(teams []string, resolve func(string) (Team, error)) (resolved []Team, unknown error, err error) {
+	for _, name := range teams {
+		team, err := resolve(name)
+		if errors.Is(err, ErrUnknownTeam) {
+			unknown = errors.Join(unknown, err)
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		resolved = append(resolved, team)
+	}
 
-	return minDate, maxDate, nil
+	return resolved, unknown, nil
 }
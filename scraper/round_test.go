@@ -0,0 +1,113 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestExtractAllMatches(t *testing.T) {
+	matches, byes, err := ExtractAllMatches(fixturePage(t, roundFixture), "2024")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []RoundMatch{
+		{
+			homeTeam: "Sea Eagles",
+			awayTeam: "Rabbitohs",
+			url: "/draw/nrl-premiership/2024/round-1/sea-eagles-v-rabbitohs/",
+			kickoff: time.Date(2024, time.March, 3, 8, 30, 0, 0, sydney),
+			status: StatusFullTime,
+		},
+		{
+			homeTeam: "Storm",
+			awayTeam: "Panthers",
+			url: "/draw/nrl-premiership/2024/round-1/storm-v-panthers/",
+			kickoff: time.Date(2024, time.March, 7, 19, 50, 0, 0, sydney),
+			status: StatusFullTime,
+		},
+		{
+			homeTeam: "Knights",
+			awayTeam: "Raiders",
+			url: "/draw/nrl-premiership/2024/round-1/knights-v-raiders/",
+			kickoff: time.Date(2024, time.March, 9, 17, 30, 0, 0, sydney),
+			status: StatusPostponed,
+		},
+		{
+			homeTeam: "Cowboys",
+			awayTeam: "Titans",
+			url: "/draw/nrl-premiership/2024/round-1/cowboys-v-titans/",
+			kickoff: time.Date(2024, time.March, 9, 19, 35, 0, 0, sydney),
+			// kicked off, but without a status or a score
+			status: StatusScheduled,
+		},
+	}
+
+	if len(matches) != len(want) {
+		t.Fatalf("got %d matches, want %d: %+v", len(matches), len(want), matches)
+	}
+	for i, w := range want {
+		got := matches[i]
+		if got.homeTeam != w.homeTeam || got.awayTeam != w.awayTeam || got.url != w.url || got.status != w.status {
+			t.Errorf("match %d = %+v, want %+v", i, got, w)
+		}
+		if !got.kickoff.Equal(w.kickoff) {
+			t.Errorf("match %d kickoff = %v, want %v", i, got.kickoff, w.kickoff)
+		}
+	}
+
+	if want := []string{"Dolphins", "Wests Tigers"}; !reflect.DeepEqual(byes, want) {
+		t.Errorf("byes = %v, want %v", byes, want)
+	}
+}
+
+func TestResolveByes(t *testing.T) {
+	errDB := errors.New("connection reset")
+	resolve := func(name string) (Team, error) {
+		switch name {
+		case "Dolphins":
+			return Team{id: "dolphins"}, nil
+		case "Wests Tigers":
+			return Team{id: "wests-tigers"}, nil
+		case "Broken":
+			return Team{}, errDB
+		}
+		return Team{}, fmt.Errorf("%w %q in season 2024, add it to team_alias", ErrUnknownTeam, name)
+	}
+
+	tests := []struct {
+		name string
+		teams []string
+		want []string
+		unknown bool
+		err error
+	}{
+		{"all known", []string{"Dolphins", "Wests Tigers"}, []string{"dolphins", "wests-tigers"}, false, nil},
+		// the known bye is still stored
+		{"unknown name", []string{"Dolphins", "Nowhere Nomads"}, []string{"dolphins"}, true, nil},
+		{"none", nil, nil, false, nil},
+		{"failed lookup", []string{"Dolphins", "Broken"}, nil, false, errDB},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolved, unknown, err := resolveByes(tt.teams, resolve)
+			if !errors.Is(err, tt.err) || (err == nil) != (tt.err == nil) {
+				t.Fatalf("err = %v, want %v", err, tt.err)
+			}
+			var got []string
+			for _, team := range resolved {
+				got = append(got, team.id)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("resolved = %v, want %v", got, tt.want)
+			}
+			if errors.Is(unknown, ErrUnknownTeam) != tt.unknown {
+				t.Errorf("unknown = %v, want unknown team %v", unknown, tt.unknown)
+			}
+		})
+	}
+}
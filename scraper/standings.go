@@ -0,0 +1,272 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/chromedp/chromedp"
+
+	"github.com/0xlfl/nrl-predictor/scraper/ladder"
+)
+
+var regularRoundPattern = regexp.MustCompile(`(?i)^round\s+\d+$`)
+
+// isRegularRound tells the rounds of the regular season, which make up the
+// ladder, from the finals.
+func isRegularRound(name string) bool {
+	return regularRoundPattern.MatchString(strings.TrimSpace(name))
+}
+
+// UpdateLadders works out the ladder after each round of every season of the
+// competition and stores it. A team with neither a match nor the bye in a
+// round is reported to errs.
+func UpdateLadders(ctx context.Context, db *DB, compID int, errs *ErrorCollector) error {
+	seasons, err := db.GetSeasonYears(ctx, compID)
+	if err != nil {
+		return fmt.Errorf("failed to get seasons: %w", err)
+	}
+
+	for _, season := range seasons {
+		_, missing, err := updateLadder(ctx, db, compID, season)
+		if err != nil {
+			return fmt.Errorf("season %s: %w", season, err)
+		}
+		for _, m := range missing {
+			errs.Add(ScrapeError{stage: StageLadder, season: season, roundIndex: m.Round, err: missingFromRound(m)})
+		}
+	}
+
+	return nil
+}
+
+func missingFromRound(m ladder.Missing) error {
+	return fmt.Errorf("%s has neither a match nor the bye in round %d", m.Team, m.Round)
+}
+
+// updateLadder stores the ladders of a season. It also returns the teams
+// missing from a round, which are left uncredited in that round's ladder.
+func updateLadder(ctx context.Context, db *DB, compID int, season string) ([]ladder.Snapshot, []ladder.Missing, error) {
+	results, roundIDs, err := db.GetLadderResults(ctx, compID, season)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get results: %w", err)
+	}
+
+	byes := map[int][]string{}
+	for round, roundID := range roundIDs {
+		teams, err := db.GetByes(ctx, roundID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get byes of round %d: %w", round, err)
+		}
+		// older scrapes didn't store the byes, so they are worked out
+		if len(teams) > 0 {
+			byes[round] = teams
+		}
+	}
+
+	snapshots, missing := ladder.Compute(nil, results, byes)
+	if err := db.SetLadderSnapshots(ctx, roundIDs, snapshots); err != nil {
+		return nil, nil, err
+	}
+
+	return snapshots, missing, nil
+}
+
+// nrlLadder is the ladder nrl.com hands its ladder widget.
+type nrlLadder struct {
+	Positions []struct {
+		TeamNickname string `json:"teamNickname"`
+		Stats struct {
+			Played int `json:"played"`
+			Wins int `json:"wins"`
+			Drawn int `json:"drawn"`
+			Lost int `json:"lost"`
+			Byes int `json:"byes"`
+			PointsFor int `json:"points for"`
+			PointsAgainst int `json:"points against"`
+			Points int `json:"points"`
+		} `json:"stats"`
+	} `json:"positions"`
+}
+
+// ExtractLadder reads the standings off an nrl.com ladder page, from the data
+// behind the ladder widget when it is there and otherwise from the rendered
+// table. Teams are named as nrl.com shows them.
+func ExtractLadder(html string) ([]ladder.Standing, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return nil, fmt.Errorf("parse error: %w", err)
+	}
+
+	var standings []ladder.Standing
+	doc.Find("[q-data]").EachWithBreak(func(_ int, s *goquery.Selection) bool {
+		data, _ := s.Attr("q-data")
+		if !strings.Contains(data, `"positions"`) {
+			return true
+		}
+
+		var l nrlLadder
+		if err := json.Unmarshal([]byte(data), &l); err != nil {
+			return true
+		}
+		for i, p := range l.Positions {
+			standings = append(standings, ladder.Standing{
+				Position: i + 1,
+				Team: p.TeamNickname,
+				Played: p.Stats.Played,
+				Won: p.Stats.Wins,
+				Drawn: p.Stats.Drawn,
+				Lost: p.Stats.Lost,
+				Byes: p.Stats.Byes,
+				Points: p.Stats.Points,
+				For: p.Stats.PointsFor,
+				Against: p.Stats.PointsAgainst,
+			})
+		}
+
+		return len(standings) == 0
+	})
+	if len(standings) > 0 {
+		return standings, nil
+	}
+
+	standings = extractLadderTable(doc)
+	if len(standings) == 0 {
+		return nil, fmt.Errorf("no ladder found on page")
+	}
+
+	return standings, nil
+}
+
+// extractLadderTable reads the rendered ladder, finding each column by its
+// heading.
+func extractLadderTable(doc *goquery.Document) []ladder.Standing {
+	columns := map[string]int{}
+	doc.Find("table thead th").Each(func(i int, s *goquery.Selection) {
+		heading := strings.ToLower(strings.TrimSpace(s.Text()))
+		if abbr, ok := s.Attr("title"); ok {
+			heading = strings.ToLower(strings.TrimSpace(abbr))
+		}
+		columns[heading] = i
+	})
+
+	column := func(names ...string) int {
+		for _, n := range names {
+			if i, ok := columns[n]; ok {
+				return i
+			}
+		}
+		return -1
+	}
+	team := column("team", "club")
+	fields := []struct {
+		index int
+		value func(*ladder.Standing) *int
+	}{
+		{column("played", "p", "pld"), func(s *ladder.Standing) *int { return &s.Played }},
+		{column("won", "wins", "w"), func(s *ladder.Standing) *int { return &s.Won }},
+		{column("drawn", "d"), func(s *ladder.Standing) *int { return &s.Drawn }},
+		{column("lost", "l"), func(s *ladder.Standing) *int { return &s.Lost }},
+		{column("byes", "bye", "b"), func(s *ladder.Standing) *int { return &s.Byes }},
+		{column("points for", "for", "pf", "f"), func(s *ladder.Standing) *int { return &s.For }},
+		{column("points against", "against", "pa", "a"), func(s *ladder.Standing) *int { return &s.Against }},
+		{column("points", "pts"), func(s *ladder.Standing) *int { return &s.Points }},
+	}
+	if team < 0 {
+		return nil
+	}
+
+	var standings []ladder.Standing
+	doc.Find("table tbody tr").Each(func(_ int, row *goquery.Selection) {
+		cells := row.Find("td, th")
+		s := ladder.Standing{
+			Position: len(standings) + 1,
+			Team: strings.Join(strings.Fields(cells.Eq(team).Text()), " "),
+		}
+		if s.Team == "" {
+			return
+		}
+		for _, f := range fields {
+			if f.index >= 0 {
+				*f.value(&s), _ = strconv.Atoi(strings.TrimSpace(cells.Eq(f.index).Text()))
+			}
+		}
+		standings = append(standings, s)
+	})
+
+	return standings
+}
+
+// ValidateLadder compares a computed ladder with the one nrl.com shows for
+// the same round and lists every difference.
+func ValidateLadder(ctx context.Context, db *DB, f Fetcher, compID int, season string, snapshot ladder.Snapshot) ([]string, error) {
+	url := fmt.Sprintf("https://www.nrl.com/ladder/?competition=%d&round=%d&season=%s", compID, snapshot.Round, season)
+	content, err := f.Fetch(ctx, url, chromedp.Tasks{}, true)
+	if err != nil {
+		return nil, err
+	}
+
+	published, err := ExtractLadder(content)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", url, err)
+	}
+
+	computed := map[string]ladder.Standing{}
+	for _, s := range snapshot.Standings {
+		computed[s.Team] = s
+	}
+
+	var diffs []string
+	for _, want := range published {
+		dbCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		team, err := db.ResolveTeam(dbCtx, want.Team, season)
+		cancel()
+		if err != nil {
+			diffs = append(diffs, err.Error())
+			continue
+		}
+
+		got, ok := computed[team.id]
+		if !ok {
+			diffs = append(diffs, fmt.Sprintf("%s is missing from the computed ladder", team.id))
+			continue
+		}
+		delete(computed, team.id)
+
+		for _, c := range []struct {
+			field string
+			want, got int
+		}{
+			{"position", want.Position, got.Position},
+			{"played", want.Played, got.Played},
+			{"won", want.Won, got.Won},
+			{"drawn", want.Drawn, got.Drawn},
+			{"lost", want.Lost, got.Lost},
+			{"byes", want.Byes, got.Byes},
+			{"points", want.Points, got.Points},
+			{"for", want.For, got.For},
+			{"against", want.Against, got.Against},
+		} {
+			if c.want != c.got {
+				diffs = append(diffs, fmt.Sprintf("%s %s: nrl.com %d, computed %d", team.id, c.field, c.want, c.got))
+			}
+		}
+	}
+
+	missing := make([]string, 0, len(computed))
+	for team := range computed {
+		missing = append(missing, team)
+	}
+	sort.Strings(missing)
+	for _, team := range missing {
+		diffs = append(diffs, fmt.Sprintf("%s is not on the nrl.com ladder", team))
+	}
+
+	return diffs, nil
+}
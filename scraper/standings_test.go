@@ -0,0 +1,53 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/0xlfl/nrl-predictor/scraper/ladder"
+)
+
+func TestExtractLadder(t *testing.T) {
+	tests := []struct {
+		name string
+		url string
+		want []ladder.Standing
+	}{
+		{
+			"widget data",
+			"https://www.nrl.com/ladder/?competition=111&round=1&season=2024",
+			[]ladder.Standing{
+				{Position: 1, Team: "Sea Eagles", Played: 1, Won: 1, Points: 2, For: 36, Against: 24},
+				{Position: 2, Team: "Storm", Played: 1, Won: 1, Points: 2, For: 8},
+				{Position: 3, Team: "Dolphins", Byes: 1, Points: 2},
+				{Position: 4, Team: "Panthers", Played: 1, Lost: 1, Against: 8},
+				{Position: 5, Team: "Rabbitohs", Played: 1, Lost: 1, For: 24, Against: 36},
+			},
+		},
+		{
+			"rendered table",
+			"https://www.nrl.com/ladder/?competition=111&round=2&season=2024",
+			[]ladder.Standing{
+				{Position: 1, Team: "Storm", Played: 2, Won: 2, Points: 4, For: 38, Against: 16},
+				{Position: 2, Team: "Sea Eagles", Played: 2, Won: 1, Drawn: 1, Points: 3, For: 58, Against: 46},
+				{Position: 3, Team: "Wests Tigers", Played: 1, Lost: 1, Byes: 1, Points: 2, For: 12, Against: 20},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExtractLadder(fixturePage(t, tt.url))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ladder =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := ExtractLadder(fixturePage(t, roundFixture)); err == nil {
+		t.Error("no error for a page without a ladder")
+	}
+}
@@ -1,10 +1,11 @@
 package main
 
 import (
+	"fmt"
+	"sort"
 	"sync"
 	"strings"
 	"strconv"
-	"fmt"
 	"context"
 	"time"
 
@@ -129,206 +130,39 @@ type NegPlays struct {
 	awayOnReport int
 }
 
-func (ms *MatchStats) String() string {
-	return fmt.Sprintf(`{
-			"posAndComp": %s,
-			"attack": %s,
-			"passing": %s,
-			"kicking": %s,
-			"defence": %s,
-			"negPlays": %s
-		}`,
-		ms.posAndComp,
-		ms.attack,
-		ms.passing,
-		ms.kicking,
-		ms.defence,
-		ms.negPlays,
-	)
-}
-
-func (pc *PosAndComp) String() string {
-	return fmt.Sprintf(`{
-			"homePosPer": %d,
-			"awayPosPer": %d,
-			"homePosTime": "%s",
-			"awayPosTime": "%s",
-			"homeSets": %d,
-			"homeSetsCompleated": %d,
-			"awaySets": %d,
-			"awaySetsCompleated": %d
-		}`,
-		pc.homePosPer,
-		pc.awayPosPer,
-		pc.homePosTime,
-		pc.awayPosTime,
-		pc.homeSets,
-		pc.homeSetsCompleated,
-		pc.awaySets,
-		pc.awaySetsCompleated,
-	)
-}
-
-func (a *Attack) String() string {
-	return fmt.Sprintf(`{
-			"homeRuns": %d,
-			"awayRuns": %d,
-			"homeRunMeters": %d,
-			"awayRunMeters": %d,
-			"homePostContactMeters": %d,
-			"awayPostContactMeters": %d,
-			"homeLineBreaks": %d,
-			"awayLineBreaks": %d,
-			"homeTackleBreaks": %d,
-			"awayTackleBreaks": %d,
-			"homeAvgSetDistance": %f,
-			"awayAvgSetDistance": %f,
-			"homeKickReturnMeters": %d,
-			"awayKickReturnMeters": %d,
-			"homeAvgPlayTheBallSpeed": %f,
-			"awayAvgPlayTheBallSpeed": %f
-		}`,
-		a.homeRuns,
-		a.awayRuns,
-		a.homeRunMeters,
-		a.awayRunMeters,
-		a.homePostContactMeters,
-		a.awayPostContactMeters,
-		a.homeLineBreaks,
-		a.awayLineBreaks,
-		a.homeTackleBreaks,
-		a.awayTackleBreaks,
-		a.homeAvgSetDistance,
-		a.awayAvgSetDistance,
-		a.homeKickReturnMeters,
-		a.awayKickReturnMeters,
-		a.homeAvgPlayTheBallSpeed,
-		a.awayAvgPlayTheBallSpeed,
-	)
-}
-
-func (p *Passing) String() string {
-	return fmt.Sprintf(`{
-			"homeOffloads": %d,
-			"awayOffloads": %d,
-			"homeReceipts": %d,
-			"awayReceipts": %d,
-			"homeTotalPasses": %d,
-			"awayTotalPasses": %d,
-			"homeDummyPasses": %d,
-			"awayDummyPasses": %d
-		}`,
-		p.homeOffloads,
-		p.awayOffloads,
-		p.homeReceipts,
-		p.awayReceipts,
-		p.homeTotalPasses,
-		p.awayTotalPasses,
-		p.homeDummyPasses,
-		p.awayDummyPasses,
-	)
-}
-
-func (k *Kicking) String() string {
-	return fmt.Sprintf(`{
-			"homeKicks": %d,
-			"awayKicks": %d,
-			"homeKickingMeters": %d,
-			"awayKickingMeters": %d,
-			"homeForcedDropOuts": %d,
-			"awayForcedDropOuts": %d,
-			"homeKickDefusal": %d,
-			"awayKickDefusal": %d,
-			"homeBombs": %d,
-			"awayBombs": %d,
-			"homeGrubbers": %d,
-			"awayGrubbers": %d
-		}`,
-		k.homeKicks,
-		k.awayKicks,
-		k.homeKickingMeters,
-		k.awayKickingMeters,
-		k.homeForcedDropOuts,
-		k.awayForcedDropOuts,
-		k.homeKickDefusal,
-		k.awayKickDefusal,
-		k.homeBombs,
-		k.awayBombs,
-		k.homeGrubbers,
-		k.awayGrubbers,
-	)
-}
-
-func (d *Defence) String() string {
-	return fmt.Sprintf(`{
-			"homeEffecTackle": %f,
-			"awayEffecTackle": %f,
-			"homeTacklesMade": %d,
-			"awayTacklesMade": %d,
-			"homeMissedTackles": %d,
-			"awayMissedTackles": %d,
-			"homeIntercepts": %d,
-			"awayIntercepts": %d,
-			"homeIneffecTackles": %d,
-			"awayIneffecTackles": %d
-		}`,
-		d.homeEffecTackle,
-		d.awayEffecTackle,
-		d.homeTacklesMade,
-		d.awayTacklesMade,
-		d.homeMissedTackles,
-		d.awayMissedTackles,
-		d.homeIntercepts,
-		d.awayIntercepts,
-		d.homeIneffecTackles,
-		d.awayIneffecTackles,
-	)
-}
-
-func (np *NegPlays) String() string {
-	return fmt.Sprintf(`{
-			"homeErrors": %d,
-			"awayErrors": %d,
-			"homePenCon": %d,
-			"awayPenCon": %d,
-			"homeRuckInf": %d,
-			"awayRuckInf": %d,
-			"homeInside10": %d,
-			"awayInside10": %d,
-			"homeOnReport": %d,
-			"awayOnReport": %d
-		}`,
-		np.homeErrors,
-		np.awayErrors,
-		np.homePenCon,
-		np.awayPenCon,
-		np.homeRuckInf,
-		np.awayRuckInf,
-		np.homeInside10,
-		np.awayInside10,
-		np.homeOnReport,
-		np.awayOnReport,
-	)
-}
-
-func parseMatchStats(matchID uuid.UUID, content string, wg *sync.WaitGroup) {
+func parseMatchStats(ctx context.Context, db *DB, matchID uuid.UUID, content string, wg *sync.WaitGroup, errs *ErrorCollector) {
 	defer wg.Done()
 
 	wg.Add(1)
-	go parsePosAndCompStats(matchID, content, wg)
+	go func() {
+		defer wg.Done()
+		if err := parsePosAndCompStats(ctx, db, matchID, content); err != nil {
+			errs.AddMatch(matchID, StagePosAndComp, err)
+		}
+	}()
 
 	ch := make(chan map[string]func(homeStr, awayStr string))
 	var wgStats sync.WaitGroup
 	var wgHandlers sync.WaitGroup
 
-	wgStats.Add(5)
-	wgHandlers.Add(5)
+	parsers := map[Stage]func() error{
+		StageAttack: func() error { return parseAttackStats(ctx, db, matchID, content, ch, &wgStats, &wgHandlers) },
+		StagePassing: func() error { return parsePassingStats(ctx, db, matchID, content, ch, &wgStats, &wgHandlers) },
+		StageKicking: func() error { return parseKickingStats(ctx, db, matchID, content, ch, &wgStats, &wgHandlers) },
+		StageDefence: func() error { return parseDefenceStats(ctx, db, matchID, content, ch, &wgStats, &wgHandlers) },
+		StageNegPlays: func() error { return parseNegPlayStats(ctx, db, matchID, content, ch, &wgStats, &wgHandlers) },
+	}
 
-	go parseAttackStats(matchID, content, ch, &wgStats, &wgHandlers)
-	go parsePassingStats(matchID, content, ch, &wgStats, &wgHandlers)
-	go parseKickingStats(matchID, content, ch, &wgStats, &wgHandlers)
-	go parseDefenceStats(matchID, content, ch, &wgStats, &wgHandlers)
-	go parseNegPlayStats(matchID, content, ch, &wgStats, &wgHandlers)
+	wgStats.Add(len(parsers))
+	wgHandlers.Add(len(parsers))
+
+	for stage, parse := range parsers {
+		go func() {
+			if err := parse(); err != nil {
+				errs.AddMatch(matchID, stage, err)
+			}
+		}()
+	}
 
 	// close the channel when all parsers finish
 	go func() {
@@ -346,14 +180,25 @@ func parseMatchStats(matchID uuid.UUID, content string, wg *sync.WaitGroup) {
 	}
 
 	parseBarChart(mergedHandlers, content)
+
+	// release the parsers waiting on charts this page doesn't have
+	if len(mergedHandlers) > 0 {
+		missing := make([]string, 0, len(mergedHandlers))
+		for title, handler := range mergedHandlers {
+			missing = append(missing, title)
+			handler("", "")
+		}
+		sort.Strings(missing)
+		errs.AddMatch(matchID, StageMatchStats, fmt.Errorf("missing stat charts: %s", strings.Join(missing, ", ")))
+	}
+
 	wgStats.Wait()
 }
 
-func parsePosAndCompStats(matchID uuid.UUID, content string, wg *sync.WaitGroup) {
-	defer wg.Done()
+func parsePosAndCompStats(ctx context.Context, db *DB, matchID uuid.UUID, content string) error {
 	doc, err := goquery.NewDocumentFromReader(strings.NewReader(content))
 	if err != nil {
-		return;
+		return err
 	}
 
 	stats := &PosAndComp{}
@@ -393,6 +238,10 @@ func parsePosAndCompStats(matchID uuid.UUID, content string, wg *sync.WaitGroup)
 
 			homeCompRate := strings.Split(homeCompRate_, "/")
 			awayCompRate := strings.Split(awayCompRate_, "/")
+			if len(homeCompRate) != 2 || len(awayCompRate) != 2 {
+				err = fmt.Errorf("unexpected completion rate %q / %q", homeCompRate_, awayCompRate_)
+				return false
+			}
 
 			stats.homeSets, _ = strconv.Atoi(homeCompRate[1])
 			stats.homeSetsCompleated, _ = strconv.Atoi(homeCompRate[0])
@@ -406,19 +255,19 @@ func parsePosAndCompStats(matchID uuid.UUID, content string, wg *sync.WaitGroup)
 		return true
 	})
 
-	db, err := NewDB()
 	if err != nil {
-		return
+		return err
 	}
-	defer db.Conn.Close() 
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	db.SetPosAndCompStats(ctx, matchID, stats)
+	return db.SetPosAndCompStats(ctx, matchID, stats)
 }
 
 func parseAttackStats(
+	ctx context.Context,
+	db *DB,
 	matchID uuid.UUID,
 	content string,
 	ch chan map[string]func(homeStr, awayStr string),
@@ -509,19 +358,15 @@ func parseAttackStats(
 
 	handlersComplete.Wait()
 
-	db, err := NewDB()
-	if err != nil {
-		return err
-	}
-	defer db.Conn.Close() 
-
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	return db.SetAttackStats(ctx, matchID, a)
 }
 
 func parsePassingStats(
+	ctx context.Context,
+	db *DB,
 	matchID uuid.UUID,
 	content string,
 	ch chan map[string]func(homeStr, awayStr string),
@@ -566,19 +411,15 @@ func parsePassingStats(
 
 	handlersComplete.Wait()
 
-	db, err := NewDB()
-	if err != nil {
-		return err
-	}
-	defer db.Conn.Close() 
-
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	return db.SetPassingStats(ctx, matchID, p)
 }
 
 func parseKickingStats(
+	ctx context.Context,
+	db *DB,
 	matchID uuid.UUID,
 	content string,
 	ch chan map[string]func(homeStr, awayStr string),
@@ -657,19 +498,15 @@ func parseKickingStats(
 
 	handlersComplete.Wait()
 
-	db, err := NewDB()
-	if err != nil {
-		return err
-	}
-	defer db.Conn.Close() 
-
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	return db.SetKickingStats(ctx, matchID, k)
 }
 
 func parseDefenceStats(
+	ctx context.Context,
+	db *DB,
 	matchID uuid.UUID,
 	content string,
 	ch chan map[string]func(homeStr, awayStr string),
@@ -742,19 +579,15 @@ func parseDefenceStats(
 
 	handlersComplete.Wait()
 
-	db, err := NewDB()
-	if err != nil {
-		return err
-	}
-	defer db.Conn.Close() 
-
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	return db.SetDefenceStats(ctx, matchID, d)
 }
 
 func parseNegPlayStats(
+	ctx context.Context,
+	db *DB,
 	matchID uuid.UUID,
 	content string,
 	ch chan map[string]func(homeStr, awayStr string),
@@ -805,13 +638,7 @@ func parseNegPlayStats(
 
 	handlersComplete.Wait()
 
-	db, err := NewDB()
-	if err != nil {
-		return err
-	}
-	defer db.Conn.Close() 
-
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	return db.SetNegPlayStats(ctx, matchID, ng)
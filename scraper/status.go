@@ -0,0 +1,86 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// MatchStatus is where a match is in its lifecycle.
+type MatchStatus string
+
+const (
+	StatusScheduled MatchStatus = "scheduled"
+	StatusLive MatchStatus = "live"
+	StatusFullTime MatchStatus = "full_time"
+	StatusPostponed MatchStatus = "postponed"
+)
+
+// statusWords are checked in order against a match card's status text and
+// classes, so "postponed" wins over the kickoff time shown next to it.
+var statusWords = []struct {
+	status MatchStatus
+	words []string
+}{
+	{StatusPostponed, []string{"postponed", "cancelled", "abandoned"}},
+	{StatusFullTime, []string{"full time", "full-time", "fulltime", "ft"}},
+	{StatusLive, []string{"live", "half time", "1st half", "2nd half", "extra time", "golden point"}},
+	{StatusScheduled, []string{"upcoming", "pre game", "pre-game", "pre-match"}},
+}
+
+// parseMatchStatus reads the status of a match card on the draw. Cards that
+// don't show one are only taken as played once they show a score and have
+// kicked off. A kickoff alone isn't enough: a card without a clock kicks off
+// at midnight, and a match in progress may not show its status.
+func parseMatchStatus(s *goquery.Selection, kickoff time.Time, now time.Time) MatchStatus {
+	if status, ok := shownStatus(s); ok {
+		return status
+	}
+
+	if !hasScore(s) || (!kickoff.IsZero() && kickoff.After(now)) {
+		return StatusScheduled
+	}
+
+	return StatusFullTime
+}
+
+// shownStatus reads the status a match card or page shows in its status text
+// or classes, if it shows one.
+func shownStatus(s *goquery.Selection) (MatchStatus, bool) {
+	class, _ := s.Attr("class")
+	text := strings.ToLower(strings.TrimSpace(s.Find(".match-state, .match-status, .match__status, .match-clock__status").Text()))
+	classes := strings.ToLower(strings.NewReplacer("--", " ", "__", " ").Replace(class))
+
+	for _, sw := range statusWords {
+		for _, w := range sw.words {
+			if hasWord(text, w) || hasWord(classes, w) {
+				return sw.status, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// hasScore reports whether a match card shows a score for both sides.
+func hasScore(s *goquery.Selection) bool {
+	for _, side := range []string{".match-team__score--home", ".match-team__score--away"} {
+		text := strings.TrimSpace(s.Find(side).First().Clone().Children().Remove().End().Text())
+		if _, err := strconv.Atoi(text); err != nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+func hasWord(text string, word string) bool {
+	return strings.Contains(" "+strings.Join(strings.Fields(text), " ")+" ", " "+word+" ")
+}
+
+// scraped reports whether the match centre has anything to scrape yet.
+func (s MatchStatus) scraped() bool {
+	return s == StatusFullTime
+}
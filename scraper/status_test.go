@@ -0,0 +1,104 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func TestParseMatchStatus(t *testing.T) {
+	doc := fixtureDoc(t, roundFixture)
+	card := func(home string) *goquery.Selection {
+		return doc.Find(".match").FilterFunction(func(_ int, s *goquery.Selection) bool {
+			return strings.TrimSpace(s.Find(".match-team__name--home").Text()) == home
+		})
+	}
+
+	kickoff := time.Date(2024, time.March, 9, 19, 35, 0, 0, sydney)
+	before, after := kickoff.Add(-time.Hour), kickoff.Add(3*time.Hour)
+
+	tests := []struct {
+		name string
+		home string
+		kickoff time.Time
+		now time.Time
+		want MatchStatus
+	}{
+		{"status text", "Sea Eagles", kickoff, before, StatusFullTime},
+		{"status class", "Storm", kickoff, before, StatusFullTime},
+		{"postponed", "Knights", kickoff, after, StatusPostponed},
+		{"not yet kicked off", "Cowboys", kickoff, before, StatusScheduled},
+		{"kicked off without a score", "Cowboys", kickoff, after, StatusScheduled},
+		{"no kickoff or score", "Cowboys", time.Time{}, after, StatusScheduled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := card(tt.home)
+			if s.Length() != 1 {
+				t.Fatalf("found %d cards for %s", s.Length(), tt.home)
+			}
+			if got := parseMatchStatus(s, tt.kickoff, tt.now); got != tt.want {
+				t.Errorf("status = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	scored, err := goquery.NewDocumentFromReader(strings.NewReader(`<div class="match">` +
+		`<p class="match-team__score match-team__score--home">18</p>` +
+		`<p class="match-team__score match-team__score--away">6</p></div>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := parseMatchStatus(scored.Find(".match"), time.Time{}, after); got != StatusFullTime {
+		t.Errorf("status of a scored card without a kickoff = %s, want %s", got, StatusFullTime)
+	}
+	if got := parseMatchStatus(scored.Find(".match"), kickoff, after); got != StatusFullTime {
+		t.Errorf("status of a scored card after kickoff = %s, want %s", got, StatusFullTime)
+	}
+	if got := parseMatchStatus(scored.Find(".match"), kickoff, before); got != StatusScheduled {
+		t.Errorf("status of a scored card before kickoff = %s, want %s", got, StatusScheduled)
+	}
+}
+
+// cards that show neither a status nor a score are never taken as played
+func TestParseMatchStatusUnscored(t *testing.T) {
+	matchDay := time.Date(2024, time.March, 9, 0, 0, 0, 0, sydney)
+
+	tests := []struct {
+		name string
+		html string
+		kickoff time.Time
+		now time.Time
+	}{
+		{
+			// a fixture listed by date only kicks off at midnight
+			name: "date only",
+			html: `<div class="match"><p class="match-team__name match-team__name--home">Cowboys</p></div>`,
+			kickoff: matchDay,
+			now: matchDay.Add(14 * time.Hour),
+		},
+		{
+			name: "in progress without status text",
+			html: `<div class="match"><span class="match-clock__kick-off">7:35 PM</span>` +
+				`<p class="match-team__score match-team__score--home"></p>` +
+				`<p class="match-team__score match-team__score--away"></p></div>`,
+			kickoff: matchDay.Add(19*time.Hour + 35*time.Minute),
+			now: matchDay.Add(20 * time.Hour),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := goquery.NewDocumentFromReader(strings.NewReader(tt.html))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := parseMatchStatus(doc.Find(".match"), tt.kickoff, tt.now); got != StatusScheduled {
+				t.Errorf("status = %s, want %s", got, StatusScheduled)
+			}
+		})
+	}
+}
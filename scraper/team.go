@@ -0,0 +1,23 @@
+package main
+
+import (
+	"errors"
+	"strings"
+)
+
+// Team is a club in the team registry. Its id stays the same through the
+// names it has played under.
+type Team struct {
+	id string
+	name string
+	nickname string
+}
+
+var ErrUnknownTeam = errors.New("unknown team")
+
+// normaliseName puts a team or venue name in the form aliases are stored in:
+// lower case, without full stops or apostrophes and with single spaces.
+func normaliseName(name string) string {
+	name = strings.NewReplacer(".", "", "'", "", "’", "").Replace(strings.ToLower(name))
+	return strings.Join(strings.Fields(name), " ")
+}
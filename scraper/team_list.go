@@ -0,0 +1,176 @@
+package main
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// PlayerRole is the part a player has in a match's team list.
+type PlayerRole string
+
+const (
+	RoleStarter PlayerRole = "starter"
+	RoleInterchange PlayerRole = "interchange"
+	RoleReserve PlayerRole = "reserve"
+	RoleEighteenthMan PlayerRole = "18th_man"
+)
+
+// playerRole reads the role from the position nrl.com lists a player under,
+// falling back on the jersey number when the position doesn't say.
+func playerRole(position string, number int) PlayerRole {
+	p := normaliseName(position)
+	switch {
+	case strings.Contains(p, "18th"):
+		return RoleEighteenthMan
+	case strings.Contains(p, "reserve"):
+		return RoleReserve
+	// a concussion replacement is named on the bench
+	case strings.Contains(p, "interchange"), strings.Contains(p, "bench"), strings.Contains(p, "replacement"):
+		return RoleInterchange
+	case p != "" && number <= 13:
+		return RoleStarter
+	}
+
+	switch {
+	case number >= 18:
+		return RoleReserve
+	case number >= 14:
+		return RoleInterchange
+	}
+
+	return RoleStarter
+}
+
+// captaincy markers nrl.com puts after a name
+var captainMarkers = map[string]string{
+	"(c)": "captain",
+	"(cc)": "captain",
+	"(vc)": "vice",
+}
+
+// parsePlayerName reads a player's full name. nrl.com puts the surname in its
+// own element under the first name, otherwise the first word is the first
+// name and the rest, however many words, the surname.
+func parsePlayerName(s *goquery.Selection) (first string, last string, captain bool, viceCaptain bool) {
+	var firstParts, lastParts []string
+	s.Contents().Each(func(_ int, c *goquery.Selection) {
+		text := strings.TrimSpace(c.Text())
+		if text == "" {
+			return
+		}
+		if goquery.NodeName(c) == "#text" {
+			firstParts = append(firstParts, text)
+		} else {
+			lastParts = append(lastParts, text)
+		}
+	})
+
+	var words []string
+	for _, w := range strings.Fields(strings.Join(append(firstParts, lastParts...), " ")) {
+		w, marker := splitMarker(w)
+		switch marker {
+		case "captain":
+			captain = true
+		case "vice":
+			viceCaptain = true
+		}
+		if w != "" {
+			words = append(words, w)
+		}
+	}
+
+	if len(firstParts) > 0 && len(lastParts) > 0 {
+		first = strings.Join(withoutMarkers(firstParts), " ")
+		last = strings.Join(withoutMarkers(lastParts), " ")
+		if first != "" && last != "" {
+			return first, last, captain, viceCaptain
+		}
+	}
+
+	switch len(words) {
+	case 0:
+		return "", "", captain, viceCaptain
+	case 1:
+		return "", words[0], captain, viceCaptain
+	}
+
+	return words[0], strings.Join(words[1:], " "), captain, viceCaptain
+}
+
+// splitMarker takes a captaincy marker off a word, whether it stands alone
+// or is attached to the name, as in "Cleary(c)".
+func splitMarker(w string) (string, string) {
+	lower := strings.ToLower(w)
+	for marker, kind := range captainMarkers {
+		if strings.HasSuffix(lower, marker) {
+			return w[:len(w)-len(marker)], kind
+		}
+	}
+
+	return w, ""
+}
+
+func withoutMarkers(parts []string) []string {
+	var words []string
+	for _, w := range strings.Fields(strings.Join(parts, " ")) {
+		if w, _ := splitMarker(w); w != "" {
+			words = append(words, w)
+		}
+	}
+
+	return words
+}
+
+// readTeamListProfile fills a player from their profile in a team list row.
+func readTeamListProfile(p *Player, s *goquery.Selection) {
+	p.nameFirst, p.nameLast, p.captain, p.viceCaptain = parsePlayerName(s.Find("div.team-list-profile-content > div.team-list-profile__name").First())
+
+	s.Find(".team-list-profile__captain").Each(func(_ int, c *goquery.Selection) {
+		switch strings.ToLower(strings.Trim(strings.TrimSpace(c.Text()), "()")) {
+		case "c", "captain":
+			p.captain = true
+		case "vc", "vice captain", "vice-captain":
+			p.viceCaptain = true
+		}
+	})
+
+	p.lateChange = s.HasClass("team-list-profile--late-change") ||
+		s.Find(".team-list-profile__late-change").Length() > 0 ||
+		strings.Contains(strings.ToLower(s.Text()), "late change")
+
+	p.profileURL, p.nrlID = profileLink(s)
+}
+
+// teamListChanges compares the team lists of a match with the players named
+// for it before, both mapping a player id to their side. A player brought
+// into a side that was already named is a late change. A player no longer
+// named is dropped, unless their side wasn't listed this time.
+func teamListChanges(previous, current map[string]string) (map[string]bool, []string) {
+	listedBefore := map[string]bool{}
+	for _, team := range previous {
+		listedBefore[team] = true
+	}
+	listedNow := map[string]bool{}
+	for _, team := range current {
+		listedNow[team] = true
+	}
+
+	late := map[string]bool{}
+	for pid, team := range current {
+		if listedBefore[team] && previous[pid] != team {
+			late[pid] = true
+		}
+	}
+
+	var dropped []string
+	for pid, team := range previous {
+		if _, ok := current[pid]; !ok && listedNow[team] {
+			dropped = append(dropped, pid)
+		}
+	}
+	sort.Strings(dropped)
+
+	return late, dropped
+}
@@ -0,0 +1,268 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+const stormFixture = "https://www.nrl.com//draw/nrl-premiership/2024/round-1/storm-v-panthers/"
+
+func TestPlayerRole(t *testing.T) {
+	tests := []struct {
+		position string
+		number int
+		want PlayerRole
+	}{
+		{"Fullback", 1, RoleStarter},
+		{"Lock", 13, RoleStarter},
+		{"Interchange", 14, RoleInterchange},
+		{"Bench", 17, RoleInterchange},
+		{"Concussion Replacement", 18, RoleInterchange},
+		{"18th Man", 18, RoleEighteenthMan},
+		{"Reserve", 19, RoleReserve},
+		{"", 15, RoleInterchange},
+		{"", 21, RoleReserve},
+		{"", 0, RoleStarter},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.position, func(t *testing.T) {
+			if got := playerRole(tt.position, tt.number); got != tt.want {
+				t.Errorf("playerRole(%q, %d) = %s, want %s", tt.position, tt.number, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePlayerName(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		first string
+		last string
+		captain bool
+		viceCaptain bool
+	}{
+		{"surname element", `Tom <span>Trbojevic</span>`, "Tom", "Trbojevic", false, false},
+		{"multi-part surname element", `Jack <span>de Belin</span>`, "Jack", "de Belin", false, false},
+		{"multi-part surname text", `Tevita Pangai Junior`, "Tevita", "Pangai Junior", false, false},
+		{"apostrophe in the surname", `Brian <span>To'o</span>`, "Brian", "To'o", false, false},
+		{"captain element", `Daly <span>Cherry-Evans</span> <span class="team-list-profile__captain">(c)</span>`, "Daly", "Cherry-Evans", true, false},
+		{"captain attached to text", `Nathan Cleary(c)`, "Nathan", "Cleary", true, false},
+		{"captain attached to element", `Nathan <span>Cleary(C)</span>`, "Nathan", "Cleary", true, false},
+		{"co-captain", `Isaah <span>Yeo</span> (cc)`, "Isaah", "Yeo", true, false},
+		{"vice captain attached", `Jahrome Hughes(vc)`, "Jahrome", "Hughes", false, true},
+		{"surname only", `Cleary`, "", "Cleary", false, false},
+		{"empty", ``, "", "", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := goquery.NewDocumentFromReader(strings.NewReader(`<div class="name">` + tt.html + `</div>`))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			first, last, captain, viceCaptain := parsePlayerName(doc.Find("div.name"))
+			if first != tt.first || last != tt.last || captain != tt.captain || viceCaptain != tt.viceCaptain {
+				t.Errorf("parsePlayerName(%q) = %q, %q, %v, %v, want %q, %q, %v, %v", tt.html, first, last, captain, viceCaptain, tt.first, tt.last, tt.captain, tt.viceCaptain)
+			}
+		})
+	}
+}
+
+func TestExtractTeamPlayersMarkers(t *testing.T) {
+	home, away, err := ExtractTeamPlayers(fixtureDoc(t, stormFixture))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type player struct {
+		number int
+		role PlayerRole
+		first string
+		last string
+		captain bool
+		viceCaptain bool
+		lateChange bool
+	}
+	flatten := func(players []*Player) []player {
+		var got []player
+		for _, p := range players {
+			got = append(got, player{p.number, p.role, p.nameFirst, p.nameLast, p.captain, p.viceCaptain, p.lateChange})
+		}
+		return got
+	}
+
+	tests := []struct {
+		side string
+		got []*Player
+		want []player
+	}{
+		// an element whose class only mentions captain isn't a marker
+		{"home", home, []player{
+			{1, RoleStarter, "Nick", "Meaney", false, false, false},
+			{7, RoleStarter, "Jahrome", "Hughes", false, true, false},
+			{9, RoleStarter, "Harry", "Grant", false, false, true},
+			{14, RoleInterchange, "Tui", "Kamikamica", false, false, false},
+			{18, RoleInterchange, "Trent", "Loiero", false, false, false},
+			{20, RoleEighteenthMan, "Jonah", "Pezet", false, false, false},
+		}},
+		{"away", away, []player{
+			{1, RoleStarter, "Dylan", "Edwards", false, false, false},
+			{7, RoleStarter, "Nathan", "Cleary", true, false, false},
+			{9, RoleStarter, "Mitch", "Kenny", false, false, false},
+			{14, RoleInterchange, "Scott", "Sorensen", false, false, false},
+			{18, RoleInterchange, "Lindsay", "Smith", false, false, false},
+			{20, RoleEighteenthMan, "Jack", "Cole", false, false, false},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.side, func(t *testing.T) {
+			if got := flatten(tt.got); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("players =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTeamListChanges(t *testing.T) {
+	tests := []struct {
+		name string
+		previous map[string]string
+		current map[string]string
+		late map[string]bool
+		dropped []string
+	}{
+		{
+			name: "first team lists",
+			previous: map[string]string{},
+			current: map[string]string{"tom": "home", "latrell": "away"},
+			late: map[string]bool{},
+		},
+		{
+			name: "unchanged",
+			previous: map[string]string{"tom": "home", "latrell": "away"},
+			current: map[string]string{"tom": "home", "latrell": "away"},
+			late: map[string]bool{},
+		},
+		{
+			name: "player swapped in",
+			previous: map[string]string{"tom": "home", "jake": "home", "latrell": "away"},
+			current: map[string]string{"tom": "home", "ben": "home", "latrell": "away"},
+			late: map[string]bool{"ben": true},
+			dropped: []string{"jake"},
+		},
+		{
+			name: "side named for the first time",
+			previous: map[string]string{"tom": "home"},
+			current: map[string]string{"tom": "home", "latrell": "away"},
+			late: map[string]bool{},
+		},
+		{
+			name: "side not listed this time",
+			previous: map[string]string{"tom": "home", "latrell": "away"},
+			current: map[string]string{"ben": "home"},
+			late: map[string]bool{"ben": true},
+			dropped: []string{"tom"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			late, dropped := teamListChanges(tt.previous, tt.current)
+			if !reflect.DeepEqual(late, tt.late) {
+				t.Errorf("late = %v, want %v", late, tt.late)
+			}
+			if !reflect.DeepEqual(dropped, tt.dropped) {
+				t.Errorf("dropped = %v, want %v", dropped, tt.dropped)
+			}
+		})
+	}
+}
+
+func TestExtractTeamPlayers(t *testing.T) {
+	home, away, err := ExtractTeamPlayers(fixtureDoc(t, matchFixture))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type player struct {
+		number int
+		position string
+		role PlayerRole
+		first string
+		last string
+		captain bool
+		nrlID string
+	}
+	flatten := func(players []*Player) []player {
+		var got []player
+		for _, p := range players {
+			got = append(got, player{p.number, p.position, p.role, p.nameFirst, p.nameLast, p.captain, p.nrlID})
+		}
+		return got
+	}
+
+	tests := []struct {
+		side string
+		got []*Player
+		want []player
+	}{
+		{"home", home, []player{
+			{1, "Fullback", RoleStarter, "Tom", "Trbojevic", false, "tom-trbojevic"},
+			{7, "Halfback", RoleStarter, "Daly", "Cherry-Evans", true, "daly-cherry-evans"},
+			{13, "Lock", RoleStarter, "Jake", "Trbojevic", false, "jake-trbojevic"},
+			{14, "Interchange", RoleInterchange, "Josh", "Aloiai", false, "josh-aloiai"},
+			{18, "Reserve", RoleReserve, "Ben", "Trbojevic", false, "ben-trbojevic"},
+		}},
+		{"away", away, []player{
+			{1, "Fullback", RoleStarter, "Latrell", "Mitchell", false, "latrell-mitchell"},
+			{7, "Halfback", RoleStarter, "Lachlan", "Ilias", false, "lachlan-ilias"},
+			{13, "Lock", RoleStarter, "Cameron", "Murray", true, "cameron-murray"},
+			{15, "Interchange", RoleInterchange, "Davvy", "Moale", false, "davvy-moale"},
+			{19, "Reserve", RoleReserve, "Jacob", "Host", false, "jacob-host"},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.side, func(t *testing.T) {
+			if got := flatten(tt.got); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("players =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractTeamPlayersEmptySlot(t *testing.T) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(`
+		<div class="team-list__container">
+			<div class="team-list">
+				<div class="team-list-position">
+					<p><span class="team-list-position__number">18</span><span class="team-list-position__number u-text-align-left">18</span></p>
+					<span class="team-list-position__text">Reserve</span>
+				</div>
+				<div class="team-list-profile team-list-profile--home">
+					<div class="team-list-profile-content"><div class="team-list-profile__name">Ben <span>Trbojevic</span></div></div>
+				</div>
+			</div>
+		</div>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	home, away, err := ExtractTeamPlayers(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(home) != 1 || home[0].nameLast != "Trbojevic" {
+		t.Errorf("home = %+v, want Trbojevic alone", home)
+	}
+	if len(away) != 0 {
+		t.Errorf("away = %+v, want nobody for an empty slot", away)
+	}
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"os"
+	"regexp"
+	"strconv"
+	"testing"
+)
+
+func TestNormaliseName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Sea Eagles", "sea eagles"},
+		{"St. George Illawarra Dragons", "st george illawarra dragons"},
+		{"St George Illawarra", "st george illawarra"},
+		{"Canterbury-Bankstown Bulldogs", "canterbury-bankstown bulldogs"},
+		{"  Wests   Tigers ", "wests tigers"},
+		{"Brian To'o", "brian too"},
+		{"Brian To’o", "brian too"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := normaliseName(tt.name); got != tt.want {
+				t.Errorf("normaliseName(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+type teamAlias struct {
+	alias string
+	teamID string
+	firstSeason int
+	lastSeason int
+}
+
+// seededTeamAliases reads the team_alias rows the team migration seeds.
+func seededTeamAliases(t *testing.T) []teamAlias {
+	t.Helper()
+
+	sql, err := os.ReadFile("../migrations/007_team.up.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body := regexp.MustCompile(`(?s)INSERT INTO team_alias[^;]*;`).Find(sql)
+	row := regexp.MustCompile(`\('([^']*)', '([^']*)', (NULL|\d+), (NULL|\d+)\)`)
+
+	var aliases []teamAlias
+	for _, m := range row.FindAllSubmatch(body, -1) {
+		a := teamAlias{alias: string(m[1]), teamID: string(m[2])}
+		a.firstSeason, _ = strconv.Atoi(string(m[3]))
+		a.lastSeason, _ = strconv.Atoi(string(m[4]))
+		aliases = append(aliases, a)
+	}
+	if len(aliases) == 0 {
+		t.Fatal("no team aliases seeded")
+	}
+
+	return aliases
+}
+
+// resolveAlias follows ResolveTeam: the alias must cover the season, and the
+// one starting latest wins.
+func resolveAlias(aliases []teamAlias, name string, season int) string {
+	var best *teamAlias
+	for i, a := range aliases {
+		if a.alias != normaliseName(name) {
+			continue
+		}
+		if (a.firstSeason != 0 && a.firstSeason > season) || (a.lastSeason != 0 && a.lastSeason < season) {
+			continue
+		}
+		if best == nil || (best.firstSeason != 0 && a.firstSeason > best.firstSeason) || (best.firstSeason == 0 && a.firstSeason != 0) {
+			best = &aliases[i]
+		}
+	}
+	if best == nil {
+		return ""
+	}
+
+	return best.teamID
+}
+
+func TestTeamAliases(t *testing.T) {
+	aliases := seededTeamAliases(t)
+
+	for _, a := range aliases {
+		if normaliseName(a.alias) != a.alias {
+			t.Errorf("alias %q is stored as %q by the scraper", a.alias, normaliseName(a.alias))
+		}
+	}
+
+	tests := []struct {
+		name string
+		season int
+		want string
+	}{
+		{"Sea Eagles", 2024, "sea-eagles"},
+		{"Manly-Warringah Sea Eagles", 1995, "sea-eagles"},
+		{"St. George Illawarra Dragons", 2024, "dragons"},
+		{"Dragons", 2024, "dragons"},
+		{"Dragons", 1990, "st-george"},
+		{"Tigers", 1995, "balmain"},
+		{"Tigers", 2024, "wests-tigers"},
+		{"Gold Coast", 1992, "gold-coast"},
+		{"Gold Coast", 2024, "titans"},
+		{"Gold Coast", 2003, ""},
+		{"One New Zealand Warriors", 2024, "warriors"},
+		{"Redcliffe Dolphins", 2024, "dolphins"},
+		{"Nowhere Nomads", 2024, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+" "+strconv.Itoa(tt.season), func(t *testing.T) {
+			if got := resolveAlias(aliases, tt.name, tt.season); got != tt.want {
+				t.Errorf("%q in %d resolves to %q, want %q", tt.name, tt.season, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ScoreEvent is a scoring play with the score once it was added.
+type ScoreEvent struct {
+	playIndex int
+	minute int
+	second int
+	timed bool
+	teamID string
+	eventType EventType
+	playerID uuid.UUID
+	points int
+	homeScore int
+	awayScore int
+}
+
+// ScoreTimeline is how the score of a match moved, derived from its typed
+// play-by-play.
+type ScoreTimeline struct {
+	events []*ScoreEvent
+
+	halfTimeKnown bool
+	halfTimeHome int
+	halfTimeAway int
+
+	homeScore int
+	awayScore int
+
+	biggestHomeLead int
+	biggestAwayLead int
+	leadChanges int
+	// the winner trailed at some point of the match
+	comeback bool
+
+	firstTryTeamID string
+	firstTryPlayerID uuid.UUID
+
+	// the plays add up to the final score of the match, so the timeline can
+	// be trusted
+	reliable bool
+}
+
+// chronological puts plays in the order they happened. nrl.com can list the
+// latest play first, which shows as a clock that runs backwards.
+func chronological(plays []*Play) []*Play {
+	var first, last *Play
+	for _, p := range plays {
+		if !p.timed {
+			continue
+		}
+		if first == nil {
+			first = p
+		}
+		last = p
+	}
+
+	ordered := append([]*Play{}, plays...)
+	if first != nil && first.minute*60+first.second > last.minute*60+last.second {
+		for i, j := 0, len(ordered)-1; i < j; i, j = i+1, j-1 {
+			ordered[i], ordered[j] = ordered[j], ordered[i]
+		}
+	}
+
+	return ordered
+}
+
+// NewScoreTimeline adds up the scoring plays of a match. Half time is taken
+// from the half time event, or failing that the clock when it can't be
+// mistaken, and is otherwise left unknown. Scoring plays that can't be put to
+// either side are left out. It returns nil for a match without plays.
+func NewScoreTimeline(plays []*Play, homeTeamID, awayTeamID string) *ScoreTimeline {
+	if len(plays) == 0 {
+		return nil
+	}
+
+	tl := &ScoreTimeline{}
+	leader := 0
+	halfTimeEvent := false
+
+	for _, p := range chronological(plays) {
+		if p.eventType == EventHalfTime && !halfTimeEvent {
+			tl.halfTimeKnown, tl.halfTimeHome, tl.halfTimeAway = true, tl.homeScore, tl.awayScore
+			halfTimeEvent = true
+			continue
+		}
+
+		if p.points == 0 || p.teamID == "" {
+			continue
+		}
+
+		switch p.teamID {
+		case homeTeamID:
+			tl.homeScore += p.points
+		case awayTeamID:
+			tl.awayScore += p.points
+		default:
+			continue
+		}
+
+		if p.eventType == EventTry && tl.firstTryTeamID == "" {
+			tl.firstTryTeamID, tl.firstTryPlayerID = p.teamID, p.playerID
+		}
+
+		tl.events = append(tl.events, &ScoreEvent{
+			playIndex: p.index,
+			minute: p.minute,
+			second: p.second,
+			timed: p.timed,
+			teamID: p.teamID,
+			eventType: p.eventType,
+			playerID: p.playerID,
+			points: p.points,
+			homeScore: tl.homeScore,
+			awayScore: tl.awayScore,
+		})
+
+		margin := tl.homeScore - tl.awayScore
+		tl.biggestHomeLead = max(tl.biggestHomeLead, margin)
+		tl.biggestAwayLead = max(tl.biggestAwayLead, -margin)
+
+		lead := 0
+		switch {
+		case margin > 0:
+			lead = 1
+		case margin < 0:
+			lead = -1
+		}
+		if lead != 0 {
+			if leader != 0 && lead != leader {
+				tl.leadChanges++
+			}
+			leader = lead
+		}
+	}
+
+	// without the half time event the clock only settles it when every point
+	// came before 40:00, as the first half runs on past the siren and the
+	// second starts again from 40:00
+	if !halfTimeEvent {
+		timed := false
+		for _, p := range plays {
+			timed = timed || p.timed
+		}
+		tl.halfTimeKnown = timed
+		for _, e := range tl.events {
+			if !e.timed || e.minute >= 40 {
+				tl.halfTimeKnown = false
+				break
+			}
+		}
+		if tl.halfTimeKnown {
+			tl.halfTimeHome, tl.halfTimeAway = tl.homeScore, tl.awayScore
+		}
+	}
+
+	switch {
+	case tl.homeScore > tl.awayScore:
+		tl.comeback = tl.biggestAwayLead > 0
+	case tl.awayScore > tl.homeScore:
+		tl.comeback = tl.biggestHomeLead > 0
+	}
+
+	return tl
+}
+
+// checkFinalScore compares the score the plays add up to with the final
+// score of the match. Plays nrl.com left out, or couldn't be put to a side,
+// show up as a difference and mark the timeline unreliable.
+func (tl *ScoreTimeline) checkFinalScore(homeScore, awayScore int) {
+	tl.reliable = tl.homeScore == homeScore && tl.awayScore == awayScore
+}
+
+// parseScoreTimeline derives the score timeline of a match from the plays
+// parsePlaybyPlay stored.
+func parseScoreTimeline(ctx context.Context, db *DB, matchID uuid.UUID, wg *sync.WaitGroup, errs *ErrorCollector) {
+	defer wg.Done()
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	homeTeamID, awayTeamID, err := db.GetMatchTeams(ctx, matchID)
+	if err != nil {
+		errs.AddMatch(matchID, StageScoreTimeline, fmt.Errorf("failed to get match teams: %w", err))
+		return
+	}
+
+	homeScore, awayScore, err := db.GetMatchScore(ctx, matchID)
+	if err != nil {
+		errs.AddMatch(matchID, StageScoreTimeline, fmt.Errorf("failed to get match score: %w", err))
+		return
+	}
+
+	plays, err := db.GetPlayByPlay(ctx, matchID)
+	if err != nil {
+		errs.AddMatch(matchID, StageScoreTimeline, fmt.Errorf("failed to get plays: %w", err))
+		return
+	}
+
+	tl := NewScoreTimeline(plays, homeTeamID, awayTeamID)
+	if tl == nil {
+		return
+	}
+
+	tl.checkFinalScore(homeScore, awayScore)
+	if !tl.reliable && homeScore >= 0 && awayScore >= 0 {
+		errs.AddMatch(matchID, StageScoreTimeline, fmt.Errorf("plays add up to %d-%d, the final score is %d-%d", tl.homeScore, tl.awayScore, homeScore, awayScore))
+	}
+
+	if err := db.SetScoreTimeline(ctx, matchID, tl); err != nil {
+		errs.AddMatch(matchID, StageScoreTimeline, err)
+	}
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"testing"
+)
+
+const (
+	timelineHome = "sea-eagles"
+	timelineAway = "rabbitohs"
+)
+
+func timedPlay(index, minute int, eventType EventType, teamID string, points int) *Play {
+	return &Play{index: index, minute: minute, timed: true, eventType: eventType, teamID: teamID, points: points}
+}
+
+func TestNewScoreTimeline(t *testing.T) {
+	if tl := NewScoreTimeline(nil, timelineHome, timelineAway); tl != nil {
+		t.Errorf("NewScoreTimeline(nil) = %+v, want nil", tl)
+	}
+
+	// home lead, away lead, home come back to win
+	plays := []*Play{
+		timedPlay(0, 0, EventKickOff, "", 0),
+		timedPlay(1, 5, EventTry, timelineHome, 4),
+		timedPlay(2, 6, EventConversion, timelineHome, 2),
+		timedPlay(3, 20, EventTry, timelineAway, 4),
+		timedPlay(4, 21, EventConversion, timelineAway, 2),
+		timedPlay(5, 30, EventPenaltyGoal, timelineAway, 2),
+		timedPlay(6, 40, EventHalfTime, "", 0),
+		timedPlay(7, 55, EventTry, timelineHome, 4),
+		timedPlay(8, 70, EventTry, timelineAway, 4),
+		timedPlay(9, 78, EventTry, timelineHome, 4),
+		timedPlay(10, 79, EventFieldGoal, "other-club", 1),
+		timedPlay(11, 80, EventFullTime, "", 0),
+	}
+
+	check := func(t *testing.T, tl *ScoreTimeline) {
+		t.Helper()
+		if tl.homeScore != 14 || tl.awayScore != 12 {
+			t.Errorf("score = %d-%d, want 14-12", tl.homeScore, tl.awayScore)
+		}
+		if !tl.halfTimeKnown || tl.halfTimeHome != 6 || tl.halfTimeAway != 8 {
+			t.Errorf("half time = %v %d-%d, want 6-8", tl.halfTimeKnown, tl.halfTimeHome, tl.halfTimeAway)
+		}
+		if tl.biggestHomeLead != 6 || tl.biggestAwayLead != 2 {
+			t.Errorf("biggest leads = %d, %d, want 6, 2", tl.biggestHomeLead, tl.biggestAwayLead)
+		}
+		// home, away, home, away, home
+		if tl.leadChanges != 4 {
+			t.Errorf("leadChanges = %d, want 4", tl.leadChanges)
+		}
+		if !tl.comeback {
+			t.Error("comeback = false, want true")
+		}
+		if tl.firstTryTeamID != timelineHome {
+			t.Errorf("firstTryTeamID = %q, want %q", tl.firstTryTeamID, timelineHome)
+		}
+		if len(tl.events) != 8 {
+			t.Fatalf("len(events) = %d, want 8", len(tl.events))
+		}
+		if e := tl.events[0]; e.playIndex != 1 || e.homeScore != 4 || e.awayScore != 0 {
+			t.Errorf("events[0] = %+v, want the first try at 4-0", e)
+		}
+	}
+
+	t.Run("chronological", func(t *testing.T) {
+		check(t, NewScoreTimeline(plays, timelineHome, timelineAway))
+	})
+
+	t.Run("latest first", func(t *testing.T) {
+		reversed := make([]*Play, len(plays))
+		for i, p := range plays {
+			reversed[len(plays)-1-i] = p
+		}
+		check(t, NewScoreTimeline(reversed, timelineHome, timelineAway))
+	})
+}
+
+func TestNewScoreTimelineHalfTimeClock(t *testing.T) {
+	tests := []struct {
+		name string
+		plays []*Play
+		known bool
+		home int
+		away int
+	}{
+		{
+			name: "all scoring before the siren",
+			plays: []*Play{
+				timedPlay(0, 12, EventTry, timelineHome, 4),
+				timedPlay(1, 39, EventPenaltyGoal, timelineAway, 2),
+			},
+			known: true,
+			home: 4,
+			away: 2,
+		},
+		{
+			name: "scoreless",
+			plays: []*Play{
+				timedPlay(0, 0, EventKickOff, "", 0),
+				timedPlay(1, 80, EventFullTime, "", 0),
+			},
+			known: true,
+		},
+		{
+			// a try after the siren and one from the second half kick off
+			// both read 40:xx
+			name: "scoring at 40:00",
+			plays: []*Play{
+				timedPlay(0, 12, EventTry, timelineHome, 4),
+				timedPlay(1, 40, EventTry, timelineAway, 4),
+			},
+		},
+		{
+			name: "scoring either side of the break",
+			plays: []*Play{
+				timedPlay(0, 12, EventTry, timelineHome, 4),
+				timedPlay(1, 39, EventPenaltyGoal, timelineAway, 2),
+				timedPlay(2, 52, EventTry, timelineAway, 4),
+			},
+		},
+		{
+			name: "untimed scoring",
+			plays: []*Play{
+				timedPlay(0, 12, EventTry, timelineHome, 4),
+				{index: 1, eventType: EventTry, teamID: timelineAway, points: 4},
+			},
+		},
+		{
+			name: "no clock",
+			plays: []*Play{{index: 0, eventType: EventKickOff}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tl := NewScoreTimeline(tt.plays, timelineHome, timelineAway)
+			if tl.halfTimeKnown != tt.known || tl.halfTimeHome != tt.home || tl.halfTimeAway != tt.away {
+				t.Errorf("half time = %v %d-%d, want %v %d-%d", tl.halfTimeKnown, tl.halfTimeHome, tl.halfTimeAway, tt.known, tt.home, tt.away)
+			}
+		})
+	}
+}
+
+func TestNewScoreTimelineComeback(t *testing.T) {
+	tests := []struct {
+		name string
+		plays []*Play
+		want bool
+	}{
+		{
+			name: "winner led throughout",
+			plays: []*Play{
+				timedPlay(0, 10, EventTry, timelineAway, 4),
+				timedPlay(1, 50, EventTry, timelineHome, 4),
+				timedPlay(2, 60, EventTry, timelineAway, 4),
+			},
+			want: false,
+		},
+		{
+			name: "away came from behind",
+			plays: []*Play{
+				timedPlay(0, 10, EventTry, timelineHome, 4),
+				timedPlay(1, 50, EventTry, timelineAway, 4),
+				timedPlay(2, 60, EventTry, timelineAway, 4),
+			},
+			want: true,
+		},
+		{
+			name: "draw",
+			plays: []*Play{
+				timedPlay(0, 10, EventTry, timelineHome, 4),
+				timedPlay(1, 50, EventTry, timelineAway, 4),
+			},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tl := NewScoreTimeline(tt.plays, timelineHome, timelineAway); tl.comeback != tt.want {
+				t.Errorf("comeback = %v, want %v", tl.comeback, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckFinalScore(t *testing.T) {
+	plays := []*Play{
+		timedPlay(0, 10, EventTry, timelineHome, 4),
+		timedPlay(1, 11, EventConversion, timelineHome, 2),
+		timedPlay(2, 50, EventPenaltyGoal, timelineAway, 2),
+	}
+
+	tests := []struct {
+		name string
+		home int
+		away int
+		want bool
+	}{
+		{"adds up", 6, 2, true},
+		{"missing a play", 6, 4, false},
+		{"no final score", -1, -1, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tl := NewScoreTimeline(plays, timelineHome, timelineAway)
+			tl.checkFinalScore(tt.home, tt.away)
+			if tl.reliable != tt.want {
+				t.Errorf("reliable = %v, want %v", tl.reliable, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/0xlfl/nrl-predictor/scraper/elo"
+	"github.com/0xlfl/nrl-predictor/scraper/poisson"
+	"github.com/0xlfl/nrl-predictor/scraper/tryscorer"
+)
+
+// tryPosition is where a player is expected to play. The bench and the
+// reserves are told apart by role, as their listed position varies.
+func tryPosition(position string, number int, role PlayerRole) tryscorer.Position {
+	switch role {
+	case RoleInterchange:
+		return tryscorer.Interchange
+	case RoleReserve, RoleEighteenthMan:
+		return tryscorer.Reserve
+	}
+
+	return tryscorer.PositionOf(position, number)
+}
+
+type tryScorerCard struct {
+	fixture elo.Match
+	players map[string]*Player
+	home []tryscorer.Price
+	away []tryscorer.Price
+}
+
+// priceTryScorers prices the players named for a match. Both the score model
+// and the try scorer model are fitted at kickoff, so only what was known
+// before the match goes into its prices.
+func priceTryScorers(ctx context.Context, db *DB, matchID uuid.UUID, results []elo.Match, apps []tryscorer.Appearance) (tryScorerCard, error) {
+	fixture, err := db.GetFixture(ctx, matchID)
+	if err != nil {
+		return tryScorerCard{}, fmt.Errorf("failed to get match %s: %w", matchID, err)
+	}
+
+	homeList, awayList, err := db.GetTeamLists(ctx, matchID)
+	if err != nil {
+		return tryScorerCard{}, fmt.Errorf("failed to get team lists of %s: %w", matchID, err)
+	}
+	if len(homeList) == 0 || len(awayList) == 0 {
+		return tryScorerCard{}, fmt.Errorf("match %s has no team lists", matchID)
+	}
+
+	at := fixture.Kickoff
+	if at.IsZero() {
+		at = time.Now()
+	}
+	homePoints, awayPoints := poisson.Fit(poisson.DefaultConfig, scoreMatches(results), at).Expected(fixture.Home, fixture.Away, fixture.Neutral)
+	model := tryscorer.Fit(tryscorer.DefaultConfig, apps, at)
+
+	card := tryScorerCard{fixture: fixture, players: map[string]*Player{}}
+	selections := func(list []*Player) []tryscorer.Selection {
+		s := make([]tryscorer.Selection, 0, len(list))
+		for _, p := range list {
+			card.players[p.id.String()] = p
+			s = append(s, tryscorer.Selection{Player: p.id.String(), Position: tryPosition(p.position, p.number, p.role)})
+		}
+		return s
+	}
+
+	card.home, card.away = model.Price(
+		tryscorer.Side{Team: fixture.Home, Points: homePoints, Players: selections(homeList)},
+		tryscorer.Side{Team: fixture.Away, Points: awayPoints, Players: selections(awayList)},
+	)
+
+	return card, nil
+}
@@ -0,0 +1,324 @@
+// Package tryscorer prices the players named for a match to score a try.
+package tryscorer
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+type Position string
+
+const (
+	Fullback Position = "fullback"
+	Winger Position = "winger"
+	Centre Position = "centre"
+	FiveEighth Position = "five_eighth"
+	Halfback Position = "halfback"
+	Prop Position = "prop"
+	Hooker Position = "hooker"
+	SecondRow Position = "second_row"
+	Lock Position = "lock"
+	Interchange Position = "interchange"
+	// named but not expected to take the field
+	Reserve Position = "reserve"
+)
+
+// numbers are the positions of the starting jerseys, used when a team list
+// doesn't name the position
+var numbers = map[int]Position{
+	1: Fullback, 2: Winger, 3: Centre, 4: Centre, 5: Winger, 6: FiveEighth, 7: Halfback,
+	8: Prop, 9: Hooker, 10: Prop, 11: SecondRow, 12: SecondRow, 13: Lock,
+}
+
+// PositionOf reads the position listed on a team list, falling back on the
+// jersey number.
+func PositionOf(position string, number int) Position {
+	p := strings.ToLower(strings.Join(strings.Fields(position), ""))
+	switch {
+	case strings.Contains(p, "fullback"):
+		return Fullback
+	case strings.Contains(p, "wing"):
+		return Winger
+	case strings.Contains(p, "centre"), strings.Contains(p, "center"):
+		return Centre
+	case strings.Contains(p, "five"), strings.Contains(p, "stand"):
+		return FiveEighth
+	case strings.Contains(p, "half"):
+		return Halfback
+	case strings.Contains(p, "prop"):
+		return Prop
+	case strings.Contains(p, "hooker"):
+		return Hooker
+	case strings.Contains(p, "row"):
+		return SecondRow
+	case strings.Contains(p, "lock"):
+		return Lock
+	case strings.Contains(p, "interchange"), strings.Contains(p, "bench"):
+		return Interchange
+	}
+
+	if pos, ok := numbers[number]; ok {
+		return pos
+	}
+	if number >= 14 && number <= 17 {
+		return Interchange
+	}
+
+	return Reserve
+}
+
+// Edge reports whether a position defends and attacks out wide, where an
+// opponent's edge defence decides the tries.
+func (p Position) Edge() bool {
+	return p == Fullback || p == Winger || p == Centre || p == SecondRow
+}
+
+type Config struct {
+	// minutes of the position's try rate a player's own rate is blended with
+	PriorMinutes float64
+	// games of the league average an opponent's defence is blended with
+	PriorGames float64
+	// recent appearances a player's expected minutes are averaged over
+	RecentGames int
+}
+
+var DefaultConfig = Config{
+	PriorMinutes: 800,
+	PriorGames: 10,
+	RecentGames: 5,
+}
+
+// Appearance is a player's part in a played match. Minutes is negative when
+// it wasn't recorded.
+type Appearance struct {
+	MatchID string
+	Kickoff time.Time
+	Player string
+	Team string
+	Opponent string
+	Position Position
+	Minutes float64
+	Tries int
+	// points the player's team scored
+	TeamScore int
+}
+
+type record struct {
+	tries float64
+	minutes float64
+}
+
+type Model struct {
+	cfg Config
+	triesPerPoint float64
+	position map[Position]record
+	// average minutes of an appearance at a position
+	positionMinutes map[Position]float64
+	player map[string]record
+	recentMinutes map[string][]float64
+	// tries let in per game by each opponent, split into edge and middle
+	conceded map[string][2]float64
+	games map[string]float64
+	leagueConceded [2]float64
+}
+
+func group(p Position) int {
+	if p.Edge() {
+		return 0
+	}
+
+	return 1
+}
+
+// Fit learns from the appearances in matches that kicked off before at, so
+// a model fitted at a kickoff knows nothing of the match it prices.
+func Fit(cfg Config, appearances []Appearance, at time.Time) *Model {
+	var apps []Appearance
+	for _, a := range appearances {
+		if !a.Kickoff.IsZero() && a.Kickoff.Before(at) {
+			apps = append(apps, a)
+		}
+	}
+	sort.SliceStable(apps, func(i, j int) bool { return apps[i].Kickoff.Before(apps[j].Kickoff) })
+
+	m := &Model{
+		cfg: cfg,
+		position: map[Position]record{},
+		positionMinutes: map[Position]float64{},
+		player: map[string]record{},
+		recentMinutes: map[string][]float64{},
+		conceded: map[string][2]float64{},
+		games: map[string]float64{},
+	}
+
+	positionApps := map[Position]float64{}
+	type side struct {
+		match string
+		team string
+	}
+	sides := map[side]bool{}
+	var tries, points, teamGames float64
+	for _, a := range apps {
+		tries += float64(a.Tries)
+		s := side{a.MatchID, a.Team}
+		if !sides[s] {
+			sides[s] = true
+			points += float64(a.TeamScore)
+			teamGames++
+			m.games[a.Opponent]++
+		}
+
+		c := m.conceded[a.Opponent]
+		c[group(a.Position)] += float64(a.Tries)
+		m.conceded[a.Opponent] = c
+		m.leagueConceded[group(a.Position)] += float64(a.Tries)
+
+		// tries without minutes can't give a rate
+		if a.Minutes < 0 {
+			continue
+		}
+		pos := m.position[a.Position]
+		pos.tries += float64(a.Tries)
+		pos.minutes += a.Minutes
+		m.position[a.Position] = pos
+		positionApps[a.Position]++
+
+		p := m.player[a.Player]
+		p.tries += float64(a.Tries)
+		p.minutes += a.Minutes
+		m.player[a.Player] = p
+		m.recentMinutes[a.Player] = append(m.recentMinutes[a.Player], a.Minutes)
+	}
+
+	if points > 0 {
+		m.triesPerPoint = tries / points
+	}
+	if teamGames > 0 {
+		m.leagueConceded[0] /= teamGames
+		m.leagueConceded[1] /= teamGames
+	}
+	for pos, n := range positionApps {
+		m.positionMinutes[pos] = m.position[pos].minutes / n
+	}
+
+	return m
+}
+
+// rate is a player's tries a minute, pulled towards the rate of the position
+// they are named in until they have played enough minutes of their own.
+func (m *Model) rate(player string, pos Position) float64 {
+	var prior float64
+	if r := m.position[pos]; r.minutes > 0 {
+		prior = r.tries / r.minutes
+	}
+	p := m.player[player]
+
+	return (p.tries + m.cfg.PriorMinutes*prior) / (p.minutes + m.cfg.PriorMinutes)
+}
+
+// minutes is how long a player is expected on the field, from their recent
+// appearances or failing that the position.
+func (m *Model) minutes(player string, pos Position) float64 {
+	if pos == Reserve {
+		return 0
+	}
+
+	recent := m.recentMinutes[player]
+	if len(recent) == 0 {
+		return m.positionMinutes[pos]
+	}
+	recent = recent[max(0, len(recent)-m.cfg.RecentGames):]
+	var sum float64
+	for _, v := range recent {
+		sum += v
+	}
+
+	return sum / float64(len(recent))
+}
+
+// defence is how many more tries an opponent lets in to a group of positions
+// than the league does.
+func (m *Model) defence(opponent string, pos Position) float64 {
+	g := group(pos)
+	league := m.leagueConceded[g]
+	if league <= 0 {
+		return 1
+	}
+
+	conceded := m.conceded[opponent][g] + m.cfg.PriorGames*league
+	return conceded / ((m.games[opponent] + m.cfg.PriorGames) * league)
+}
+
+// Selection is a player named in a team list.
+type Selection struct {
+	Player string
+	Position Position
+}
+
+// Side is a team going into a match, with the points it is expected to score.
+type Side struct {
+	Team string
+	Points float64
+	Players []Selection
+}
+
+type Price struct {
+	Selection
+	Minutes float64
+	// tries the player is expected to score
+	Tries float64
+	Anytime float64
+	First float64
+}
+
+// TeamTries turns expected points into expected tries.
+func (m *Model) TeamTries(points float64) float64 {
+	return points * m.triesPerPoint
+}
+
+// Price shares each team's expected tries between its players by their try
+// rate, expected minutes and the opponent's defence of their part of the
+// field. Tries are taken as Poisson, so the first try of the match goes to a
+// player in proportion to their expected tries.
+func (m *Model) Price(home, away Side) ([]Price, []Price) {
+	homePrices := m.share(home, away.Team)
+	awayPrices := m.share(away, home.Team)
+
+	var total float64
+	for _, p := range append(append([]Price{}, homePrices...), awayPrices...) {
+		total += p.Tries
+	}
+	for _, prices := range [][]Price{homePrices, awayPrices} {
+		for i := range prices {
+			prices[i].Anytime = 1 - math.Exp(-prices[i].Tries)
+			if total > 0 {
+				prices[i].First = prices[i].Tries / total * (1 - math.Exp(-total))
+			}
+		}
+	}
+
+	return homePrices, awayPrices
+}
+
+func (m *Model) share(s Side, opponent string) []Price {
+	prices := make([]Price, len(s.Players))
+	weights := make([]float64, len(s.Players))
+	var sum float64
+	for i, p := range s.Players {
+		prices[i] = Price{Selection: p, Minutes: m.minutes(p.Player, p.Position)}
+		weights[i] = m.rate(p.Player, p.Position) * prices[i].Minutes * m.defence(opponent, p.Position)
+		sum += weights[i]
+	}
+	if sum <= 0 {
+		return prices
+	}
+
+	tries := m.TeamTries(s.Points)
+	for i := range prices {
+		prices[i].Tries = tries * weights[i] / sum
+	}
+
+	return prices
+}
@@ -0,0 +1,220 @@
+package tryscorer
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+const tolerance = 1e-9
+
+func TestPositionOf(t *testing.T) {
+	tests := []struct {
+		position string
+		number int
+		want Position
+	}{
+		{"Fullback", 1, Fullback},
+		{"Winger", 2, Winger},
+		{"Centre", 3, Centre},
+		{"Center", 4, Centre},
+		{"Five-Eighth", 6, FiveEighth},
+		{"Stand Off", 6, FiveEighth},
+		{"Halfback", 7, Halfback},
+		{"Prop", 8, Prop},
+		{"Hooker", 9, Hooker},
+		{"2nd Row", 11, SecondRow},
+		{"Second Row", 12, SecondRow},
+		{"Lock", 13, Lock},
+		{"Interchange", 14, Interchange},
+		{"Bench", 17, Interchange},
+		// the listed position wins over the jersey
+		{"Hooker", 14, Hooker},
+		// and the jersey decides when the position doesn't say
+		{"", 1, Fullback},
+		{"", 5, Winger},
+		{"", 9, Hooker},
+		{"", 13, Lock},
+		{"", 15, Interchange},
+		{"Reserve", 18, Reserve},
+		{"18th Man", 18, Reserve},
+		{"", 21, Reserve},
+		{"", 0, Reserve},
+	}
+
+	for _, tt := range tests {
+		if got := PositionOf(tt.position, tt.number); got != tt.want {
+			t.Errorf("PositionOf(%q, %d) = %s, want %s", tt.position, tt.number, got, tt.want)
+		}
+	}
+}
+
+func TestRate(t *testing.T) {
+	m := &Model{
+		cfg: Config{PriorMinutes: 800},
+		// a winger scores every 160 minutes
+		position: map[Position]record{Winger: {tries: 10, minutes: 1600}},
+		player: map[string]record{
+			"regular": {tries: 20, minutes: 800},
+			"veteran": {tries: 500, minutes: 80000},
+		},
+	}
+
+	tests := []struct {
+		name string
+		player string
+		pos Position
+		want float64
+	}{
+		{"no minutes of their own", "debutant", Winger, 10.0 / 1600},
+		// as many minutes as the prior, so half way between the two rates
+		{"as many minutes as the prior", "regular", Winger, (20.0/800 + 10.0/1600) / 2},
+		{"far more minutes than the prior", "veteran", Winger, (500 + 5) / 80800.0},
+		{"position without minutes", "regular", Prop, 20.0 / 1600},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := m.rate(tt.player, tt.pos); math.Abs(got-tt.want) > tolerance {
+				t.Errorf("rate = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMinutes(t *testing.T) {
+	m := &Model{
+		cfg: Config{RecentGames: 2},
+		positionMinutes: map[Position]float64{Interchange: 30},
+		recentMinutes: map[string][]float64{"forward": {80, 20, 40}},
+	}
+
+	tests := []struct {
+		name string
+		player string
+		pos Position
+		want float64
+	}{
+		{"recent appearances", "forward", Interchange, 30},
+		{"position average", "debutant", Interchange, 30},
+		{"reserve", "forward", Reserve, 0},
+		{"position without appearances", "debutant", Lock, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := m.minutes(tt.player, tt.pos); got != tt.want {
+				t.Errorf("minutes = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDefence(t *testing.T) {
+	empty := Fit(DefaultConfig, nil, time.Now())
+	if got := empty.defence("Titans", Winger); got != 1 {
+		t.Errorf("defence without league data = %v, want 1", got)
+	}
+
+	m := &Model{
+		cfg: Config{PriorGames: 10},
+		conceded: map[string][2]float64{"Titans": {30, 5}},
+		games: map[string]float64{"Titans": 10},
+		leagueConceded: [2]float64{2, 1},
+	}
+	// 3 edge tries a game against the league's 2, pulled half way back by
+	// the prior
+	if got := m.defence("Titans", Centre); math.Abs(got-1.25) > tolerance {
+		t.Errorf("edge defence = %v, want 1.25", got)
+	}
+	if got := m.defence("Titans", Prop); math.Abs(got-0.75) > tolerance {
+		t.Errorf("middle defence = %v, want 0.75", got)
+	}
+	if got := m.defence("Dolphins", Prop); got != 1 {
+		t.Errorf("defence of a team without games = %v, want 1", got)
+	}
+}
+
+func appearances() []Appearance {
+	kickoff := time.Date(2024, time.March, 7, 9, 0, 0, 0, time.UTC)
+	var apps []Appearance
+	for i := 0; i < 4; i++ {
+		id := string(rune('a' + i))
+		at := kickoff.AddDate(0, 0, 7*i)
+		apps = append(apps,
+			Appearance{MatchID: id, Kickoff: at, Player: "munster", Team: "Storm", Opponent: "Titans", Position: FiveEighth, Minutes: 80, Tries: i % 2, TeamScore: 24},
+			Appearance{MatchID: id, Kickoff: at, Player: "coates", Team: "Storm", Opponent: "Titans", Position: Winger, Minutes: 80, Tries: 2, TeamScore: 24},
+			Appearance{MatchID: id, Kickoff: at, Player: "welch", Team: "Storm", Opponent: "Titans", Position: Prop, Minutes: 50, Tries: 0, TeamScore: 24},
+			Appearance{MatchID: id, Kickoff: at, Player: "campbell", Team: "Titans", Opponent: "Storm", Position: Winger, Minutes: 80, Tries: 1, TeamScore: 12},
+			Appearance{MatchID: id, Kickoff: at, Player: "fifita", Team: "Titans", Opponent: "Storm", Position: SecondRow, Minutes: -1, Tries: 1, TeamScore: 12},
+		)
+	}
+	return apps
+}
+
+func TestPrice(t *testing.T) {
+	m := Fit(DefaultConfig, appearances(), time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC))
+
+	home := Side{Team: "Storm", Points: 26, Players: []Selection{
+		{Player: "munster", Position: FiveEighth},
+		{Player: "coates", Position: Winger},
+		{Player: "welch", Position: Prop},
+		{Player: "grant", Position: Reserve},
+	}}
+	away := Side{Team: "Titans", Points: 14, Players: []Selection{
+		{Player: "campbell", Position: Winger},
+		{Player: "fifita", Position: SecondRow},
+		{Player: "debutant", Position: Centre},
+	}}
+
+	homePrices, awayPrices := m.Price(home, away)
+
+	var total float64
+	for _, side := range []struct {
+		side Side
+		prices []Price
+	}{{home, homePrices}, {away, awayPrices}} {
+		var tries float64
+		for _, p := range side.prices {
+			tries += p.Tries
+			if want := 1 - math.Exp(-p.Tries); math.Abs(p.Anytime-want) > tolerance {
+				t.Errorf("%s anytime = %v, want %v", p.Player, p.Anytime, want)
+			}
+		}
+		if want := m.TeamTries(side.side.Points); math.Abs(tries-want) > tolerance {
+			t.Errorf("%s players share %v tries, want the team's %v", side.side.Team, tries, want)
+		}
+		total += tries
+	}
+
+	var first float64
+	for _, p := range append(append([]Price{}, homePrices...), awayPrices...) {
+		first += p.First
+		if p.Position == Reserve && (p.Tries != 0 || p.Minutes != 0) {
+			t.Errorf("reserve priced at %v tries in %v minutes", p.Tries, p.Minutes)
+		}
+	}
+	if want := 1 - math.Exp(-total); math.Abs(first-want) > tolerance {
+		t.Errorf("first try chances sum to %v, want %v", first, want)
+	}
+
+	// a team whose players have no rate and no minutes shares nothing
+	none := m.share(Side{Team: "Dolphins", Points: 20, Players: []Selection{{Player: "nobody", Position: Reserve}}}, "Storm")
+	if none[0].Tries != 0 {
+		t.Errorf("tries of an unrated reserve = %v, want 0", none[0].Tries)
+	}
+}
+
+func TestFitIgnoresLaterMatches(t *testing.T) {
+	apps := appearances()
+	at := apps[len(apps)-1].Kickoff
+
+	m := Fit(DefaultConfig, apps, at)
+	if got := len(m.recentMinutes["munster"]); got != 3 {
+		t.Errorf("munster has %d appearances before the last match, want 3", got)
+	}
+	// one try in the first three games
+	if got := m.player["munster"]; got.tries != 1 || got.minutes != 240 {
+		t.Errorf("munster = %+v, want 1 try in 240 minutes", got)
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+//go:embed data/venues.json
+var venueData []byte
+
+var ErrUnknownVenue = errors.New("unknown venue")
+
+type Venue struct {
+	ID string `json:"id"`
+	Name string `json:"name"`
+	Aliases []string `json:"aliases"`
+	City string `json:"city"`
+	State string `json:"state"`
+	Country string `json:"country"`
+	Latitude float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Capacity int `json:"capacity"`
+	Surface string `json:"surface"`
+}
+
+// HomeGround is a venue a team plays its home games at over a span of
+// seasons. A team can have more than one at a time.
+type HomeGround struct {
+	Team string `json:"team"`
+	Venue string `json:"venue"`
+	FirstSeason int `json:"firstSeason"`
+	LastSeason *int `json:"lastSeason"`
+}
+
+type VenueData struct {
+	Venues []Venue `json:"venues"`
+	HomeGrounds []HomeGround `json:"homeGrounds"`
+}
+
+// LoadVenueData reads the venue registry bundled with the scraper.
+func LoadVenueData() (VenueData, error) {
+	var data VenueData
+	if err := json.Unmarshal(venueData, &data); err != nil {
+		return data, fmt.Errorf("invalid venue data: %w", err)
+	}
+
+	known := map[string]bool{}
+	for _, v := range data.Venues {
+		if v.ID == "" || v.Name == "" {
+			return data, fmt.Errorf("invalid venue data: venue without an id or name")
+		}
+		known[v.ID] = true
+	}
+
+	for _, h := range data.HomeGrounds {
+		if !known[h.Venue] {
+			return data, fmt.Errorf("invalid venue data: %s has unknown home ground %q", h.Team, h.Venue)
+		}
+	}
+
+	return data, nil
+}
+
+// venueNames lists the names to look a scraped location up by, most specific
+// first. nrl.com sometimes follows the ground with its suburb after a comma.
+func venueNames(location string) []string {
+	names := []string{normaliseName(location)}
+	if name, _, ok := strings.Cut(location, ","); ok {
+		names = append(names, normaliseName(name))
+	}
+
+	return names
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLoadVenueData(t *testing.T) {
+	data, err := LoadVenueData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data.Venues) == 0 || len(data.HomeGrounds) == 0 {
+		t.Fatalf("%d venues and %d home grounds", len(data.Venues), len(data.HomeGrounds))
+	}
+
+	ids := map[string]bool{}
+	aliases := map[string]string{}
+	for _, v := range data.Venues {
+		if ids[v.ID] {
+			t.Errorf("venue %s listed twice", v.ID)
+		}
+		ids[v.ID] = true
+
+		if v.Latitude < -90 || v.Latitude > 90 || v.Longitude < -180 || v.Longitude > 180 || (v.Latitude == 0 && v.Longitude == 0) {
+			t.Errorf("venue %s at %v, %v", v.ID, v.Latitude, v.Longitude)
+		}
+
+		// venue_alias is keyed by the alias, a shared one would link matches
+		// to whichever venue was seeded last
+		for _, name := range append([]string{v.Name}, v.Aliases...) {
+			alias := normaliseName(name)
+			if other, ok := aliases[alias]; ok && other != v.ID {
+				t.Errorf("alias %q names both %s and %s", name, other, v.ID)
+			}
+			aliases[alias] = v.ID
+		}
+	}
+
+	for _, h := range data.HomeGrounds {
+		if h.LastSeason != nil && *h.LastSeason < h.FirstSeason {
+			t.Errorf("%s at %s from %d to %d", h.Team, h.Venue, h.FirstSeason, *h.LastSeason)
+		}
+	}
+}
+
+func TestVenueNames(t *testing.T) {
+	tests := []struct {
+		location string
+		want []string
+	}{
+		{"Suncorp Stadium", []string{"suncorp stadium"}},
+		{"  4 Pines  Park ", []string{"4 pines park"}},
+		{"Accor Stadium, Sydney Olympic Park", []string{"accor stadium, sydney olympic park", "accor stadium"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.location, func(t *testing.T) {
+			if got := venueNames(tt.location); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("venueNames(%q) = %q, want %q", tt.location, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVenueAliasResolution(t *testing.T) {
+	data, err := LoadVenueData()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the aliases as SeedVenues stores them
+	aliases := map[string]string{}
+	for _, v := range data.Venues {
+		for _, name := range append([]string{v.Name}, v.Aliases...) {
+			aliases[normaliseName(name)] = v.ID
+		}
+	}
+
+	tests := []struct {
+		location string
+		want string
+	}{
+		{"Suncorp Stadium", "suncorp-stadium"},
+		{"Lang Park", "suncorp-stadium"},
+		{"ANZ Stadium", "accor-stadium"},
+		{"Accor Stadium, Sydney Olympic Park", "accor-stadium"},
+		{"Brookvale Oval", "4-pines-park"},
+		{"bankwest stadium", "commbank-stadium"},
+		{"Allegiant Stadium", "allegiant-stadium"},
+		{"Somewhere Oval", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.location, func(t *testing.T) {
+			got := ""
+			for _, name := range venueNames(tt.location) {
+				if id, ok := aliases[name]; ok {
+					got = id
+					break
+				}
+			}
+			if got != tt.want {
+				t.Errorf("%q resolves to %q, want %q", tt.location, got, tt.want)
+			}
+		})
+	}
+}